@@ -0,0 +1,288 @@
+package cloudevents
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SpecVersion поддерживаемая версия спецификации CloudEvents
+const SpecVersion = "1.0"
+
+// Content-Type структурированного режима HTTP
+const StructuredContentType = "application/cloudevents+json"
+
+// Префиксы атрибутов в заголовках HTTP (binary mode) и Kafka
+const (
+	httpHeaderPrefix  = "ce-"
+	kafkaHeaderPrefix = "ce_"
+)
+
+// Атрибут расширения partitionkey задает ключ записи Kafka
+const partitionKeyAttribute = "partitionkey"
+
+// ErrInvalidEvent возвращается, если запрос не является корректным CloudEvent
+var ErrInvalidEvent = errors.New("invalid cloudevent")
+
+// Event CloudEvent 1.0
+type Event struct {
+	ID              string
+	Source          string
+	SpecVersion     string
+	Type            string
+	DataContentType string
+	DataSchema      string
+	Subject         string
+	Time            string
+	// Extensions атрибуты расширений в строковом представлении
+	Extensions map[string]string
+	// Data данные события в исходном виде
+	Data []byte
+}
+
+// IsStructured сообщает, передан ли CloudEvent в структурированном режиме
+func IsStructured(r *http.Request) bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mediaType == StructuredContentType
+}
+
+// FromHTTP разбирает CloudEvent из HTTP запроса в структурированном или бинарном режиме
+func FromHTTP(r *http.Request) (*Event, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read request body: %w", err)
+	}
+
+	if IsStructured(r) {
+		return parseStructured(body)
+	}
+	return parseBinary(r.Header, body)
+}
+
+func parseStructured(body []byte) (*Event, error) {
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(body, &attributes); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+
+	event := &Event{Extensions: make(map[string]string)}
+	var dataBase64 string
+	for name, raw := range attributes {
+		if name == "data" {
+			if string(raw) != "null" {
+				event.Data = raw
+			}
+			continue
+		}
+
+		value, err := attributeString(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: attribute %s: %v", ErrInvalidEvent, name, err)
+		}
+		if name == "data_base64" {
+			dataBase64 = value
+			continue
+		}
+		if err := event.setAttribute(name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if dataBase64 != "" {
+		if event.Data != nil {
+			return nil, fmt.Errorf("%w: data and data_base64 are mutually exclusive", ErrInvalidEvent)
+		}
+		data, err := base64.StdEncoding.DecodeString(dataBase64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: data_base64: %v", ErrInvalidEvent, err)
+		}
+		event.Data = data
+	} else if event.Data != nil && !event.IsJSONData() {
+		// Нестроковые данные с не-JSON типом содержимого передаются JSON строкой
+		var text string
+		if err := json.Unmarshal(event.Data, &text); err == nil {
+			event.Data = []byte(text)
+		}
+	}
+
+	return event, event.validate()
+}
+
+func parseBinary(header http.Header, body []byte) (*Event, error) {
+	event := &Event{Extensions: make(map[string]string)}
+
+	for key, values := range header {
+		name := strings.ToLower(key)
+		if !strings.HasPrefix(name, httpHeaderPrefix) || len(values) == 0 {
+			continue
+		}
+
+		value := values[0]
+		if unescaped, err := url.PathUnescape(value); err == nil {
+			value = unescaped
+		}
+		if err := event.setAttribute(strings.TrimPrefix(name, httpHeaderPrefix), value); err != nil {
+			return nil, err
+		}
+	}
+
+	event.DataContentType = header.Get("Content-Type")
+	if len(body) > 0 {
+		event.Data = body
+	}
+
+	return event, event.validate()
+}
+
+func (e *Event) setAttribute(name, value string) error {
+	switch name {
+	case "id":
+		e.ID = value
+	case "source":
+		e.Source = value
+	case "specversion":
+		e.SpecVersion = value
+	case "type":
+		e.Type = value
+	case "datacontenttype":
+		e.DataContentType = value
+	case "dataschema":
+		e.DataSchema = value
+	case "subject":
+		e.Subject = value
+	case "time":
+		e.Time = value
+	default:
+		if !isAttributeName(name) {
+			return fmt.Errorf("%w: invalid attribute name %q", ErrInvalidEvent, name)
+		}
+		e.Extensions[name] = value
+	}
+	return nil
+}
+
+func (e *Event) validate() error {
+	if e.SpecVersion != SpecVersion {
+		return fmt.Errorf("%w: unsupported specversion %q", ErrInvalidEvent, e.SpecVersion)
+	}
+	required := []struct{ name, value string }{{"id", e.ID}, {"source", e.Source}, {"type", e.Type}}
+	for _, attribute := range required {
+		if attribute.value == "" {
+			return fmt.Errorf("%w: required attribute %s is missing", ErrInvalidEvent, attribute.name)
+		}
+	}
+	if e.Time != "" {
+		if _, err := time.Parse(time.RFC3339Nano, e.Time); err != nil {
+			return fmt.Errorf("%w: time must be RFC3339", ErrInvalidEvent)
+		}
+	}
+	return nil
+}
+
+// IsJSONData сообщает, содержит ли событие JSON данные (тип не задан, application/json или +json)
+func (e *Event) IsJSONData() bool {
+	if e.DataContentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(e.DataContentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// DecodedData возвращает разобранные JSON данные события или nil для не-JSON данных
+func (e *Event) DecodedData() (interface{}, error) {
+	if e.Data == nil || !e.IsJSONData() {
+		return nil, nil
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(e.Data))
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("%w: data is not valid JSON: %v", ErrInvalidEvent, err)
+	}
+	return value, nil
+}
+
+// Key возвращает ключ записи Kafka: расширение partitionkey или id события
+func (e *Event) Key() string {
+	if key := e.PartitionKey(); key != "" {
+		return key
+	}
+	return e.ID
+}
+
+// PartitionKey возвращает расширение partitionkey или пустую строку
+func (e *Event) PartitionKey() string {
+	return e.Extensions[partitionKeyAttribute]
+}
+
+// KafkaHeaders возвращает заголовки записи Kafka по протоколу CloudEvents Kafka binding (binary mode)
+func (e *Event) KafkaHeaders() map[string]string {
+	headers := map[string]string{
+		kafkaHeaderPrefix + "id":          e.ID,
+		kafkaHeaderPrefix + "source":      e.Source,
+		kafkaHeaderPrefix + "specversion": e.SpecVersion,
+		kafkaHeaderPrefix + "type":        e.Type,
+	}
+
+	optional := map[string]string{
+		"dataschema": e.DataSchema,
+		"subject":    e.Subject,
+		"time":       e.Time,
+	}
+	for name, value := range optional {
+		if value != "" {
+			headers[kafkaHeaderPrefix+name] = value
+		}
+	}
+	for name, value := range e.Extensions {
+		headers[kafkaHeaderPrefix+name] = value
+	}
+
+	if e.DataContentType != "" {
+		headers["content-type"] = e.DataContentType
+	}
+	return headers
+}
+
+// attributeString приводит значение атрибута структурированного события к строке
+func attributeString(raw json.RawMessage) (string, error) {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "", err
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("unsupported value %s", raw)
+}
+
+// isAttributeName проверяет имя атрибута: строчные латинские буквы и цифры
+func isAttributeName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !((r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,183 @@
+package cloudevents
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+
+	"kafkaGateway/config"
+)
+
+func TestFromHTTPStructured(t *testing.T) {
+	body := `{
+		"specversion": "1.0",
+		"id": "evt-1",
+		"source": "/shop/orders",
+		"type": "com.example.order.created",
+		"time": "2026-01-02T03:04:05Z",
+		"datacontenttype": "application/json",
+		"tenant": "acme",
+		"priority": 5,
+		"data": {"order_id": "o-1"}
+	}`
+	req, _ := http.NewRequest("POST", "/events", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/cloudevents+json; charset=utf-8")
+
+	event, err := FromHTTP(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if event.ID != "evt-1" || event.Type != "com.example.order.created" || event.Source != "/shop/orders" {
+		t.Errorf("Unexpected event attributes: %+v", event)
+	}
+
+	if event.Extensions["tenant"] != "acme" || event.Extensions["priority"] != "5" {
+		t.Errorf("Expected extensions to be parsed, got %v", event.Extensions)
+	}
+
+	if string(event.Data) != `{"order_id": "o-1"}` {
+		t.Errorf("Expected data to be kept as is, got %s", event.Data)
+	}
+
+	headers := event.KafkaHeaders()
+	expected := map[string]string{
+		"ce_id":          "evt-1",
+		"ce_source":      "/shop/orders",
+		"ce_specversion": "1.0",
+		"ce_type":        "com.example.order.created",
+		"ce_time":        "2026-01-02T03:04:05Z",
+		"ce_tenant":      "acme",
+		"ce_priority":    "5",
+		"content-type":   "application/json",
+	}
+	for name, value := range expected {
+		if headers[name] != value {
+			t.Errorf("Expected header %s=%q, got %q", name, value, headers[name])
+		}
+	}
+
+	if event.Key() != "evt-1" {
+		t.Errorf("Expected id to be used as key, got %q", event.Key())
+	}
+}
+
+func TestFromHTTPBinary(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/events", bytes.NewBufferString("plain text payload"))
+	req.Header.Set("Content-Type", "text/plain")
+	req.Header.Set("Ce-Specversion", "1.0")
+	req.Header.Set("Ce-Id", "evt-2")
+	req.Header.Set("Ce-Source", "/shop")
+	req.Header.Set("Ce-Type", "com.example.note")
+	req.Header.Set("Ce-Subject", "caf%C3%A9")
+	req.Header.Set("Ce-Partitionkey", "customer-7")
+
+	event, err := FromHTTP(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if event.Subject != "café" {
+		t.Errorf("Expected percent-encoded subject to be decoded, got %q", event.Subject)
+	}
+
+	if event.Key() != "customer-7" {
+		t.Errorf("Expected partitionkey to be used as key, got %q", event.Key())
+	}
+
+	if string(event.Data) != "plain text payload" || event.DataContentType != "text/plain" {
+		t.Errorf("Unexpected data: %s (%s)", event.Data, event.DataContentType)
+	}
+
+	if value, err := event.DecodedData(); value != nil || err != nil {
+		t.Errorf("Expected non-JSON data not to be decoded, got %v, %v", value, err)
+	}
+}
+
+func TestFromHTTPStructuredBase64(t *testing.T) {
+	body := `{"specversion": "1.0", "id": "1", "source": "s", "type": "t", "datacontenttype": "application/octet-stream", "data_base64": "AAEC"}`
+	req, _ := http.NewRequest("POST", "/events", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", StructuredContentType)
+
+	event, err := FromHTTP(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !bytes.Equal(event.Data, []byte{0, 1, 2}) {
+		t.Errorf("Expected base64 data to be decoded, got %v", event.Data)
+	}
+}
+
+func TestFromHTTPInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		headers map[string]string
+	}{
+		{
+			name:    "missing type",
+			body:    `{"specversion": "1.0", "id": "1", "source": "s"}`,
+			headers: map[string]string{"Content-Type": StructuredContentType},
+		},
+		{
+			name:    "unsupported specversion",
+			body:    `{"specversion": "0.3", "id": "1", "source": "s", "type": "t"}`,
+			headers: map[string]string{"Content-Type": StructuredContentType},
+		},
+		{
+			name:    "invalid time",
+			body:    `{"specversion": "1.0", "id": "1", "source": "s", "type": "t", "time": "yesterday"}`,
+			headers: map[string]string{"Content-Type": StructuredContentType},
+		},
+		{
+			name:    "invalid extension name",
+			body:    `{"specversion": "1.0", "id": "1", "source": "s", "type": "t", "Tenant-ID": "x"}`,
+			headers: map[string]string{"Content-Type": StructuredContentType},
+		},
+		{
+			name:    "binary without headers",
+			body:    `{}`,
+			headers: map[string]string{"Content-Type": "application/json"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/events", bytes.NewBufferString(tt.body))
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			if _, err := FromHTTP(req); !errors.Is(err, ErrInvalidEvent) {
+				t.Errorf("Expected ErrInvalidEvent, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRouter(t *testing.T) {
+	router := NewRouter(config.CloudEventsConfig{
+		DefaultTopic: "events",
+		Routes: []config.CloudEventRoute{
+			{Type: "com.example.order.*", Topic: "orders"},
+			{Type: "com.example.*", Topic: "example"},
+		},
+	})
+
+	tests := map[string]string{
+		"com.example.order.created": "orders",
+		"com.example.user.created":  "example",
+		"org.other.event":           "events",
+	}
+	for eventType, expected := range tests {
+		if topic, ok := router.Route(eventType); !ok || topic != expected {
+			t.Errorf("Expected %s to be routed to %s, got %s", eventType, expected, topic)
+		}
+	}
+
+	if _, ok := NewRouter(config.CloudEventsConfig{}).Route("any"); ok {
+		t.Errorf("Expected no route without default topic")
+	}
+}
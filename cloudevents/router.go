@@ -0,0 +1,30 @@
+package cloudevents
+
+import (
+	"path"
+
+	"kafkaGateway/config"
+)
+
+// Router выбирает топик Kafka по типу события
+type Router struct {
+	routes       []config.CloudEventRoute
+	defaultTopic string
+}
+
+func NewRouter(cfg config.CloudEventsConfig) *Router {
+	return &Router{
+		routes:       cfg.Routes,
+		defaultTopic: cfg.DefaultTopic,
+	}
+}
+
+// Route возвращает топик для типа события: первое подходящее правило или топик по умолчанию
+func (r *Router) Route(eventType string) (string, bool) {
+	for _, route := range r.routes {
+		if matched, _ := path.Match(route.Type, eventType); matched {
+			return route.Topic, true
+		}
+	}
+	return r.defaultTopic, r.defaultTopic != ""
+}
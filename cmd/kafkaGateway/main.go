@@ -2,8 +2,6 @@ package main
 
 import (
 	"context"
-	"fmt"
-	"log"
 	"net/http"
 	"os"
 	"os/signal"
@@ -13,42 +11,158 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.uber.org/zap"
 
+	"kafkaGateway/cloudevents"
 	"kafkaGateway/config"
+	"kafkaGateway/csvimport"
 	"kafkaGateway/handlers"
 	"kafkaGateway/kafka"
+	"kafkaGateway/logger"
 	"kafkaGateway/metrics"
 	"kafkaGateway/middleware"
+	"kafkaGateway/policy"
+	"kafkaGateway/propagation"
+	"kafkaGateway/requestid"
+	"kafkaGateway/routing"
+	"kafkaGateway/schema"
+	"kafkaGateway/tracing"
+	"kafkaGateway/transform"
 )
 
 func main() {
 	// Загружаем конфигурацию
 	cfg := config.LoadConfig()
 	defer cfg.Logger.Sync()
+	// Стандартный log (в том числе ошибки net/http) пишется через zap
+	defer zap.RedirectStdLog(cfg.Logger)()
+
+	// Настраиваем трассировку OpenTelemetry
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Exporter:    cfg.TracingExporter,
+		File:        cfg.TracingFile,
+		ServiceName: cfg.ServiceName,
+		SampleRatio: cfg.TracingSampleRatio,
+	}, cfg.Logger)
+	if err != nil {
+		cfg.Logger.Fatal("Failed to set up tracing", zap.Error(err))
+	}
 
 	// Создаем Kafka Producer
-	kafkaProducer := kafka.NewProducer(cfg.KafkaBrokers, cfg.Logger)
+	kafkaProducer := kafka.NewProducer(cfg.KafkaBrokers, cfg.Loggers.Named(logger.ComponentProducer)).
+		WithMaxMessageBytes(cfg.LargestMessageBytes(cfg.MaxMessageBytes)).
+		WithPartitioners(cfg.Partitioner, cfg.Topics)
 	defer kafkaProducer.Close()
+	if err := metrics.RegisterWriter("producer", kafkaProducer.Stats, kafkaProducer.TopicStats); err != nil {
+		cfg.Logger.Fatal("Failed to register Kafka writer metrics", zap.Error(err))
+	}
 
-	// Создаем обработчик сообщений
-	messageHandler := handlers.NewMessageHandler(kafkaProducer, cfg.Logger)
+	// Создаем административный клиент Kafka
+	kafkaAdmin := kafka.NewAdmin(cfg.KafkaBrokers, cfg.Logger)
+	metrics.SetTopicLister(kafkaAdmin.TopicNames)
+	metrics.SetConsumerGroupSource(kafkaAdmin.DescribeAllConsumerGroups)
+
+	// Создаем политику топиков
+	topicPolicy := policy.NewTopicPolicy(cfg.TopicPolicy, kafkaAdmin, cfg.Logger)
+
+	// Создаем сериализатор значений по схемам топиков
+	schemaRegistry := schema.NewRegistryClient(cfg.SchemaRegistryURL, cfg.SchemaRegistryUsername, cfg.SchemaRegistryPassword)
+	serializer := schema.NewSerializer(cfg.Topics, schemaRegistry, cfg.Logger)
+	if err := serializer.LoadDescriptors(); err != nil {
+		cfg.Logger.Fatal("Failed to load protobuf descriptors", zap.Error(err))
+	}
+
+	// Загружаем локальное хранилище схем топиков
+	schemaStore := schema.NewStore(cfg.SchemaDir, cfg.SchemaCompatibility, cfg.Logger)
+	if err := schemaStore.Load(); err != nil {
+		cfg.Logger.Fatal("Failed to load schemas", zap.Error(err))
+	}
+
+	// Собираем цепочки преобразования сообщений по топикам
+	transformers, err := transform.NewPipelines(cfg.Topics)
+	if err != nil {
+		cfg.Logger.Fatal("Failed to build transform pipelines", zap.Error(err))
+	}
+
+	// Собираем источники ключей сообщений по топикам
+	keyExtractors, err := transform.NewKeyExtractors(cfg.Topics)
+	if err != nil {
+		cfg.Logger.Fatal("Failed to build message key extractors", zap.Error(err))
+	}
+
+	// Собираем пути к времени записи в значениях по топикам
+	timestampExtractors, err := transform.NewTimestampExtractors(cfg.Topics)
+	if err != nil {
+		cfg.Logger.Fatal("Failed to build record timestamp extractors", zap.Error(err))
+	}
+
+	// Создаем маршрутизатор сообщений: псевдонимы топиков и правила из файла конфигурации
+	messageRouter := routing.NewRouter(cfg.Routing, cfg.ConfigPath, cfg.Logger)
+
+	// Создаем перенос заголовков запроса в записи; имена API ключей используются и в журнале доступа
+	headerPropagator := propagation.NewPropagator(cfg.Headers)
+
+	// Создаем обработчики
+	messageHandler := handlers.NewMessageHandler(kafkaProducer, cfg.Logger).
+		WithTopicPolicy(topicPolicy).
+		WithValidator(schemaStore).
+		WithSerializer(serializer).
+		WithCloudEventRouter(cloudevents.NewRouter(cfg.CloudEvents)).
+		WithCSVMappings(csvimport.NewMappings(cfg.Topics)).
+		WithTopics(cfg.Topics).
+		WithMaxMessageBytes(cfg.MaxMessageBytes).
+		WithMessageRouter(messageRouter).
+		WithTransformer(transformers).
+		WithKeyExtractor(keyExtractors).
+		WithTimestampExtractor(timestampExtractors).
+		WithHeaderPropagator(headerPropagator)
+	adminHandler := handlers.NewAdminHandler(kafkaAdmin, cfg.Logger).WithTopicPolicy(topicPolicy)
+	consumerGroupHandler := handlers.NewConsumerGroupHandler(kafkaAdmin, cfg.Logger)
+	schemaHandler := handlers.NewSchemaHandler(schemaStore, cfg.Logger)
+	routingHandler := handlers.NewRoutingHandler(messageRouter, cfg.Logger)
+	logLevelHandler := handlers.NewLogLevelHandler(cfg.Loggers, cfg.Logger)
 
 	// Создаем middleware для аутентификации
-	authMiddleware := middleware.NewAuthMiddleware(cfg.APIKeys, cfg.Logger)
+	authLogger := cfg.Loggers.Named(logger.ComponentAuth)
+	authMiddleware := middleware.NewAuthMiddleware(cfg.APIKeys, authLogger)
+	adminAuthMiddleware := middleware.NewAuthMiddleware(cfg.AdminAPIKeys, authLogger)
+
+	// Создаем middleware для идентификатора запроса
+	requestIDMiddleware := middleware.NewRequestIDMiddleware(cfg.Logger)
+
+	// Создаем журнал доступа
+	accessLogMiddleware := middleware.NewAccessLogMiddleware(cfg.Loggers.Named(logger.ComponentHTTP), cfg.AccessLogSampleRatio, headerPropagator.APIKeyName)
+
+	// Создаем middleware для ограничения размера тела запроса и распаковки сжатых тел
+	bodyLimitMiddleware := middleware.NewBodyLimitMiddleware(cfg.MaxRequestBodySize, cfg.Logger)
+	decompressMiddleware := middleware.NewDecompressMiddleware(cfg.MaxDecompressedBodySize, cfg.Logger)
+
+	// Потоковые загрузки NDJSON и CSV читаются построчно, поэтому для них действует отдельный лимит
+	streamBodyLimitMiddleware := middleware.NewBodyLimitMiddleware(cfg.MaxStreamBodySize, cfg.Logger)
+	streamDecompressMiddleware := middleware.NewDecompressMiddleware(cfg.MaxStreamBodySize, cfg.Logger)
 
 	// Создаем Gin роутер
 	router := gin.New()
 
+	// Идентификатор запроса присваивается первым, чтобы попасть во все логи и ответы
+	router.Use(requestIDMiddleware.Assign)
+
 	// Добавляем CORS middleware
 	configCORS := cors.DefaultConfig()
 	configCORS.AllowAllOrigins = true
 	configCORS.AllowCredentials = true
-	configCORS.AllowHeaders = append(configCORS.AllowHeaders, "Authorization", "Content-Type")
+	configCORS.AllowHeaders = append(configCORS.AllowHeaders, "Authorization", "Content-Type", "Content-Encoding", "traceparent", "tracestate", requestid.Header)
+	configCORS.ExposeHeaders = append(configCORS.ExposeHeaders, requestid.Header)
 	router.Use(cors.New(configCORS))
 
-	// Добавляем логирование запросов
-	router.Use(gin.Logger())
+	// Добавляем журнал доступа; Recovery подключается после него, чтобы паника попала в журнал со статусом 500
+	router.Use(accessLogMiddleware.Log)
 	router.Use(gin.Recovery())
+	// Спаны HTTP запросов; контекст вызывающей стороны берется из traceparent
+	router.Use(otelgin.Middleware(cfg.ServiceName, otelgin.WithFilter(func(r *http.Request) bool {
+		return r.URL.Path != "/health" && r.URL.Path != "/metrics"
+	})))
 
 	// Маршрут для проверки состояния
 	router.GET("/health", func(c *gin.Context) {
@@ -82,7 +196,14 @@ func main() {
 	protected := router.Group("/")
 	protected.Use(authMiddleware.AuthRequired)
 	{
-		protected.POST("/message", messageHandler.SendMessage)
+		messages := protected.Group("/", bodyLimitMiddleware.Limit, decompressMiddleware.Decompress)
+		messages.POST("/message", messageHandler.SendMessage)
+		messages.POST("/events", messageHandler.SendCloudEvent)
+
+		streams := protected.Group("/topics", streamBodyLimitMiddleware.Limit, streamDecompressMiddleware.Decompress)
+		streams.POST("/:topic/ndjson", messageHandler.SendNDJSON)
+		streams.POST("/:topic/csv", messageHandler.SendCSV)
+
 		// Добавим новый маршрут для получения статуса
 		protected.GET("/api/status", func(c *gin.Context) {
 			c.JSON(http.StatusOK, gin.H{
@@ -92,6 +213,41 @@ func main() {
 		})
 	}
 
+	// Административные маршруты регистрируются только при явно заданных ADMIN_API_KEYS,
+	// чтобы клиентские ключи не давали доступа к удалению топиков и сбросу смещений
+	if len(cfg.AdminAPIKeys) > 0 {
+		admin := router.Group("/admin")
+		admin.Use(adminAuthMiddleware.AuthRequired)
+		admin.Use(bodyLimitMiddleware.Limit)
+		{
+			admin.GET("/topics", adminHandler.ListTopics)
+			admin.POST("/topics", adminHandler.CreateTopic)
+			admin.GET("/topics/:topic", adminHandler.DescribeTopic)
+			admin.DELETE("/topics/:topic", adminHandler.DeleteTopic)
+			admin.POST("/topics/:topic/partitions", adminHandler.CreatePartitions)
+			admin.GET("/consumer-groups", consumerGroupHandler.ListConsumerGroups)
+			admin.GET("/consumer-groups/:group", consumerGroupHandler.DescribeConsumerGroup)
+			admin.POST("/consumer-groups/:group/offsets/reset", consumerGroupHandler.ResetOffsets)
+			admin.GET("/schemas", schemaHandler.ListSchemas)
+			admin.GET("/schemas/:topic", schemaHandler.GetSchema)
+			admin.PUT("/schemas/:topic", schemaHandler.PutSchema)
+			admin.DELETE("/schemas/:topic", schemaHandler.DeleteSchema)
+			admin.PUT("/schemas/:topic/config", schemaHandler.Configure)
+			admin.GET("/schemas/:topic/diff", schemaHandler.DiffVersions)
+			admin.GET("/schemas/:topic/versions", schemaHandler.ListVersions)
+			admin.POST("/schemas/:topic/versions", schemaHandler.RegisterVersion)
+			admin.GET("/schemas/:topic/versions/:version", schemaHandler.GetVersion)
+			admin.GET("/routing/rules", routingHandler.ListRules)
+			admin.GET("/routing/aliases", routingHandler.ListAliases)
+			admin.POST("/routing/reload", routingHandler.ReloadRules)
+			admin.GET("/log-levels", logLevelHandler.ListLevels)
+			admin.PUT("/log-levels/:component", logLevelHandler.SetLevel)
+			admin.DELETE("/log-levels/:component", logLevelHandler.ResetLevel)
+		}
+	} else {
+		cfg.Logger.Info("Admin API disabled: ADMIN_API_KEYS is not set")
+	}
+
 	// Создаем HTTP сервер
 	server := &http.Server{
 		Addr:    ":" + cfg.ServerPort,
@@ -100,13 +256,13 @@ func main() {
 
 	// Запускаем сервер в отдельной горутине
 	go func() {
-		log.Printf("Kafka Gateway starting on port %s", cfg.ServerPort)
+		cfg.Logger.Info("Kafka Gateway starting", zap.String("port", cfg.ServerPort))
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server: %v", err)
+			cfg.Logger.Fatal("Failed to start server", zap.Error(err))
 		}
 	}()
 
-	// Запускаем горутину для обновления демонстрационных данных
+	// Запускаем горутину для обновления списка топиков из кластера
 	go func() {
 		ticker := time.NewTicker(5 * time.Second) // Обновляем каждые 5 секунд
 		defer ticker.Stop()
@@ -114,27 +270,55 @@ func main() {
 		for {
 			select {
 			case <-ticker.C:
-				metrics.UpdateDemoData()
+				metrics.RefreshTopics()
 			case <-context.Background().Done():
 				return
 			}
 		}
 	}()
 
+	// Запускаем горутину для обновления метрик отставания групп потребителей
+	go func() {
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := metrics.UpdateConsumerLag(); err != nil {
+				cfg.Logger.Warn("Failed to update consumer group lag", zap.Error(err))
+			}
+		}
+	}()
+
+	// Перечитываем псевдонимы и правила маршрутизации по SIGHUP
+	go func() {
+		reload := make(chan os.Signal, 1)
+		signal.Notify(reload, syscall.SIGHUP)
+		for range reload {
+			if err := messageRouter.Reload(); err != nil {
+				cfg.Logger.Error("Failed to reload routing rules", zap.Error(err))
+			}
+		}
+	}()
+
 	// Ждем сигнал остановки
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Println("Shutting down server...")
+	cfg.Logger.Info("Shutting down server")
 
 	// Плавное завершение
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		cfg.Logger.Fatal("Server forced to shutdown", zap.Error(err))
+	}
+
+	// Отправляем накопленные спаны
+	if err := shutdownTracing(ctx); err != nil {
+		cfg.Logger.Warn("Failed to shut down tracing", zap.Error(err))
 	}
 
-	fmt.Println("Server exited")
+	cfg.Logger.Info("Server exited")
 }
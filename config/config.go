@@ -4,9 +4,14 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"kafkaGateway/logger"
 )
 
 type Config struct {
@@ -14,7 +19,46 @@ type Config struct {
 	KafkaLogLevel int
 	ServerPort    string
 	APIKeys       []string
+	AdminAPIKeys  []string
 	Logger        *zap.Logger
+	// Loggers фабрика логгеров компонентов с общими настройками уровня, формата и вывода
+	Loggers *logger.Factory
+
+	// AccessLogSampleRatio доля успешных запросов в журнале доступа; ошибки пишутся всегда
+	AccessLogSampleRatio float64
+
+	// Реестр схем, совместимый с Confluent Schema Registry
+	SchemaRegistryURL      string
+	SchemaRegistryUsername string
+	SchemaRegistryPassword string
+
+	// Локальное хранилище схем топиков и режим совместимости по умолчанию
+	SchemaDir           string
+	SchemaCompatibility string
+
+	// Ограничения размеров, байт: тело запроса, тело после распаковки Content-Encoding
+	// и запись Kafka для топиков без собственного лимита
+	MaxRequestBodySize      int64
+	MaxDecompressedBodySize int64
+	MaxMessageBytes         int64
+
+	// Ограничение тела потоковых загрузок NDJSON и CSV до и после распаковки, байт; 0 - без ограничения.
+	// Длина строки и размер записи проверяются обработчиками
+	MaxStreamBodySize int64
+
+	// Стратегия выбора партиции для топиков без собственной настройки
+	Partitioner string
+
+	// Трассировка OpenTelemetry: экспортер (none, otlp, stdout, file), файл для file,
+	// имя сервиса и доля трассировок, начатых шлюзом
+	TracingExporter    string
+	TracingFile        string
+	ServiceName        string
+	TracingSampleRatio float64
+
+	// Настройки из YAML файла GATEWAY_CONFIG и путь к нему для перезагрузки
+	FileConfig
+	ConfigPath string
 }
 
 func LoadConfig() *Config {
@@ -35,8 +79,39 @@ func LoadConfig() *Config {
 	// Получаем API ключи (в реальном приложении можно загружать из безопасного хранилища)
 	apiKeys := getEnv("API_KEYS", "default-api-key")
 
-	// Создаем logger
-	logger, err := zap.NewProduction()
+	// Ключи для административных эндпоинтов; без них административный API отключен
+	adminAPIKeys := splitList(getEnv("ADMIN_API_KEYS", ""))
+
+	// Загружаем файл конфигурации шлюза
+	configPath := getEnv("GATEWAY_CONFIG", "")
+	fileConfig, err := LoadFileConfig(configPath)
+	if err != nil {
+		log.Fatalf("Failed to load gateway config: %v", err)
+	}
+
+	partitioner := getEnv("KAFKA_PARTITIONER", PartitionerHash)
+	if !IsPartitioner(partitioner) {
+		log.Fatalf("Invalid KAFKA_PARTITIONER %q", partitioner)
+	}
+
+	tracingExporter := getEnv("TRACING_EXPORTER", "none")
+	switch tracingExporter {
+	case "none", "otlp", "stdout":
+	case "file":
+		if os.Getenv("TRACING_FILE") == "" {
+			log.Fatalf("TRACING_FILE is required when TRACING_EXPORTER=file")
+		}
+	default:
+		log.Fatalf("Invalid TRACING_EXPORTER %q", tracingExporter)
+	}
+
+	schemaRegistryURL := getEnv("SCHEMA_REGISTRY_URL", "")
+	if fileConfig.UsesSchemaRegistry() && schemaRegistryURL == "" {
+		log.Fatalf("SCHEMA_REGISTRY_URL is required when topics are bound to Avro subjects")
+	}
+
+	// Создаем фабрику логгеров
+	loggers, err := logger.NewFactory(loadLogOptions())
 	if err != nil {
 		log.Fatalf("Failed to create logger: %v", err)
 	}
@@ -46,13 +121,129 @@ func LoadConfig() *Config {
 		KafkaLogLevel: kafkaLogLevel,
 		ServerPort:    serverPort,
 		APIKeys:       []string{apiKeys}, // В реальном приложении можно разделить по запятой
-		Logger:        logger,
+		AdminAPIKeys:  adminAPIKeys,
+		Logger:        loggers.Logger(),
+		Loggers:       loggers,
+		FileConfig:    *fileConfig,
+		ConfigPath:    configPath,
+
+		AccessLogSampleRatio: getEnvRatio("ACCESS_LOG_SAMPLE_RATIO", 1),
+
+		SchemaRegistryURL:      schemaRegistryURL,
+		SchemaRegistryUsername: getEnv("SCHEMA_REGISTRY_USERNAME", ""),
+		SchemaRegistryPassword: getEnv("SCHEMA_REGISTRY_PASSWORD", ""),
+		SchemaDir:              getEnv("SCHEMA_DIR", getEnv("JSON_SCHEMA_DIR", "")),
+		SchemaCompatibility:    getEnv("SCHEMA_COMPATIBILITY", "BACKWARD"),
+
+		MaxRequestBodySize:      getEnvSize("MAX_REQUEST_BODY_SIZE", 16<<20),
+		MaxDecompressedBodySize: getEnvSize("MAX_DECOMPRESSED_BODY_SIZE", 64<<20),
+		MaxMessageBytes:         getEnvSize("MAX_MESSAGE_BYTES", 1<<20),
+		MaxStreamBodySize:       getEnvLimit("MAX_STREAM_BODY_SIZE", 0),
+
+		Partitioner: partitioner,
+
+		TracingExporter:    tracingExporter,
+		TracingFile:        getEnv("TRACING_FILE", ""),
+		ServiceName:        getEnv("OTEL_SERVICE_NAME", "kafka-gateway"),
+		TracingSampleRatio: getEnvRatio("TRACING_SAMPLE_RATIO", 1),
 	}
 }
 
+// loadLogOptions читает настройки логгеров. Без LOG_LEVEL используется явно заданный KAFKA_LOG_LEVEL
+func loadLogOptions() logger.Options {
+	opts := logger.DefaultOptions()
+
+	if level := os.Getenv("LOG_LEVEL"); level != "" {
+		parsed, err := zapcore.ParseLevel(level)
+		if err != nil {
+			log.Fatalf("Invalid LOG_LEVEL %q", level)
+		}
+		opts.Level = parsed
+	} else if level := os.Getenv("KAFKA_LOG_LEVEL"); level != "" {
+		var numeric int
+		fmt.Sscanf(level, "%d", &numeric)
+		opts.Level = logger.LevelFromInt(numeric)
+	}
+
+	opts.Encoding = getEnv("LOG_ENCODING", opts.Encoding)
+	if output := splitList(getEnv("LOG_OUTPUT", "")); len(output) > 0 {
+		opts.OutputPaths = output
+	}
+	opts.SamplingInitial = getEnvCount("LOG_SAMPLING_INITIAL", opts.SamplingInitial)
+	opts.SamplingThereafter = getEnvCount("LOG_SAMPLING_THEREAFTER", opts.SamplingThereafter)
+	return opts
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+// getEnvSize читает размер в байтах; некорректное значение останавливает запуск
+func getEnvSize(key string, defaultValue int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size <= 0 {
+		log.Fatalf("Invalid %s: must be a positive number of bytes", key)
+	}
+	return size
+}
+
+// getEnvLimit читает размер в байтах, 0 отключает ограничение; некорректное значение останавливает запуск
+func getEnvLimit(key string, defaultValue int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size < 0 {
+		log.Fatalf("Invalid %s: must be a non-negative number of bytes", key)
+	}
+	return size
+}
+
+// getEnvCount читает неотрицательное целое; некорректное значение останавливает запуск
+func getEnvCount(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		log.Fatalf("Invalid %s: must be a non-negative integer", key)
+	}
+	return count
+}
+
+// getEnvRatio читает долю от 0 до 1; некорректное значение останавливает запуск
+func getEnvRatio(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	ratio, err := strconv.ParseFloat(value, 64)
+	if err != nil || ratio < 0 || ratio > 1 {
+		log.Fatalf("Invalid %s: must be a number from 0 to 1", key)
+	}
+	return ratio
+}
+
+// splitList разбивает строку по запятым, отбрасывая пустые элементы
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
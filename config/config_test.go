@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"testing"
+
+	"go.uber.org/zap/zapcore"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -55,6 +57,7 @@ func TestLoadConfigWithDefaults(t *testing.T) {
 	os.Unsetenv("KAFKA_LOG_LEVEL")
 	os.Unsetenv("SERVER_PORT")
 	os.Unsetenv("API_KEYS")
+	os.Unsetenv("ADMIN_API_KEYS")
 
 	config := LoadConfig()
 
@@ -74,4 +77,48 @@ func TestLoadConfigWithDefaults(t *testing.T) {
 	if len(config.APIKeys) != 1 || config.APIKeys[0] != "default-api-key" {
 		t.Errorf("Expected default APIKeys=[default-api-key], got %v", config.APIKeys)
 	}
+
+	if len(config.AdminAPIKeys) != 0 {
+		t.Errorf("Expected no default AdminAPIKeys, got %v", config.AdminAPIKeys)
+	}
+}
+
+func TestLoadConfigAdminAPIKeys(t *testing.T) {
+	os.Setenv("ADMIN_API_KEYS", "admin-1, admin-2,")
+	defer os.Unsetenv("ADMIN_API_KEYS")
+
+	config := LoadConfig()
+
+	if len(config.AdminAPIKeys) != 2 || config.AdminAPIKeys[0] != "admin-1" || config.AdminAPIKeys[1] != "admin-2" {
+		t.Errorf("Expected AdminAPIKeys=[admin-1 admin-2], got %v", config.AdminAPIKeys)
+	}
+}
+
+func TestLoadConfigLogging(t *testing.T) {
+	os.Setenv("LOG_LEVEL", "debug")
+	os.Setenv("LOG_ENCODING", "console")
+	os.Setenv("LOG_SAMPLING_INITIAL", "0")
+	os.Setenv("ACCESS_LOG_SAMPLE_RATIO", "0.25")
+	defer func() {
+		os.Unsetenv("LOG_LEVEL")
+		os.Unsetenv("LOG_ENCODING")
+		os.Unsetenv("LOG_SAMPLING_INITIAL")
+		os.Unsetenv("ACCESS_LOG_SAMPLE_RATIO")
+	}()
+
+	opts := loadLogOptions()
+	if opts.Level != zapcore.DebugLevel || opts.Encoding != "console" || opts.SamplingInitial != 0 {
+		t.Errorf("Expected debug console logging without sampling, got %+v", opts)
+	}
+
+	config := LoadConfig()
+	if config.Loggers == nil || config.Logger == nil {
+		t.Fatalf("Expected loggers to be initialized")
+	}
+	if !config.Logger.Core().Enabled(zapcore.DebugLevel) {
+		t.Errorf("Expected debug level to be enabled")
+	}
+	if config.AccessLogSampleRatio != 0.25 {
+		t.Errorf("Expected AccessLogSampleRatio=0.25, got %v", config.AccessLogSampleRatio)
+	}
 }
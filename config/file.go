@@ -0,0 +1,511 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileConfig структура YAML файла конфигурации шлюза (GATEWAY_CONFIG)
+type FileConfig struct {
+	TopicPolicy TopicPolicyConfig      `yaml:"topic_policy"`
+	Topics      map[string]TopicConfig `yaml:"topics"`
+	CloudEvents CloudEventsConfig      `yaml:"cloudevents"`
+	Routing     RoutingConfig          `yaml:"routing"`
+	Headers     HeadersConfig          `yaml:"headers"`
+}
+
+// HeadersConfig перенос заголовков HTTP запроса и метаданных шлюза в заголовки записей Kafka
+type HeadersConfig struct {
+	// Forward заголовки HTTP запроса, которые копируются в записи
+	Forward  []HeaderForward `yaml:"forward"`
+	Metadata HeaderMetadata  `yaml:"metadata"`
+}
+
+// HeaderForward правило переноса заголовка HTTP запроса
+type HeaderForward struct {
+	// Header имя или шаблон имени заголовка без учета регистра (X-Tenant-*)
+	Header string `yaml:"header"`
+	// As имя заголовка записи; * заменяется частью имени, совпавшей с * в Header.
+	// По умолчанию - имя заголовка запроса в нижнем регистре
+	As string `yaml:"as"`
+}
+
+// HeaderMetadata заголовки метаданных шлюза, добавляемые ко всем записям
+type HeaderMetadata struct {
+	// Disabled отключает заголовки метаданных
+	Disabled bool `yaml:"disabled"`
+	// Prefix префикс имен заголовков, по умолчанию "gateway-"
+	Prefix string `yaml:"prefix"`
+	// Instance имя экземпляра шлюза, по умолчанию имя хоста; поддерживает переменные окружения
+	Instance string `yaml:"instance"`
+	// APIKeyNames имена API ключей по отпечатку sha256:<первые 16 hex символов SHA-256 ключа>
+	APIKeyNames map[string]string `yaml:"api_key_names"`
+}
+
+// TopicConfig настройки обработки сообщений конкретного топика
+type TopicConfig struct {
+	// Avro привязывает топик к субъекту реестра схем
+	Avro *AvroConfig `yaml:"avro"`
+	// Protobuf кодирует значения топика в Protobuf по описанию сообщения
+	Protobuf *ProtobufConfig `yaml:"protobuf"`
+	// CSV правила преобразования строк CSV при загрузке в топик
+	CSV *CSVConfig `yaml:"csv"`
+	// BinaryValue обработка значений из тел MessagePack и CBOR: transcode (по умолчанию) или forward
+	BinaryValue string `yaml:"binary_value"`
+	// MaxMessageBytes максимальный размер записи (ключ, значение и заголовки), по умолчанию MAX_MESSAGE_BYTES
+	MaxMessageBytes int64 `yaml:"max_message_bytes"`
+	// Transform шаги преобразования значения перед проверкой по схеме и отправкой
+	Transform []TransformStep `yaml:"transform"`
+	// Key извлечение ключа из значения, если ключ не передан в запросе
+	Key *KeyConfig `yaml:"key"`
+	// Partitioner стратегия выбора партиции, по умолчанию KAFKA_PARTITIONER
+	Partitioner string `yaml:"partitioner"`
+	// TimestampPath путь к времени записи в значении ($.occurred_at), если время не передано в запросе
+	TimestampPath string `yaml:"timestamp_path"`
+}
+
+// Стратегии выбора партиции
+const (
+	// PartitionerHash FNV-1a по ключу (стратегия kafka-go по умолчанию)
+	PartitionerHash = "hash"
+	// PartitionerMurmur2 murmur2 по ключу, совместима с партиционером Java клиента
+	PartitionerMurmur2 = "murmur2"
+	// PartitionerCRC32 CRC32 по ключу, совместима с партиционером consistent_random librdkafka
+	PartitionerCRC32      = "crc32"
+	PartitionerRoundRobin = "round_robin"
+	PartitionerLeastBytes = "least_bytes"
+	// PartitionerExplicit партиция обязательно передается в запросе
+	PartitionerExplicit = "explicit"
+)
+
+// IsPartitioner сообщает, поддерживается ли стратегия выбора партиции
+func IsPartitioner(name string) bool {
+	switch name {
+	case PartitionerHash, PartitionerMurmur2, PartitionerCRC32, PartitionerRoundRobin, PartitionerLeastBytes, PartitionerExplicit:
+		return true
+	}
+	return false
+}
+
+// KeyConfig источник ключа сообщения в значении; задается ровно одно из полей Path и Template
+type KeyConfig struct {
+	// Path путь к полю значения: $.user.id, $.items[0].sku
+	Path string `yaml:"path"`
+	// Template шаблон text/template по значению: "{{.tenant}}:{{.user.id}}"
+	Template string `yaml:"template"`
+	// Required отклонять сообщения, для которых ключ не удалось получить
+	Required bool `yaml:"required"`
+}
+
+// TransformStep шаг преобразования значения сообщения; в шаге задается ровно одно действие.
+// Поля указываются путем через точку (customer.address.city)
+type TransformStep struct {
+	// Add задает значения полей, перезаписывая существующие
+	Add map[string]interface{} `yaml:"add"`
+	// Defaults задает значения отсутствующих полей
+	Defaults map[string]interface{} `yaml:"defaults"`
+	// Drop удаляет поля
+	Drop []string `yaml:"drop"`
+	// Rename переименовывает поля: старый путь -> новый путь
+	Rename map[string]string `yaml:"rename"`
+	// Cast приводит поля к типам string, int, float или bool
+	Cast map[string]string `yaml:"cast"`
+	// Flatten разворачивает вложенные объекты в поля верхнего уровня
+	Flatten *FlattenStep `yaml:"flatten"`
+	// Template задает поля по шаблонам text/template, данные шаблона - значение сообщения
+	Template map[string]string `yaml:"template"`
+	// Custom имя шага, зарегистрированного через transform.Register, с параметрами Options
+	Custom  string                 `yaml:"custom"`
+	Options map[string]interface{} `yaml:"options"`
+}
+
+// FlattenStep разворачивание вложенных объектов
+type FlattenStep struct {
+	// Field объект, который нужно развернуть; по умолчанию все значение
+	Field string `yaml:"field"`
+	// Separator разделитель имен полей, по умолчанию "."
+	Separator string `yaml:"separator"`
+}
+
+// Обработка значений сообщений, переданных в MessagePack или CBOR
+const (
+	// BinaryValueTranscode перекодирует значение в JSON
+	BinaryValueTranscode = "transcode"
+	// BinaryValueForward отправляет значение в исходной кодировке
+	BinaryValueForward = "forward"
+)
+
+// AvroConfig привязка топика к субъекту Confluent Schema Registry
+type AvroConfig struct {
+	// Subject субъект реестра, по умолчанию "<topic>-value"
+	Subject string `yaml:"subject"`
+	// Version версия схемы: номер или "latest"
+	Version string `yaml:"version"`
+}
+
+// ProtobufConfig привязка топика к типу сообщения из скомпилированного FileDescriptorSet
+type ProtobufConfig struct {
+	// DescriptorSet путь к файлу, созданному protoc --include_imports --descriptor_set_out
+	DescriptorSet string `yaml:"descriptor_set"`
+	// Message полное имя типа сообщения, например "shop.v1.Order"
+	Message string `yaml:"message"`
+}
+
+// Типы значений колонок CSV
+const (
+	CSVTypeString = "string"
+	CSVTypeInt    = "int"
+	CSVTypeFloat  = "float"
+	CSVTypeBool   = "bool"
+	CSVTypeJSON   = "json"
+)
+
+// CSVConfig преобразование строк CSV в JSON объекты
+type CSVConfig struct {
+	// Delimiter разделитель колонок, по умолчанию ","
+	Delimiter string `yaml:"delimiter"`
+	// KeyColumn колонка, значение которой становится ключом записи
+	KeyColumn string `yaml:"key_column"`
+	// Fields переименование колонок в поля объекта: колонка -> поле
+	Fields map[string]string `yaml:"fields"`
+	// Types приведение значений колонок к типам string, int, float, bool или json
+	Types map[string]string `yaml:"types"`
+	// SkipColumns колонки, которые не попадают в объект
+	SkipColumns []string `yaml:"skip_columns"`
+}
+
+// CloudEventsConfig маршрутизация CloudEvents в топики по типу события
+type CloudEventsConfig struct {
+	// DefaultTopic топик для событий, не подходящих ни под одно правило
+	DefaultTopic string            `yaml:"default_topic"`
+	Routes       []CloudEventRoute `yaml:"routes"`
+}
+
+// CloudEventRoute правило маршрутизации CloudEvents
+type CloudEventRoute struct {
+	// Type шаблон типа события в формате path.Match, например "com.example.order.*"
+	Type  string `yaml:"type"`
+	Topic string `yaml:"topic"`
+}
+
+// RoutingConfig логические имена топиков и правила выбора топиков по содержимому сообщений POST /message
+type RoutingConfig struct {
+	// Prefix добавляется к физическим топикам псевдонимов, поддерживает переменные окружения (${DEPLOY_ENV}.)
+	Prefix string `yaml:"prefix" json:"prefix,omitempty"`
+	// Aliases логические имена топиков: псевдоним -> физический топик без префикса
+	Aliases map[string]string `yaml:"aliases" json:"aliases,omitempty"`
+	Rules   []RoutingRule     `yaml:"rules" json:"rules"`
+}
+
+// RoutingRule правило маршрутизации: при совпадении всех условий match сообщение
+// отправляется во все топики правила
+type RoutingRule struct {
+	Name   string       `yaml:"name" json:"name,omitempty"`
+	Match  RoutingMatch `yaml:"match" json:"match"`
+	Topics []string     `yaml:"topics" json:"topics"`
+	// Continue продолжает проверку следующих правил после совпадения
+	Continue bool `yaml:"continue" json:"continue,omitempty"`
+}
+
+// RoutingMatch условия правила маршрутизации. Пустое условие совпадает с любым сообщением
+type RoutingMatch struct {
+	// Topic шаблон топика из запроса в формате path.Match
+	Topic string `yaml:"topic" json:"topic,omitempty"`
+	// Key шаблон ключа сообщения в формате path.Match
+	Key string `yaml:"key" json:"key,omitempty"`
+	// Headers шаблоны значений заголовков сообщения
+	Headers map[string]string `yaml:"headers" json:"headers,omitempty"`
+	// Value ожидаемые значения полей JSON значения по пути через точку (event_type, customer.country).
+	// Список означает любое из значений
+	Value map[string]interface{} `yaml:"value" json:"value,omitempty"`
+}
+
+// Действия политики топиков
+const (
+	// TopicActionExisting разрешает отправку только в уже существующие топики
+	TopicActionExisting = "existing"
+	// TopicActionCreate создает отсутствующий топик по шаблону правила
+	TopicActionCreate = "create"
+	// TopicActionDeny запрещает отправку в топик
+	TopicActionDeny = "deny"
+)
+
+// TopicPolicyConfig политика отправки в топики и их автосоздания
+type TopicPolicyConfig struct {
+	// DefaultAction применяется к топикам, не подходящим ни под одно правило
+	DefaultAction string      `yaml:"default_action"`
+	Rules         []TopicRule `yaml:"rules"`
+}
+
+// TopicRule правило политики для топиков, подходящих под шаблон
+type TopicRule struct {
+	// Pattern шаблон имени топика в формате path.Match, например "orders.*"
+	Pattern           string            `yaml:"pattern"`
+	Action            string            `yaml:"action"`
+	Partitions        int               `yaml:"partitions"`
+	ReplicationFactor int               `yaml:"replication_factor"`
+	Retention         time.Duration     `yaml:"retention"`
+	Configs           map[string]string `yaml:"configs"`
+}
+
+// LoadFileConfig читает YAML файл конфигурации. Пустой путь означает конфигурацию по умолчанию
+func LoadFileConfig(path string) (*FileConfig, error) {
+	fileConfig := &FileConfig{}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, fileConfig); err != nil {
+			return nil, fmt.Errorf("parse config file %s: %w", path, err)
+		}
+	}
+
+	if err := fileConfig.TopicPolicy.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := fileConfig.CloudEvents.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := fileConfig.Routing.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := fileConfig.Headers.validate(); err != nil {
+		return nil, err
+	}
+
+	for topic, topicConfig := range fileConfig.Topics {
+		if topicConfig.Avro != nil && topicConfig.Protobuf != nil {
+			return nil, fmt.Errorf("topics: topic %q can not use both avro and protobuf", topic)
+		}
+		if pb := topicConfig.Protobuf; pb != nil && (pb.DescriptorSet == "" || pb.Message == "") {
+			return nil, fmt.Errorf("topics: topic %q must set protobuf descriptor_set and message", topic)
+		}
+		if topicConfig.MaxMessageBytes < 0 {
+			return nil, fmt.Errorf("topics: topic %q has negative max_message_bytes", topic)
+		}
+		switch topicConfig.BinaryValue {
+		case "", BinaryValueTranscode:
+		case BinaryValueForward:
+			if topicConfig.Avro != nil || topicConfig.Protobuf != nil {
+				return nil, fmt.Errorf("topics: topic %q can not forward binary values with avro or protobuf", topic)
+			}
+		default:
+			return nil, fmt.Errorf("topics: topic %q has invalid binary_value %q", topic, topicConfig.BinaryValue)
+		}
+		if topicConfig.Partitioner != "" && !IsPartitioner(topicConfig.Partitioner) {
+			return nil, fmt.Errorf("topics: topic %q has invalid partitioner %q", topic, topicConfig.Partitioner)
+		}
+		if topicConfig.TimestampPath != "" && !isJSONPath(topicConfig.TimestampPath) {
+			return nil, fmt.Errorf("topics: topic %q: timestamp_path %q must start with $", topic, topicConfig.TimestampPath)
+		}
+		if topicConfig.Key != nil {
+			if err := topicConfig.Key.validate(); err != nil {
+				return nil, fmt.Errorf("topics: topic %q: key: %w", topic, err)
+			}
+		}
+		for i, step := range topicConfig.Transform {
+			if err := step.validate(); err != nil {
+				return nil, fmt.Errorf("topics: topic %q: transform step %d: %w", topic, i+1, err)
+			}
+		}
+		if topicConfig.CSV != nil {
+			if err := topicConfig.CSV.validate(); err != nil {
+				return nil, fmt.Errorf("topics: topic %q: %w", topic, err)
+			}
+		}
+		if topicConfig.Avro != nil {
+			if topicConfig.Avro.Subject == "" {
+				topicConfig.Avro.Subject = topic + "-value"
+			}
+			if topicConfig.Avro.Version == "" {
+				topicConfig.Avro.Version = "latest"
+			}
+		}
+	}
+
+	return fileConfig, nil
+}
+
+func (tp *TopicPolicyConfig) validate() error {
+	if tp.DefaultAction == "" {
+		tp.DefaultAction = TopicActionExisting
+	}
+	if !isTopicAction(tp.DefaultAction) || tp.DefaultAction == TopicActionCreate {
+		return fmt.Errorf("topic_policy: invalid default_action %q", tp.DefaultAction)
+	}
+
+	for i, rule := range tp.Rules {
+		if rule.Pattern == "" {
+			return fmt.Errorf("topic_policy: rule %d has empty pattern", i)
+		}
+		if !isTopicAction(rule.Action) {
+			return fmt.Errorf("topic_policy: rule %q has invalid action %q", rule.Pattern, rule.Action)
+		}
+		if rule.Action == TopicActionCreate && (rule.Partitions < 1 || rule.ReplicationFactor < 1) {
+			return fmt.Errorf("topic_policy: rule %q must set partitions and replication_factor", rule.Pattern)
+		}
+	}
+
+	return nil
+}
+
+func (ce *CloudEventsConfig) validate() error {
+	for i, route := range ce.Routes {
+		if route.Type == "" || route.Topic == "" {
+			return fmt.Errorf("cloudevents: route %d must set type and topic", i)
+		}
+		if _, err := path.Match(route.Type, ""); err != nil {
+			return fmt.Errorf("cloudevents: route %q: %w", route.Type, err)
+		}
+	}
+	return nil
+}
+
+func (hc *HeadersConfig) validate() error {
+	if hc.Metadata.Prefix == "" {
+		hc.Metadata.Prefix = "gateway-"
+	}
+	hc.Metadata.Instance = os.ExpandEnv(hc.Metadata.Instance)
+
+	for _, forward := range hc.Forward {
+		if forward.Header == "" {
+			return fmt.Errorf("headers: forward rule must set header")
+		}
+		if _, err := path.Match(forward.Header, ""); err != nil {
+			return fmt.Errorf("headers: pattern %q: %w", forward.Header, err)
+		}
+		if strings.Contains(forward.As, "*") && (strings.Count(forward.Header, "*") != 1 || strings.ContainsAny(forward.Header, "?[\\")) {
+			return fmt.Errorf("headers: %q can use * in as only with a single * in header", forward.Header)
+		}
+	}
+	return nil
+}
+
+func (rc *RoutingConfig) validate() error {
+	rc.Prefix = os.ExpandEnv(rc.Prefix)
+	for alias, topic := range rc.Aliases {
+		if alias == "" || topic == "" {
+			return fmt.Errorf("routing: alias %q must map to a topic", alias)
+		}
+	}
+
+	for i, rule := range rc.Rules {
+		name := rule.Name
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+		if len(rule.Topics) == 0 {
+			return fmt.Errorf("routing: rule %s must set topics", name)
+		}
+		patterns := []string{rule.Match.Topic, rule.Match.Key}
+		for _, pattern := range rule.Match.Headers {
+			patterns = append(patterns, pattern)
+		}
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("routing: rule %s: pattern %q: %w", name, pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (kc *KeyConfig) validate() error {
+	if (kc.Path == "") == (kc.Template == "") {
+		return fmt.Errorf("must set exactly one of path and template")
+	}
+	if kc.Path != "" && !isJSONPath(kc.Path) {
+		return fmt.Errorf("path %q must start with $", kc.Path)
+	}
+	return nil
+}
+
+func isJSONPath(path string) bool {
+	return path == "$" || strings.HasPrefix(path, "$.") || strings.HasPrefix(path, "$[")
+}
+
+func (ts *TransformStep) validate() error {
+	actions := 0
+	for _, set := range []bool{
+		ts.Add != nil, ts.Defaults != nil, ts.Drop != nil, ts.Rename != nil,
+		ts.Cast != nil, ts.Flatten != nil, ts.Template != nil, ts.Custom != "",
+	} {
+		if set {
+			actions++
+		}
+	}
+	if actions != 1 {
+		return fmt.Errorf("must set exactly one action, got %d", actions)
+	}
+
+	for field, castType := range ts.Cast {
+		switch castType {
+		case CSVTypeString, CSVTypeInt, CSVTypeFloat, CSVTypeBool:
+		default:
+			return fmt.Errorf("cast: field %q has invalid type %q", field, castType)
+		}
+	}
+	return nil
+}
+
+func (cc *CSVConfig) validate() error {
+	if utf8.RuneCountInString(cc.Delimiter) > 1 || cc.Delimiter == "\"" || cc.Delimiter == "\n" || cc.Delimiter == "\r" {
+		return fmt.Errorf("csv: invalid delimiter %q", cc.Delimiter)
+	}
+	for column, columnType := range cc.Types {
+		if !IsCSVType(columnType) {
+			return fmt.Errorf("csv: column %q has invalid type %q", column, columnType)
+		}
+	}
+	return nil
+}
+
+// IsCSVType сообщает, поддерживается ли тип колонки CSV
+func IsCSVType(columnType string) bool {
+	switch columnType {
+	case CSVTypeString, CSVTypeInt, CSVTypeFloat, CSVTypeBool, CSVTypeJSON:
+		return true
+	}
+	return false
+}
+
+func isTopicAction(action string) bool {
+	switch action {
+	case TopicActionExisting, TopicActionCreate, TopicActionDeny:
+		return true
+	}
+	return false
+}
+
+// LargestMessageBytes возвращает наибольший из лимитов размера записи топиков и лимита по умолчанию
+func (fc *FileConfig) LargestMessageBytes(defaultLimit int64) int64 {
+	limit := defaultLimit
+	for _, topicConfig := range fc.Topics {
+		if topicConfig.MaxMessageBytes > limit {
+			limit = topicConfig.MaxMessageBytes
+		}
+	}
+	return limit
+}
+
+// UsesSchemaRegistry сообщает, привязан ли хотя бы один топик к реестру схем
+func (fc *FileConfig) UsesSchemaRegistry() bool {
+	for _, topicConfig := range fc.Topics {
+		if topicConfig.Avro != nil {
+			return true
+		}
+	}
+	return false
+}
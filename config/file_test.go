@@ -0,0 +1,411 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "gateway.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoadFileConfigDefaults(t *testing.T) {
+	fileConfig, err := LoadFileConfig("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if fileConfig.TopicPolicy.DefaultAction != TopicActionExisting {
+		t.Errorf("Expected default action %q, got %q", TopicActionExisting, fileConfig.TopicPolicy.DefaultAction)
+	}
+}
+
+func TestLoadFileConfigTopicPolicy(t *testing.T) {
+	path := writeConfigFile(t, `
+topic_policy:
+  default_action: deny
+  rules:
+    - pattern: "orders.*"
+      action: existing
+    - pattern: "events.*"
+      action: create
+      partitions: 6
+      replication_factor: 3
+      retention: 168h
+      configs:
+        cleanup.policy: delete
+`)
+
+	fileConfig, err := LoadFileConfig(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	policy := fileConfig.TopicPolicy
+	if policy.DefaultAction != TopicActionDeny {
+		t.Errorf("Expected default action deny, got %q", policy.DefaultAction)
+	}
+
+	if len(policy.Rules) != 2 {
+		t.Fatalf("Expected 2 rules, got %d", len(policy.Rules))
+	}
+
+	rule := policy.Rules[1]
+	if rule.Partitions != 6 || rule.ReplicationFactor != 3 || rule.Retention != 168*time.Hour {
+		t.Errorf("Unexpected rule template: %+v", rule)
+	}
+
+	if rule.Configs["cleanup.policy"] != "delete" {
+		t.Errorf("Expected cleanup.policy=delete, got %v", rule.Configs)
+	}
+}
+
+func TestLoadFileConfigTopicAvro(t *testing.T) {
+	path := writeConfigFile(t, `
+topics:
+  orders:
+    avro: {}
+  payments:
+    avro:
+      subject: payments-v2
+      version: "3"
+  logs: {}
+`)
+
+	fileConfig, err := LoadFileConfig(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if avro := fileConfig.Topics["orders"].Avro; avro == nil || avro.Subject != "orders-value" || avro.Version != "latest" {
+		t.Errorf("Expected default subject and version for orders, got %+v", avro)
+	}
+
+	if avro := fileConfig.Topics["payments"].Avro; avro == nil || avro.Subject != "payments-v2" || avro.Version != "3" {
+		t.Errorf("Expected explicit subject and version for payments, got %+v", avro)
+	}
+
+	if fileConfig.Topics["logs"].Avro != nil {
+		t.Errorf("Expected logs topic without avro binding")
+	}
+
+	if !fileConfig.UsesSchemaRegistry() {
+		t.Errorf("Expected config to use schema registry")
+	}
+}
+
+func TestLoadFileConfigInvalidTopicPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "unknown action",
+			content: "topic_policy:\n  rules:\n    - pattern: \"a.*\"\n      action: maybe\n",
+		},
+		{
+			name:    "create without template",
+			content: "topic_policy:\n  rules:\n    - pattern: \"a.*\"\n      action: create\n",
+		},
+		{
+			name:    "create as default action",
+			content: "topic_policy:\n  default_action: create\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadFileConfig(writeConfigFile(t, tt.content)); err == nil {
+				t.Errorf("Expected error for invalid topic policy")
+			}
+		})
+	}
+}
+
+func TestLoadFileConfigInvalidTopics(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "avro and protobuf",
+			content: "topics:\n  orders:\n    avro: {}\n    protobuf:\n      descriptor_set: orders.pb\n      message: shop.v1.Order\n",
+		},
+		{
+			name:    "protobuf without message",
+			content: "topics:\n  orders:\n    protobuf:\n      descriptor_set: orders.pb\n",
+		},
+		{
+			name:    "invalid binary value",
+			content: "topics:\n  orders:\n    binary_value: keep\n",
+		},
+		{
+			name:    "forward binary value with avro",
+			content: "topics:\n  orders:\n    avro: {}\n    binary_value: forward\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadFileConfig(writeConfigFile(t, tt.content)); err == nil {
+				t.Errorf("Expected error for invalid topic config")
+			}
+		})
+	}
+}
+
+func TestLoadFileConfigCloudEvents(t *testing.T) {
+	path := writeConfigFile(t, `
+cloudevents:
+  default_topic: events
+  routes:
+    - type: "com.example.order.*"
+      topic: orders
+`)
+
+	fileConfig, err := LoadFileConfig(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if fileConfig.CloudEvents.DefaultTopic != "events" || len(fileConfig.CloudEvents.Routes) != 1 {
+		t.Errorf("Unexpected cloudevents config: %+v", fileConfig.CloudEvents)
+	}
+
+	if _, err := LoadFileConfig(writeConfigFile(t, "cloudevents:\n  routes:\n    - type: \"[\"\n      topic: orders\n")); err == nil {
+		t.Errorf("Expected error for invalid route pattern")
+	}
+}
+
+func TestLoadFileConfigCSV(t *testing.T) {
+	path := writeConfigFile(t, `
+topics:
+  users:
+    csv:
+      delimiter: ";"
+      key_column: id
+      fields:
+        "E-mail": email
+      types:
+        age: int
+`)
+
+	fileConfig, err := LoadFileConfig(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	csvConfig := fileConfig.Topics["users"].CSV
+	if csvConfig == nil || csvConfig.Delimiter != ";" || csvConfig.KeyColumn != "id" || csvConfig.Types["age"] != CSVTypeInt {
+		t.Errorf("Unexpected csv config: %+v", csvConfig)
+	}
+
+	invalid := []string{
+		"topics:\n  users:\n    csv:\n      delimiter: \";;\"\n",
+		"topics:\n  users:\n    csv:\n      types:\n        age: integer\n",
+	}
+	for _, content := range invalid {
+		if _, err := LoadFileConfig(writeConfigFile(t, content)); err == nil {
+			t.Errorf("Expected error for config %q", content)
+		}
+	}
+}
+
+func TestLoadFileConfigRouting(t *testing.T) {
+	path := writeConfigFile(t, `
+routing:
+  rules:
+    - name: orders-created
+      match:
+        topic: orders
+        headers:
+          x-region: "eu-*"
+        value:
+          event_type: order.created
+      topics: [orders.created.v2, analytics]
+`)
+
+	fileConfig, err := LoadFileConfig(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rules := fileConfig.Routing.Rules
+	if len(rules) != 1 || len(rules[0].Topics) != 2 || rules[0].Match.Value["event_type"] != "order.created" {
+		t.Errorf("Unexpected routing config: %+v", fileConfig.Routing)
+	}
+
+	invalid := []string{
+		"routing:\n  rules:\n    - match: {topic: orders}\n",
+		"routing:\n  rules:\n    - match: {key: \"[\"}\n      topics: [orders]\n",
+	}
+	for _, content := range invalid {
+		if _, err := LoadFileConfig(writeConfigFile(t, content)); err == nil {
+			t.Errorf("Expected error for config %q", content)
+		}
+	}
+}
+
+func TestLoadFileConfigAliases(t *testing.T) {
+	t.Setenv("DEPLOY_ENV", "staging")
+
+	path := writeConfigFile(t, `
+routing:
+  prefix: "${DEPLOY_ENV}."
+  aliases:
+    user-events: users.events.v3
+`)
+
+	fileConfig, err := LoadFileConfig(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if fileConfig.Routing.Prefix != "staging." || fileConfig.Routing.Aliases["user-events"] != "users.events.v3" {
+		t.Errorf("Unexpected routing config: %+v", fileConfig.Routing)
+	}
+
+	if _, err := LoadFileConfig(writeConfigFile(t, "routing:\n  aliases:\n    user-events: \"\"\n")); err == nil {
+		t.Errorf("Expected error for empty alias target")
+	}
+}
+
+func TestLoadFileConfigTransform(t *testing.T) {
+	path := writeConfigFile(t, `
+topics:
+  orders:
+    transform:
+      - rename: {userId: user_id}
+      - cast: {amount: float}
+      - flatten: {field: address, separator: _}
+      - template: {full_name: "{{.first_name}} {{.last_name}}"}
+`)
+
+	fileConfig, err := LoadFileConfig(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	steps := fileConfig.Topics["orders"].Transform
+	if len(steps) != 4 || steps[0].Rename["userId"] != "user_id" || steps[2].Flatten.Separator != "_" {
+		t.Errorf("Unexpected transform config: %+v", steps)
+	}
+
+	invalid := []string{
+		"topics:\n  orders:\n    transform:\n      - {}\n",
+		"topics:\n  orders:\n    transform:\n      - drop: [a]\n        add: {b: 1}\n",
+		"topics:\n  orders:\n    transform:\n      - cast: {amount: decimal}\n",
+	}
+	for _, content := range invalid {
+		if _, err := LoadFileConfig(writeConfigFile(t, content)); err == nil {
+			t.Errorf("Expected error for config %q", content)
+		}
+	}
+}
+
+func TestLoadFileConfigKey(t *testing.T) {
+	path := writeConfigFile(t, `
+topics:
+  users:
+    key:
+      path: $.user.id
+      required: true
+  payments:
+    key:
+      template: "{{.tenant}}:{{.user.id}}"
+`)
+
+	fileConfig, err := LoadFileConfig(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if key := fileConfig.Topics["users"].Key; key == nil || key.Path != "$.user.id" || !key.Required {
+		t.Errorf("Unexpected key config: %+v", key)
+	}
+
+	invalid := []string{
+		"topics:\n  users:\n    key: {}\n",
+		"topics:\n  users:\n    key: {path: $.id, template: \"{{.id}}\"}\n",
+		"topics:\n  users:\n    key: {path: user.id}\n",
+	}
+	for _, content := range invalid {
+		if _, err := LoadFileConfig(writeConfigFile(t, content)); err == nil {
+			t.Errorf("Expected error for config %q", content)
+		}
+	}
+}
+
+func TestLoadFileConfigPartitioner(t *testing.T) {
+	fileConfig, err := LoadFileConfig(writeConfigFile(t, "topics:\n  orders:\n    partitioner: murmur2\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fileConfig.Topics["orders"].Partitioner != PartitionerMurmur2 {
+		t.Errorf("Unexpected partitioner: %q", fileConfig.Topics["orders"].Partitioner)
+	}
+
+	if _, err := LoadFileConfig(writeConfigFile(t, "topics:\n  orders:\n    partitioner: sticky\n")); err == nil {
+		t.Errorf("Expected error for invalid partitioner")
+	}
+}
+
+func TestLoadFileConfigTimestampPath(t *testing.T) {
+	fileConfig, err := LoadFileConfig(writeConfigFile(t, "topics:\n  events:\n    timestamp_path: $.occurred_at\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fileConfig.Topics["events"].TimestampPath != "$.occurred_at" {
+		t.Errorf("Unexpected timestamp path: %q", fileConfig.Topics["events"].TimestampPath)
+	}
+
+	if _, err := LoadFileConfig(writeConfigFile(t, "topics:\n  events:\n    timestamp_path: occurred_at\n")); err == nil {
+		t.Errorf("Expected error for invalid timestamp path")
+	}
+}
+
+func TestLoadFileConfigHeaders(t *testing.T) {
+	t.Setenv("POD_NAME", "gateway-7")
+
+	path := writeConfigFile(t, `
+headers:
+  forward:
+    - header: X-Request-Id
+    - header: X-Tenant-*
+      as: "tenant.*"
+  metadata:
+    instance: ${POD_NAME}
+`)
+
+	fileConfig, err := LoadFileConfig(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	headers := fileConfig.Headers
+	if len(headers.Forward) != 2 || headers.Forward[1].As != "tenant.*" {
+		t.Errorf("Unexpected forward rules: %+v", headers.Forward)
+	}
+	if headers.Metadata.Instance != "gateway-7" || headers.Metadata.Prefix != "gateway-" {
+		t.Errorf("Unexpected metadata config: %+v", headers.Metadata)
+	}
+
+	invalid := []string{
+		"headers:\n  forward:\n    - as: tenant\n",
+		"headers:\n  forward:\n    - header: \"[\"\n",
+		"headers:\n  forward:\n    - header: \"x-*-*\"\n      as: \"t.*\"\n",
+	}
+	for _, content := range invalid {
+		if _, err := LoadFileConfig(writeConfigFile(t, content)); err == nil {
+			t.Errorf("Expected error for config %q", content)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package csvimport
+
+import (
+	"kafkaGateway/config"
+)
+
+// Mappings правила преобразования CSV, заданные для топиков
+type Mappings struct {
+	topics map[string]config.CSVConfig
+}
+
+func NewMappings(topics map[string]config.TopicConfig) *Mappings {
+	mappings := &Mappings{topics: make(map[string]config.CSVConfig)}
+	for topic, topicConfig := range topics {
+		if topicConfig.CSV != nil {
+			mappings.topics[topic] = *topicConfig.CSV
+		}
+	}
+	return mappings
+}
+
+// Mapping возвращает правила топика; для топиков без настроек все колонки передаются строками
+func (m *Mappings) Mapping(topic string) config.CSVConfig {
+	return m.topics[topic]
+}
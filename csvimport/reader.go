@@ -0,0 +1,198 @@
+package csvimport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"kafkaGateway/config"
+)
+
+// ErrInvalidHeader возвращается, если строка заголовков CSV отсутствует или некорректна
+var ErrInvalidHeader = errors.New("invalid csv header")
+
+// utf8BOM добавляется в начало файла некоторыми табличными редакторами
+const utf8BOM = "\uFEFF"
+
+// Row строка CSV, преобразованная в JSON объект
+type Row struct {
+	// Line номер строки в теле запроса
+	Line  int
+	Key   []byte
+	Value map[string]interface{}
+}
+
+// RowError ошибка разбора или приведения типов строки CSV
+type RowError struct {
+	Line int
+	// Field поле объекта, значение которого не удалось привести к типу
+	Field   string
+	Message string
+}
+
+func (e *RowError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("line %d: field %s: %s", e.Line, e.Field, e.Message)
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+type column struct {
+	field      string
+	columnType string
+	skip       bool
+}
+
+// Reader потоково читает CSV и преобразует строки в объекты: заголовки колонок становятся полями
+type Reader struct {
+	reader   *csv.Reader
+	columns  []column
+	keyIndex int
+}
+
+// NewReader читает строку заголовков и готовит преобразование строк по правилам cfg
+func NewReader(r io.Reader, cfg config.CSVConfig) (*Reader, error) {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+	if cfg.Delimiter != "" {
+		reader.Comma, _ = utf8.DecodeRuneInString(cfg.Delimiter)
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: request body is empty", ErrInvalidHeader)
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHeader, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	header[0] = strings.TrimPrefix(header[0], utf8BOM)
+
+	skip := make(map[string]bool, len(cfg.SkipColumns))
+	for _, name := range cfg.SkipColumns {
+		skip[name] = true
+	}
+
+	cr := &Reader{reader: reader, columns: make([]column, len(header)), keyIndex: -1}
+	fields := make(map[string]bool, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: column %d has empty name", ErrInvalidHeader, i+1)
+		}
+		if name == cfg.KeyColumn {
+			cr.keyIndex = i
+		}
+
+		field := name
+		if renamed, ok := cfg.Fields[name]; ok {
+			field = renamed
+		}
+		if !skip[name] {
+			if fields[field] {
+				return nil, fmt.Errorf("%w: duplicate field %q", ErrInvalidHeader, field)
+			}
+			fields[field] = true
+		}
+
+		columnType := cfg.Types[name]
+		if columnType == "" {
+			columnType = config.CSVTypeString
+		}
+		cr.columns[i] = column{field: field, columnType: columnType, skip: skip[name]}
+	}
+
+	if cfg.KeyColumn != "" && cr.keyIndex < 0 {
+		return nil, fmt.Errorf("%w: key column %q not found", ErrInvalidHeader, cfg.KeyColumn)
+	}
+
+	return cr, nil
+}
+
+// Read возвращает следующую строку. Ошибки отдельных строк возвращаются как *RowError,
+// после них чтение можно продолжить. В конце данных возвращается io.EOF
+func (cr *Reader) Read() (*Row, error) {
+	record, err := cr.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if !errors.As(err, &parseErr) {
+			return nil, err
+		}
+		if errors.Is(err, csv.ErrFieldCount) {
+			return nil, &RowError{
+				Line:    parseErr.StartLine,
+				Message: fmt.Sprintf("expected %d fields, got %d", len(cr.columns), len(record)),
+			}
+		}
+		return nil, &RowError{Line: parseErr.StartLine, Message: parseErr.Err.Error()}
+	}
+
+	line, _ := cr.reader.FieldPos(0)
+	row := &Row{Line: line, Value: make(map[string]interface{}, len(record))}
+
+	for i, raw := range record {
+		col := cr.columns[i]
+		if i == cr.keyIndex && raw != "" {
+			row.Key = []byte(raw)
+		}
+		if col.skip {
+			continue
+		}
+
+		value, err := coerce(raw, col.columnType)
+		if err != nil {
+			return nil, &RowError{Line: line, Field: col.field, Message: err.Error()}
+		}
+		row.Value[col.field] = value
+	}
+
+	return row, nil
+}
+
+// coerce приводит значение ячейки к типу колонки. Пустая ячейка типизированной колонки становится null
+func coerce(raw, columnType string) (interface{}, error) {
+	if columnType == config.CSVTypeString {
+		return raw, nil
+	}
+
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return nil, nil
+	}
+
+	switch columnType {
+	case config.CSVTypeInt:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", raw)
+		}
+		return i, nil
+	case config.CSVTypeFloat:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", raw)
+		}
+		return f, nil
+	case config.CSVTypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", raw)
+		}
+		return b, nil
+	case config.CSVTypeJSON:
+		var v interface{}
+		if err := json.Unmarshal([]byte(value), &v); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %v", err)
+		}
+		return v, nil
+	}
+	return nil, fmt.Errorf("unsupported type %q", columnType)
+}
@@ -0,0 +1,95 @@
+package csvimport
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"kafkaGateway/config"
+)
+
+func TestReader(t *testing.T) {
+	body := "\uFEFFid;E-mail;age;active;tags;note\n" +
+		"1;a@example.com;30;true;\"[\"\"x\"\"]\";hello\n" +
+		"2;b@example.com;;false;;\n" +
+		"3;c@example.com;thirty;true;;\n" +
+		"4;d@example.com\n" +
+		"\"5;e@example.com;40;true;;\n"
+
+	reader, err := NewReader(strings.NewReader(body), config.CSVConfig{
+		Delimiter:   ";",
+		KeyColumn:   "id",
+		Fields:      map[string]string{"E-mail": "email"},
+		Types:       map[string]string{"age": config.CSVTypeInt, "active": config.CSVTypeBool, "tags": config.CSVTypeJSON},
+		SkipColumns: []string{"note"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	row, err := reader.Read()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"id": "1", "email": "a@example.com", "age": int64(30), "active": true, "tags": []interface{}{"x"},
+	}
+	if row.Line != 2 || string(row.Key) != "1" || !reflect.DeepEqual(row.Value, expected) {
+		t.Errorf("Unexpected row: line=%d key=%s value=%v", row.Line, row.Key, row.Value)
+	}
+
+	row, err = reader.Read()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if row.Value["age"] != nil || row.Value["tags"] != nil || row.Value["active"] != false {
+		t.Errorf("Expected empty typed cells to be null, got %v", row.Value)
+	}
+
+	expectedErrors := []struct {
+		line  int
+		field string
+	}{
+		{line: 4, field: "age"},
+		{line: 5},
+		{line: 6},
+	}
+	for _, want := range expectedErrors {
+		_, err := reader.Read()
+		var rowErr *RowError
+		if !errors.As(err, &rowErr) {
+			t.Fatalf("Expected RowError for line %d, got %v", want.line, err)
+		}
+		if rowErr.Line != want.line || rowErr.Field != want.field {
+			t.Errorf("Expected error on line %d field %q, got %+v", want.line, want.field, rowErr)
+		}
+	}
+
+	if _, err := reader.Read(); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+}
+
+func TestNewReaderInvalidHeader(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		cfg  config.CSVConfig
+	}{
+		{name: "empty body", body: ""},
+		{name: "missing key column", body: "id,name\n", cfg: config.CSVConfig{KeyColumn: "user_id"}},
+		{name: "empty column name", body: "id,,name\n"},
+		{name: "duplicate field", body: "id,user\n", cfg: config.CSVConfig{Fields: map[string]string{"user": "id"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewReader(strings.NewReader(tt.body), tt.cfg)
+			if !errors.Is(err, ErrInvalidHeader) {
+				t.Errorf("Expected ErrInvalidHeader, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"kafkaGateway/kafka"
+	"kafkaGateway/models"
+	"kafkaGateway/requestid"
+	"kafkaGateway/utils"
+)
+
+// Интерфейс для административного клиента Kafka, чтобы можно было использовать мок
+type AdminInterface interface {
+	ListTopics(ctx context.Context) ([]models.TopicInfo, error)
+	DescribeTopic(ctx context.Context, topic string) (*models.TopicDescription, error)
+	CreateTopic(ctx context.Context, req models.CreateTopicRequest) error
+	CreatePartitions(ctx context.Context, topic string, count int) error
+	DeleteTopic(ctx context.Context, topic string) error
+}
+
+// Интерфейс для сброса кэша существования топиков политики топиков
+type TopicForgetterInterface interface {
+	Forget(topic string)
+}
+
+type AdminHandler struct {
+	admin       AdminInterface
+	topicPolicy TopicForgetterInterface
+	logger      *zap.Logger
+}
+
+func NewAdminHandler(admin AdminInterface, logger *zap.Logger) *AdminHandler {
+	return &AdminHandler{
+		admin:  admin,
+		logger: logger,
+	}
+}
+
+// WithTopicPolicy сбрасывает кэш политики топиков после удаления топика, чтобы следующая отправка
+// снова проверила его существование и при необходимости создала
+func (ah *AdminHandler) WithTopicPolicy(topicPolicy TopicForgetterInterface) *AdminHandler {
+	ah.topicPolicy = topicPolicy
+	return ah
+}
+
+// ListTopics возвращает список топиков кластера
+func (ah *AdminHandler) ListTopics(c *gin.Context) {
+	topics, err := ah.admin.ListTopics(c.Request.Context())
+	if err != nil {
+		ah.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"topics":    topics,
+		"timestamp": time.Now().Unix(),
+	})
+}
+
+// DescribeTopic возвращает партиции и конфигурацию топика
+func (ah *AdminHandler) DescribeTopic(c *gin.Context) {
+	topic := c.Param("topic")
+
+	description, err := ah.admin.DescribeTopic(c.Request.Context(), topic)
+	if err != nil {
+		ah.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, description)
+}
+
+// CreateTopic создает новый топик
+func (ah *AdminHandler) CreateTopic(c *gin.Context) {
+	var req models.CreateTopicRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format: " + err.Error()})
+		return
+	}
+
+	if !utils.IsValidTopic(req.Name) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid topic name"})
+		return
+	}
+
+	if err := ah.admin.CreateTopic(c.Request.Context(), req); err != nil {
+		ah.respondError(c, err)
+		return
+	}
+
+	requestid.Logger(c.Request.Context(), ah.logger).Info("Topic created via admin API",
+		zap.String("topic", req.Name),
+		auditKey(c))
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"topic":   req.Name,
+	})
+}
+
+// CreatePartitions увеличивает число партиций топика
+func (ah *AdminHandler) CreatePartitions(c *gin.Context) {
+	topic := c.Param("topic")
+
+	var req models.CreatePartitionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format: " + err.Error()})
+		return
+	}
+
+	if err := ah.admin.CreatePartitions(c.Request.Context(), topic, req.Count); err != nil {
+		ah.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"topic":      topic,
+		"partitions": req.Count,
+	})
+}
+
+// DeleteTopic удаляет топик
+func (ah *AdminHandler) DeleteTopic(c *gin.Context) {
+	topic := c.Param("topic")
+
+	if err := ah.admin.DeleteTopic(c.Request.Context(), topic); err != nil {
+		ah.respondError(c, err)
+		return
+	}
+	if ah.topicPolicy != nil {
+		ah.topicPolicy.Forget(topic)
+	}
+
+	requestid.Logger(c.Request.Context(), ah.logger).Info("Topic deleted via admin API",
+		zap.String("topic", topic),
+		auditKey(c))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"topic":   topic,
+	})
+}
+
+func (ah *AdminHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, kafka.ErrTopicNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, kafka.ErrTopicAlreadyExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		requestid.Logger(c.Request.Context(), ah.logger).Error("Admin request failed", zap.String("path", c.FullPath()), zap.Error(err))
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Kafka admin request failed: " + err.Error()})
+	}
+}
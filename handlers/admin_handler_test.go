@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"kafkaGateway/kafka"
+	"kafkaGateway/models"
+	"kafkaGateway/propagation"
+)
+
+// MockAdmin - имитация административного клиента Kafka для тестирования
+type MockAdmin struct {
+	ListTopicsFunc       func(ctx context.Context) ([]models.TopicInfo, error)
+	DescribeTopicFunc    func(ctx context.Context, topic string) (*models.TopicDescription, error)
+	CreateTopicFunc      func(ctx context.Context, req models.CreateTopicRequest) error
+	CreatePartitionsFunc func(ctx context.Context, topic string, count int) error
+	DeleteTopicFunc      func(ctx context.Context, topic string) error
+}
+
+func (m *MockAdmin) ListTopics(ctx context.Context) ([]models.TopicInfo, error) {
+	if m.ListTopicsFunc != nil {
+		return m.ListTopicsFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockAdmin) DescribeTopic(ctx context.Context, topic string) (*models.TopicDescription, error) {
+	if m.DescribeTopicFunc != nil {
+		return m.DescribeTopicFunc(ctx, topic)
+	}
+	return &models.TopicDescription{}, nil
+}
+
+func (m *MockAdmin) CreateTopic(ctx context.Context, req models.CreateTopicRequest) error {
+	if m.CreateTopicFunc != nil {
+		return m.CreateTopicFunc(ctx, req)
+	}
+	return nil
+}
+
+func (m *MockAdmin) CreatePartitions(ctx context.Context, topic string, count int) error {
+	if m.CreatePartitionsFunc != nil {
+		return m.CreatePartitionsFunc(ctx, topic, count)
+	}
+	return nil
+}
+
+func (m *MockAdmin) DeleteTopic(ctx context.Context, topic string) error {
+	if m.DeleteTopicFunc != nil {
+		return m.DeleteTopicFunc(ctx, topic)
+	}
+	return nil
+}
+
+func newAdminRouter(admin AdminInterface) *gin.Engine {
+	logger, _ := zap.NewDevelopment()
+	gin.SetMode(gin.TestMode)
+
+	handler := NewAdminHandler(admin, logger)
+	router := gin.New()
+	router.GET("/admin/topics", handler.ListTopics)
+	router.POST("/admin/topics", handler.CreateTopic)
+	router.GET("/admin/topics/:topic", handler.DescribeTopic)
+	router.DELETE("/admin/topics/:topic", handler.DeleteTopic)
+	router.POST("/admin/topics/:topic/partitions", handler.CreatePartitions)
+	return router
+}
+
+func TestAdminHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		admin          *MockAdmin
+		expectedStatus int
+	}{
+		{
+			name:   "list topics",
+			method: "GET",
+			path:   "/admin/topics",
+			admin: &MockAdmin{
+				ListTopicsFunc: func(ctx context.Context) ([]models.TopicInfo, error) {
+					return []models.TopicInfo{{Name: "users", Partitions: 3, ReplicationFactor: 2}}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "list topics broker error",
+			method: "GET",
+			path:   "/admin/topics",
+			admin: &MockAdmin{
+				ListTopicsFunc: func(ctx context.Context) ([]models.TopicInfo, error) {
+					return nil, errors.New("connection refused")
+				},
+			},
+			expectedStatus: http.StatusBadGateway,
+		},
+		{
+			name:   "describe missing topic",
+			method: "GET",
+			path:   "/admin/topics/missing",
+			admin: &MockAdmin{
+				DescribeTopicFunc: func(ctx context.Context, topic string) (*models.TopicDescription, error) {
+					return nil, kafka.ErrTopicNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "create topic",
+			method:         "POST",
+			path:           "/admin/topics",
+			body:           `{"name":"users","partitions":3,"replication_factor":2,"configs":{"retention.ms":"1000"}}`,
+			admin:          &MockAdmin{},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "create topic with invalid name",
+			method:         "POST",
+			path:           "/admin/topics",
+			body:           `{"name":".users","partitions":3,"replication_factor":2}`,
+			admin:          &MockAdmin{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "create topic without partitions",
+			method:         "POST",
+			path:           "/admin/topics",
+			body:           `{"name":"users","replication_factor":2}`,
+			admin:          &MockAdmin{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "create existing topic",
+			method: "POST",
+			path:   "/admin/topics",
+			body:   `{"name":"users","partitions":3,"replication_factor":2}`,
+			admin: &MockAdmin{
+				CreateTopicFunc: func(ctx context.Context, req models.CreateTopicRequest) error {
+					return kafka.ErrTopicAlreadyExists
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "add partitions",
+			method: "POST",
+			path:   "/admin/topics/users/partitions",
+			body:   `{"count":6}`,
+			admin: &MockAdmin{
+				CreatePartitionsFunc: func(ctx context.Context, topic string, count int) error {
+					if topic != "users" || count != 6 {
+						return errors.New("unexpected arguments")
+					}
+					return nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "delete topic",
+			method:         "DELETE",
+			path:           "/admin/topics/users",
+			admin:          &MockAdmin{},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newAdminRouter(tt.admin)
+
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d. Response body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+// MockTopicForgetter - имитация сброса кэша политики топиков для тестирования
+type MockTopicForgetter struct {
+	forgotten []string
+}
+
+func (m *MockTopicForgetter) Forget(topic string) {
+	m.forgotten = append(m.forgotten, topic)
+}
+
+func TestAdminHandler_DeleteTopicForgetsPolicyCache(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name              string
+		deleteError       error
+		expectedStatus    int
+		expectedForgotten int
+	}{
+		{
+			name:              "deleted",
+			expectedStatus:    http.StatusOK,
+			expectedForgotten: 1,
+		},
+		{
+			name:              "topic not found",
+			deleteError:       kafka.ErrTopicNotFound,
+			expectedStatus:    http.StatusNotFound,
+			expectedForgotten: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admin := &MockAdmin{
+				DeleteTopicFunc: func(ctx context.Context, topic string) error {
+					return tt.deleteError
+				},
+			}
+			topicPolicy := &MockTopicForgetter{}
+
+			handler := NewAdminHandler(admin, logger).WithTopicPolicy(topicPolicy)
+			router := gin.New()
+			router.DELETE("/admin/topics/:topic", handler.DeleteTopic)
+
+			req, _ := http.NewRequest("DELETE", "/admin/topics/users", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d. Response body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if len(topicPolicy.forgotten) != tt.expectedForgotten {
+				t.Errorf("Expected %d forgotten topics, got %v", tt.expectedForgotten, topicPolicy.forgotten)
+			}
+			if tt.expectedForgotten > 0 && topicPolicy.forgotten[0] != "users" {
+				t.Errorf("Expected topic users to be forgotten, got %v", topicPolicy.forgotten)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_AuditLogFingerprintsAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	handler := NewAdminHandler(&MockAdmin{}, zap.New(core))
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("api_key", "admin-secret")
+		c.Next()
+	})
+	router.POST("/admin/topics", handler.CreateTopic)
+	router.DELETE("/admin/topics/:topic", handler.DeleteTopic)
+
+	req, _ := http.NewRequest("POST", "/admin/topics", bytes.NewBufferString(`{"name": "users", "partitions": 3, "replication_factor": 1}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	req, _ = http.NewRequest("DELETE", "/admin/topics/users", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if logs.Len() != 2 {
+		t.Fatalf("Expected 2 audit entries, got %d", logs.Len())
+	}
+	for _, entry := range logs.All() {
+		fields := entry.ContextMap()
+		if fields["api_key_id"] != propagation.Fingerprint("admin-secret") {
+			t.Errorf("Expected api_key_id fingerprint in %q, got %v", entry.Message, fields)
+		}
+		for name, value := range fields {
+			if value == "admin-secret" {
+				t.Errorf("Raw API key logged in field %s of %q", name, entry.Message)
+			}
+		}
+	}
+}
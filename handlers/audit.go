@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"kafkaGateway/propagation"
+)
+
+// auditKey поле журнала с отпечатком API ключа, выполнившего административное действие.
+// Сам ключ в журнал не пишется
+func auditKey(c *gin.Context) zap.Field {
+	apiKey := c.GetString("api_key")
+	if apiKey == "" {
+		return zap.Skip()
+	}
+	return zap.String("api_key_id", propagation.Fingerprint(apiKey))
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"kafkaGateway/propagation"
+)
+
+func TestAuditKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		apiKey   string
+		expected interface{}
+	}{
+		{name: "fingerprint", apiKey: "admin-secret", expected: propagation.Fingerprint("admin-secret")},
+		{name: "no key", apiKey: "", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.InfoLevel)
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			if tt.apiKey != "" {
+				c.Set("api_key", tt.apiKey)
+			}
+
+			zap.New(core).Info("Admin action", auditKey(c))
+
+			fields := logs.All()[0].ContextMap()
+			if fields["api_key_id"] != tt.expected {
+				t.Errorf("Expected api_key_id %v, got %v", tt.expected, fields["api_key_id"])
+			}
+			if _, ok := fields["api_key"]; ok {
+				t.Errorf("Expected raw api_key field to be absent, got %v", fields)
+			}
+		})
+	}
+}
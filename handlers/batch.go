@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+
+	"kafkaGateway/kafka"
+	"kafkaGateway/metrics"
+	"kafkaGateway/models"
+	"kafkaGateway/propagation"
+	"kafkaGateway/requestid"
+	"kafkaGateway/tracing"
+)
+
+const (
+	// batchSize число записей в одном вызове Writer при пакетной загрузке
+	batchSize = 500
+	// maxReportedErrors ограничивает число ошибок записей в ответе
+	maxReportedErrors = 100
+)
+
+// batchWriter накапливает записи пакетной загрузки и отправляет их в Kafka пакетами
+type batchWriter struct {
+	mh    *MessageHandler
+	topic string
+	// ctx контекст запроса, родительский для спанов отправки пакетов
+	ctx context.Context
+	// headers заголовки HTTP запроса, добавляемые к каждой записи
+	headers propagation.Headers
+
+	records []kafka.Record
+	// lines номера строк тела запроса для накопленных записей
+	lines []int
+
+	result models.BatchResponse
+	// fatal ошибка, после которой загрузка прекращается
+	fatal *publishError
+}
+
+func newBatchWriter(ctx context.Context, mh *MessageHandler, topic string, headers propagation.Headers) *batchWriter {
+	return &batchWriter{
+		mh:      mh,
+		topic:   topic,
+		ctx:     ctx,
+		headers: headers,
+		records: make([]kafka.Record, 0, batchSize),
+		lines:   make([]int, 0, batchSize),
+		result:  models.BatchResponse{Topic: topic},
+	}
+}
+
+// add проверяет и кодирует запись и добавляет ее в пакет. Возвращает false, если загрузку нужно прекратить
+func (bw *batchWriter) add(ctx context.Context, line int, msg outgoingMessage) bool {
+	bw.result.Total++
+
+	// traceparent добавляется до проверки размера записи и заменяется контекстом спана пакета при отправке
+	msg.Headers = requestid.Inject(ctx, tracing.Inject(ctx, bw.headers.Merge(msg.Headers)))
+	valueBytes, err := bw.mh.encodeValue(ctx, &msg)
+	if err != nil {
+		bw.recordError(line, err)
+		return true
+	}
+
+	bw.records = append(bw.records, kafka.Record{Key: msg.Key, Value: valueBytes, Headers: msg.Headers, Partition: msg.Partition, Time: msg.Timestamp})
+	bw.lines = append(bw.lines, line)
+	if len(bw.records) >= batchSize {
+		return bw.flush()
+	}
+	return true
+}
+
+// reject учитывает запись, отклоненную до проверки по схеме (например, некорректный JSON)
+func (bw *batchWriter) reject(line int, err *publishError) {
+	bw.result.Total++
+	bw.recordError(line, err)
+}
+
+// flush отправляет накопленные записи. Возвращает false, если пакет не был записан
+func (bw *batchWriter) flush() bool {
+	if len(bw.records) == 0 {
+		return true
+	}
+	defer func() {
+		bw.records = bw.records[:0]
+		bw.lines = bw.lines[:0]
+	}()
+
+	ctx, span := startPublishSpan(bw.ctx, bw.topic, len(bw.records))
+	for i := range bw.records {
+		bw.records[i].Headers = tracing.Inject(ctx, bw.records[i].Headers)
+	}
+
+	recordErrors, err := bw.mh.producer.SendBatch(bw.topic, bw.records)
+	if err != nil {
+		metrics.KafkaErrors.WithLabelValues(bw.topic, "send_error").Inc()
+		bw.abort(sendError(err))
+		endPublishSpan(span, bw.fatal)
+		for _, line := range bw.lines {
+			bw.recordError(line, bw.fatal)
+		}
+		return false
+	}
+
+	sent := 0
+	for i, recordErr := range recordErrors {
+		if recordErr != nil {
+			metrics.KafkaErrors.WithLabelValues(bw.topic, "send_error").Inc()
+			bw.recordError(bw.lines[i], sendError(recordErr))
+			continue
+		}
+		sent++
+	}
+
+	if failed := len(bw.records) - sent; failed > 0 {
+		span.SetAttributes(attribute.Int("gateway.failed_records", failed))
+	}
+	endPublishSpan(span, nil)
+
+	bw.result.Sent += sent
+	metrics.MessagesProcessed.WithLabelValues(bw.topic, "success").Add(float64(sent))
+	return true
+}
+
+// abort прекращает загрузку с ошибкой
+func (bw *batchWriter) abort(err *publishError) {
+	if bw.fatal == nil {
+		bw.fatal = err
+	}
+}
+
+func (bw *batchWriter) recordError(line int, err *publishError) {
+	bw.result.Failed++
+	if len(bw.result.Errors) >= maxReportedErrors {
+		bw.result.ErrorsTruncated = true
+		return
+	}
+	bw.result.Errors = append(bw.result.Errors, models.RecordError{Line: line, Error: err.Message, Fields: err.Fields})
+}
+
+// respondBatch отправляет оставшиеся записи и возвращает итог загрузки клиенту
+func (mh *MessageHandler) respondBatch(c *gin.Context, startTime time.Time, bw *batchWriter) {
+	if bw.fatal == nil {
+		bw.flush()
+	}
+
+	status := http.StatusOK
+	if bw.fatal != nil {
+		status = bw.fatal.Status
+		bw.result.Error = bw.fatal.Message
+	}
+	bw.result.Success = bw.fatal == nil && bw.result.Failed == 0
+	bw.result.RequestID = c.GetString(requestid.ContextKey)
+	bw.result.Timestamp = time.Now()
+
+	mh.log(c.Request.Context()).Info("Batch upload finished",
+		zap.String("topic", bw.topic),
+		zap.Int("total", bw.result.Total),
+		zap.Int("sent", bw.result.Sent),
+		zap.Int("failed", bw.result.Failed))
+
+	route := metricsRoute(c)
+	metrics.RequestDuration.WithLabelValues("POST", route).Observe(time.Since(startTime).Seconds())
+	metrics.HTTPLatency.WithLabelValues(route, "POST", strconv.Itoa(status)).Observe(time.Since(startTime).Seconds())
+
+	c.JSON(status, bw.result)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"kafkaGateway/cloudevents"
+)
+
+// Интерфейс для выбора топика CloudEvent по типу события
+type CloudEventRouterInterface interface {
+	Route(eventType string) (string, bool)
+}
+
+// WithCloudEventRouter задает маршрутизацию CloudEvents в топики
+func (mh *MessageHandler) WithCloudEventRouter(router CloudEventRouterInterface) *MessageHandler {
+	mh.cloudEventRouter = router
+	return mh
+}
+
+// SendCloudEvent принимает CloudEvent 1.0 в структурированном (application/cloudevents+json)
+// или бинарном (заголовки ce-*) режиме и отправляет его в Kafka по протоколу CloudEvents Kafka binding
+func (mh *MessageHandler) SendCloudEvent(c *gin.Context) {
+	startTime := time.Now()
+
+	event, err := cloudevents.FromHTTP(c.Request)
+	if err != nil {
+		mh.log(c.Request.Context()).Error("Invalid CloudEvent", zap.Error(err))
+		if errors.Is(err, cloudevents.ErrInvalidEvent) {
+			mh.respondError(c, startTime, http.StatusBadRequest, err.Error())
+			return
+		}
+		mh.respondPublishError(c, startTime, requestBodyError("", err))
+		return
+	}
+
+	var topic string
+	routed := false
+	if mh.cloudEventRouter != nil {
+		topic, routed = mh.cloudEventRouter.Route(event.Type)
+	}
+	if !routed {
+		mh.log(c.Request.Context()).Error("No topic route for CloudEvent", zap.String("type", event.Type))
+		mh.respondError(c, startTime, http.StatusBadRequest, "No topic route for event type "+event.Type)
+		return
+	}
+	topic = mh.resolveTopic(topic)
+	c.Set("topic", topic)
+
+	value, err := event.DecodedData()
+	if err != nil {
+		mh.respondError(c, startTime, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Данные события отправляются как есть, разобранный JSON используется только для проверки по схеме
+	rawValue := event.Data
+	if rawValue == nil {
+		rawValue = []byte{}
+	}
+
+	if err := mh.publish(c.Request.Context(), outgoingMessage{
+		Topic: topic,
+		// Ключ из partitionkey; без него ключ берется из значения по правилу топика, иначе - id события
+		Key:        []byte(event.PartitionKey()),
+		DefaultKey: []byte(event.ID),
+		Value:      value,
+		RawValue:   rawValue,
+		Headers:    mh.requestHeaders(c).Merge(event.KafkaHeaders()),
+	}); err != nil {
+		mh.respondPublishError(c, startTime, err)
+		return
+	}
+
+	mh.respondSuccess(c, startTime, "CloudEvent "+event.ID+" sent to topic "+topic, []string{topic})
+}
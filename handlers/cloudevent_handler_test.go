@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"kafkaGateway/cloudevents"
+	"kafkaGateway/config"
+	"kafkaGateway/transform"
+)
+
+func TestMessageHandler_SendCloudEvent(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	router := cloudevents.NewRouter(config.CloudEventsConfig{
+		Routes: []config.CloudEventRoute{{Type: "com.example.order.*", Topic: "orders"}},
+	})
+
+	tests := []struct {
+		name           string
+		body           string
+		headers        map[string]string
+		expectedStatus int
+		expectedKey    string
+		expectedValue  string
+	}{
+		{
+			name:           "structured mode",
+			body:           `{"specversion": "1.0", "id": "evt-1", "source": "/shop", "type": "com.example.order.created", "data": {"id": "o-1"}}`,
+			headers:        map[string]string{"Content-Type": "application/cloudevents+json"},
+			expectedStatus: http.StatusOK,
+			expectedKey:    "evt-1",
+			expectedValue:  `{"id": "o-1"}`,
+		},
+		{
+			name: "binary mode",
+			body: `{"id": "o-2"}`,
+			headers: map[string]string{
+				"Content-Type":   "application/json",
+				"Ce-Specversion": "1.0",
+				"Ce-Id":          "evt-2",
+				"Ce-Source":      "/shop",
+				"Ce-Type":        "com.example.order.paid",
+			},
+			expectedStatus: http.StatusOK,
+			expectedKey:    "evt-2",
+			expectedValue:  `{"id": "o-2"}`,
+		},
+		{
+			name:           "no route",
+			body:           `{"specversion": "1.0", "id": "evt-3", "source": "/shop", "type": "com.example.user.created"}`,
+			headers:        map[string]string{"Content-Type": "application/cloudevents+json"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid event",
+			body:           `{"specversion": "1.0", "id": "evt-4"}`,
+			headers:        map[string]string{"Content-Type": "application/cloudevents+json"},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sentTopic, sentKey, sentValue string
+			var sentHeaders map[string]string
+			mockProducer := &ProducerMock{
+				MockSendMessageWithHeaders: func(topic string, key, value []byte, headers map[string]string) error {
+					sentTopic, sentKey, sentValue, sentHeaders = topic, string(key), string(value), headers
+					return nil
+				},
+			}
+
+			handler := NewMessageHandler(mockProducer, logger).WithCloudEventRouter(router)
+
+			req, _ := http.NewRequest("POST", "/events", bytes.NewBufferString(tt.body))
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+
+			handler.SendCloudEvent(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d. Response body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			if sentTopic != "orders" || sentKey != tt.expectedKey || sentValue != tt.expectedValue {
+				t.Errorf("Unexpected record: topic=%s key=%s value=%s", sentTopic, sentKey, sentValue)
+			}
+
+			if sentHeaders["ce_id"] != tt.expectedKey || sentHeaders["ce_specversion"] != "1.0" {
+				t.Errorf("Expected ce_ headers, got %v", sentHeaders)
+			}
+		})
+	}
+}
+
+func TestMessageHandler_SendCloudEventKeyExtraction(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	router := cloudevents.NewRouter(config.CloudEventsConfig{
+		Routes: []config.CloudEventRoute{
+			{Type: "com.example.user.*", Topic: "users"},
+			{Type: "com.example.order.*", Topic: "orders"},
+		},
+	})
+	extractors, err := transform.NewKeyExtractors(map[string]config.TopicConfig{
+		"users": {Key: &config.KeyConfig{Path: "$.user.id"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to build key extractors: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		body        string
+		expectedKey string
+	}{
+		{
+			name:        "key from value without partitionkey",
+			body:        `{"specversion": "1.0", "id": "evt-1", "source": "/app", "type": "com.example.user.created", "data": {"user": {"id": "u-1"}}}`,
+			expectedKey: "u-1",
+		},
+		{
+			name:        "partitionkey wins",
+			body:        `{"specversion": "1.0", "id": "evt-2", "source": "/app", "type": "com.example.user.created", "partitionkey": "tenant-1", "data": {"user": {"id": "u-1"}}}`,
+			expectedKey: "tenant-1",
+		},
+		{
+			name:        "id when value has no key",
+			body:        `{"specversion": "1.0", "id": "evt-3", "source": "/app", "type": "com.example.user.created", "data": {"user": {}}}`,
+			expectedKey: "evt-3",
+		},
+		{
+			name:        "id for topic without key rule",
+			body:        `{"specversion": "1.0", "id": "evt-4", "source": "/app", "type": "com.example.order.created", "data": {"user": {"id": "u-1"}}}`,
+			expectedKey: "evt-4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sentKey string
+			mockProducer := &ProducerMock{
+				MockSendMessageWithHeaders: func(topic string, key, value []byte, headers map[string]string) error {
+					sentKey = string(key)
+					return nil
+				},
+			}
+			handler := NewMessageHandler(mockProducer, logger).WithCloudEventRouter(router).WithKeyExtractor(extractors)
+
+			req, _ := http.NewRequest("POST", "/events", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/cloudevents+json")
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+
+			handler.SendCloudEvent(c)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d. Response body: %s", w.Code, w.Body.String())
+			}
+			if sentKey != tt.expectedKey {
+				t.Errorf("Expected key %q, got %q", tt.expectedKey, sentKey)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"kafkaGateway/kafka"
+	"kafkaGateway/models"
+	"kafkaGateway/requestid"
+)
+
+// Интерфейс для работы с группами потребителей, чтобы можно было использовать мок
+type ConsumerGroupAdminInterface interface {
+	ListConsumerGroups(ctx context.Context) ([]models.ConsumerGroupInfo, error)
+	DescribeConsumerGroup(ctx context.Context, groupID string) (*models.ConsumerGroupDescription, error)
+	ResetConsumerGroupOffsets(ctx context.Context, groupID string, req models.ResetOffsetsRequest) ([]models.PartitionOffset, error)
+}
+
+type ConsumerGroupHandler struct {
+	admin  ConsumerGroupAdminInterface
+	logger *zap.Logger
+}
+
+func NewConsumerGroupHandler(admin ConsumerGroupAdminInterface, logger *zap.Logger) *ConsumerGroupHandler {
+	return &ConsumerGroupHandler{
+		admin:  admin,
+		logger: logger,
+	}
+}
+
+// ListConsumerGroups возвращает список групп потребителей
+func (ch *ConsumerGroupHandler) ListConsumerGroups(c *gin.Context) {
+	groups, err := ch.admin.ListConsumerGroups(c.Request.Context())
+	if err != nil {
+		ch.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"groups":    groups,
+		"timestamp": time.Now().Unix(),
+	})
+}
+
+// DescribeConsumerGroup возвращает участников, смещения и отставание группы
+func (ch *ConsumerGroupHandler) DescribeConsumerGroup(c *gin.Context) {
+	description, err := ch.admin.DescribeConsumerGroup(c.Request.Context(), c.Param("group"))
+	if err != nil {
+		ch.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, description)
+}
+
+// ResetOffsets сбрасывает смещения неактивной группы
+func (ch *ConsumerGroupHandler) ResetOffsets(c *gin.Context) {
+	group := c.Param("group")
+
+	var req models.ResetOffsetsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format: " + err.Error()})
+		return
+	}
+
+	offsets, err := ch.admin.ResetConsumerGroupOffsets(c.Request.Context(), group, req)
+	if err != nil {
+		ch.respondError(c, err)
+		return
+	}
+
+	requestid.Logger(c.Request.Context(), ch.logger).Info("Consumer group offsets reset via admin API",
+		zap.String("group", group),
+		zap.String("topic", req.Topic),
+		zap.String("strategy", req.Strategy),
+		auditKey(c))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"group":   group,
+		"offsets": offsets,
+	})
+}
+
+func (ch *ConsumerGroupHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, kafka.ErrConsumerGroupNotFound), errors.Is(err, kafka.ErrTopicNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, kafka.ErrConsumerGroupActive):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, kafka.ErrInvalidOffsetReset):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		requestid.Logger(c.Request.Context(), ch.logger).Error("Consumer group request failed", zap.String("path", c.FullPath()), zap.Error(err))
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Kafka admin request failed: " + err.Error()})
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"kafkaGateway/kafka"
+	"kafkaGateway/models"
+)
+
+// MockConsumerGroupAdmin - имитация клиента групп потребителей для тестирования
+type MockConsumerGroupAdmin struct {
+	ListConsumerGroupsFunc        func(ctx context.Context) ([]models.ConsumerGroupInfo, error)
+	DescribeConsumerGroupFunc     func(ctx context.Context, groupID string) (*models.ConsumerGroupDescription, error)
+	ResetConsumerGroupOffsetsFunc func(ctx context.Context, groupID string, req models.ResetOffsetsRequest) ([]models.PartitionOffset, error)
+}
+
+func (m *MockConsumerGroupAdmin) ListConsumerGroups(ctx context.Context) ([]models.ConsumerGroupInfo, error) {
+	if m.ListConsumerGroupsFunc != nil {
+		return m.ListConsumerGroupsFunc(ctx)
+	}
+	return []models.ConsumerGroupInfo{}, nil
+}
+
+func (m *MockConsumerGroupAdmin) DescribeConsumerGroup(ctx context.Context, groupID string) (*models.ConsumerGroupDescription, error) {
+	if m.DescribeConsumerGroupFunc != nil {
+		return m.DescribeConsumerGroupFunc(ctx, groupID)
+	}
+	return &models.ConsumerGroupDescription{GroupID: groupID}, nil
+}
+
+func (m *MockConsumerGroupAdmin) ResetConsumerGroupOffsets(ctx context.Context, groupID string, req models.ResetOffsetsRequest) ([]models.PartitionOffset, error) {
+	if m.ResetConsumerGroupOffsetsFunc != nil {
+		return m.ResetConsumerGroupOffsetsFunc(ctx, groupID, req)
+	}
+	return []models.PartitionOffset{}, nil
+}
+
+func TestConsumerGroupHandler(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		admin          *MockConsumerGroupAdmin
+		expectedStatus int
+	}{
+		{
+			name:           "list groups",
+			method:         "GET",
+			path:           "/admin/consumer-groups",
+			admin:          &MockConsumerGroupAdmin{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "describe group",
+			method:         "GET",
+			path:           "/admin/consumer-groups/billing",
+			admin:          &MockConsumerGroupAdmin{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "describe missing group",
+			method: "GET",
+			path:   "/admin/consumer-groups/missing",
+			admin: &MockConsumerGroupAdmin{
+				DescribeConsumerGroupFunc: func(ctx context.Context, groupID string) (*models.ConsumerGroupDescription, error) {
+					return nil, kafka.ErrConsumerGroupNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "reset offsets",
+			method:         "POST",
+			path:           "/admin/consumer-groups/billing/offsets/reset",
+			body:           `{"topic":"orders","strategy":"earliest"}`,
+			admin:          &MockConsumerGroupAdmin{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "reset offsets with unknown strategy",
+			method:         "POST",
+			path:           "/admin/consumer-groups/billing/offsets/reset",
+			body:           `{"topic":"orders","strategy":"yesterday"}`,
+			admin:          &MockConsumerGroupAdmin{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "reset offsets of active group",
+			method: "POST",
+			path:   "/admin/consumer-groups/billing/offsets/reset",
+			body:   `{"topic":"orders","strategy":"latest"}`,
+			admin: &MockConsumerGroupAdmin{
+				ResetConsumerGroupOffsetsFunc: func(ctx context.Context, groupID string, req models.ResetOffsetsRequest) ([]models.PartitionOffset, error) {
+					return nil, kafka.ErrConsumerGroupActive
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewConsumerGroupHandler(tt.admin, logger)
+			router := gin.New()
+			router.GET("/admin/consumer-groups", handler.ListConsumerGroups)
+			router.GET("/admin/consumer-groups/:group", handler.DescribeConsumerGroup)
+			router.POST("/admin/consumer-groups/:group/offsets/reset", handler.ResetOffsets)
+
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d. Response body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"kafkaGateway/config"
+	"kafkaGateway/csvimport"
+	"kafkaGateway/models"
+)
+
+// Интерфейс для правил преобразования CSV по топикам
+type CSVMappingInterface interface {
+	Mapping(topic string) config.CSVConfig
+}
+
+// WithCSVMappings задает правила преобразования строк CSV для топиков
+func (mh *MessageHandler) WithCSVMappings(mappings CSVMappingInterface) *MessageHandler {
+	mh.csvMappings = mappings
+	return mh
+}
+
+// SendCSV потоково читает CSV (text/csv) и отправляет каждую строку JSON объектом в топик.
+// Первая строка задает имена полей; параметры key и delimiter переопределяют настройки топика
+func (mh *MessageHandler) SendCSV(c *gin.Context) {
+	startTime := time.Now()
+	ctx := c.Request.Context()
+	topic := mh.resolveTopic(c.Param("topic"))
+
+	if err := mh.checkTopic(ctx, topic); err != nil {
+		mh.respondPublishError(c, startTime, err)
+		return
+	}
+
+	var mapping config.CSVConfig
+	if mh.csvMappings != nil {
+		mapping = mh.csvMappings.Mapping(topic)
+	}
+	if key, ok := c.GetQuery("key"); ok {
+		mapping.KeyColumn = key
+	}
+	if delimiter, ok := c.GetQuery("delimiter"); ok {
+		if utf8.RuneCountInString(delimiter) != 1 {
+			mh.respondError(c, startTime, http.StatusBadRequest, "delimiter must be a single character")
+			return
+		}
+		mapping.Delimiter = delimiter
+	}
+
+	reader, err := csvimport.NewReader(c.Request.Body, mapping)
+	if err != nil {
+		if errors.Is(err, csvimport.ErrInvalidHeader) {
+			mh.respondError(c, startTime, http.StatusBadRequest, err.Error())
+			return
+		}
+		mh.respondPublishError(c, startTime, requestBodyError("Failed to read request body: ", err))
+		return
+	}
+
+	batch := newBatchWriter(ctx, mh, topic, mh.requestHeaders(c))
+	for {
+		if err := ctx.Err(); err != nil {
+			batch.abort(&publishError{Status: http.StatusRequestTimeout, Message: "Request cancelled: " + err.Error()})
+			break
+		}
+
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		var rowErr *csvimport.RowError
+		if errors.As(err, &rowErr) {
+			batch.reject(rowErr.Line, csvRowError(rowErr))
+			continue
+		}
+		if err != nil {
+			batch.abort(requestBodyError("Failed to read request body: ", err))
+			break
+		}
+
+		if !batch.add(ctx, row.Line, outgoingMessage{Topic: topic, Key: row.Key, Value: row.Value}) {
+			break
+		}
+	}
+
+	mh.respondBatch(c, startTime, batch)
+}
+
+func csvRowError(err *csvimport.RowError) *publishError {
+	if err.Field == "" {
+		return &publishError{Message: err.Message}
+	}
+	return &publishError{
+		Message: "Invalid value of field " + err.Field,
+		Fields:  []models.FieldError{{Path: "/" + err.Field, Message: err.Message}},
+	}
+}
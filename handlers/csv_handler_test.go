@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"kafkaGateway/config"
+	"kafkaGateway/csvimport"
+	"kafkaGateway/kafka"
+	"kafkaGateway/models"
+)
+
+func TestMessageHandler_SendCSV(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	mappings := csvimport.NewMappings(map[string]config.TopicConfig{
+		"users": {CSV: &config.CSVConfig{KeyColumn: "id", Types: map[string]string{"age": config.CSVTypeInt}}},
+	})
+
+	tests := []struct {
+		name           string
+		topic          string
+		query          string
+		body           string
+		expectedStatus int
+		expectedSent   int
+		expectedFailed int
+		expectedKeys   []string
+		expectedValues []string
+		expectedField  string
+	}{
+		{
+			name:           "topic mapping",
+			topic:          "users",
+			body:           "id,name,age\n1,Ann,30\n2,Bob,x\n",
+			expectedStatus: http.StatusOK,
+			expectedSent:   1,
+			expectedFailed: 1,
+			expectedKeys:   []string{"1"},
+			expectedValues: []string{`{"age":30,"id":"1","name":"Ann"}`},
+			expectedField:  "/age",
+		},
+		{
+			name:           "query overrides",
+			topic:          "events",
+			query:          "?key=name&delimiter=%3B",
+			body:           "id;name\n1;Ann\n",
+			expectedStatus: http.StatusOK,
+			expectedSent:   1,
+			expectedKeys:   []string{"Ann"},
+			expectedValues: []string{`{"id":"1","name":"Ann"}`},
+		},
+		{
+			name:           "missing key column",
+			topic:          "users",
+			body:           "user_id,name\n1,Ann\n",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid delimiter",
+			topic:          "events",
+			query:          "?delimiter=%3B%3B",
+			body:           "id\n1\n",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys, values []string
+			mockProducer := &ProducerMock{
+				MockSendBatch: func(topic string, records []kafka.Record) ([]error, error) {
+					for _, record := range records {
+						keys = append(keys, string(record.Key))
+						values = append(values, string(record.Value))
+					}
+					return make([]error, len(records)), nil
+				},
+			}
+			handler := NewMessageHandler(mockProducer, logger).WithCSVMappings(mappings)
+
+			req, _ := http.NewRequest("POST", "/topics/"+tt.topic+"/csv"+tt.query, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "text/csv")
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+			c.Params = gin.Params{{Key: "topic", Value: tt.topic}}
+
+			handler.SendCSV(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d. Response body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response models.BatchResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if response.Sent != tt.expectedSent || response.Failed != tt.expectedFailed {
+				t.Errorf("Expected sent=%d failed=%d, got %+v", tt.expectedSent, tt.expectedFailed, response)
+			}
+			if strings.Join(keys, ",") != strings.Join(tt.expectedKeys, ",") {
+				t.Errorf("Expected keys %v, got %v", tt.expectedKeys, keys)
+			}
+			if strings.Join(values, ",") != strings.Join(tt.expectedValues, ",") {
+				t.Errorf("Expected values %v, got %v", tt.expectedValues, values)
+			}
+
+			if tt.expectedField != "" {
+				if len(response.Errors) != 1 || response.Errors[0].Line != 3 ||
+					len(response.Errors[0].Fields) != 1 || response.Errors[0].Fields[0].Path != tt.expectedField {
+					t.Errorf("Expected error for field %s on line 3, got %+v", tt.expectedField, response.Errors)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"kafkaGateway/logger"
+	"kafkaGateway/models"
+	"kafkaGateway/requestid"
+)
+
+// Интерфейс для управления уровнями логирования во время работы
+type LogLevelControllerInterface interface {
+	Levels() []logger.LevelStatus
+	SetLevel(component string, level zapcore.Level, ttl time.Duration) (logger.LevelStatus, error)
+	ResetLevel(component string) (logger.LevelStatus, error)
+}
+
+type LogLevelHandler struct {
+	levels LogLevelControllerInterface
+	logger *zap.Logger
+}
+
+func NewLogLevelHandler(levels LogLevelControllerInterface, logger *zap.Logger) *LogLevelHandler {
+	return &LogLevelHandler{
+		levels: levels,
+		logger: logger,
+	}
+}
+
+// ListLevels возвращает общий уровень логирования и уровни компонентов
+func (lh *LogLevelHandler) ListLevels(c *gin.Context) {
+	statuses := lh.levels.Levels()
+	levels := make([]models.LogLevel, 0, len(statuses))
+	for _, status := range statuses {
+		levels = append(levels, toLogLevel(status))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"levels":    levels,
+		"timestamp": time.Now().Unix(),
+	})
+}
+
+// SetLevel задает уровень логирования компонента (global - общий уровень), при ttl - временно
+func (lh *LogLevelHandler) SetLevel(c *gin.Context) {
+	var req models.LogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format: " + err.Error()})
+		return
+	}
+
+	level, err := zapcore.ParseLevel(req.Level)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid level: " + err.Error()})
+		return
+	}
+
+	var ttl time.Duration
+	if req.TTL != "" {
+		if ttl, err = time.ParseDuration(req.TTL); err != nil || ttl <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ttl: must be a positive duration such as 15m"})
+			return
+		}
+	}
+
+	component := c.Param("component")
+	status, err := lh.levels.SetLevel(component, level, ttl)
+	if err != nil {
+		lh.respondError(c, err)
+		return
+	}
+
+	requestid.Logger(c.Request.Context(), lh.logger).Info("Log level changed via admin API",
+		zap.String("component", component),
+		zap.String("level", level.String()),
+		zap.Duration("ttl", ttl),
+		auditKey(c))
+
+	c.JSON(http.StatusOK, toLogLevel(status))
+}
+
+// ResetLevel возвращает компонент к общему уровню, а общий уровень - к заданному при запуске
+func (lh *LogLevelHandler) ResetLevel(c *gin.Context) {
+	component := c.Param("component")
+	status, err := lh.levels.ResetLevel(component)
+	if err != nil {
+		lh.respondError(c, err)
+		return
+	}
+
+	requestid.Logger(c.Request.Context(), lh.logger).Info("Log level reset via admin API",
+		zap.String("component", component),
+		zap.String("level", status.Level.String()),
+		auditKey(c))
+
+	c.JSON(http.StatusOK, toLogLevel(status))
+}
+
+func (lh *LogLevelHandler) respondError(c *gin.Context, err error) {
+	if errors.Is(err, logger.ErrUnknownComponent) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error() + ": " + c.Param("component")})
+		return
+	}
+	requestid.Logger(c.Request.Context(), lh.logger).Error("Admin request failed", zap.String("path", c.FullPath()), zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+func toLogLevel(status logger.LevelStatus) models.LogLevel {
+	level := models.LogLevel{
+		Component: status.Component,
+		Level:     status.Level.String(),
+		Inherited: status.Inherited,
+	}
+	if !status.RevertAt.IsZero() {
+		revertAt := status.RevertAt
+		level.RevertAt = &revertAt
+	}
+	return level
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"kafkaGateway/logger"
+)
+
+// Мок для уровней логирования
+type MockLogLevels struct {
+	component string
+	level     zapcore.Level
+	ttl       time.Duration
+}
+
+func (m *MockLogLevels) Levels() []logger.LevelStatus {
+	return []logger.LevelStatus{
+		{Component: logger.ComponentGlobal, Level: zapcore.InfoLevel},
+		{Component: logger.ComponentProducer, Level: zapcore.InfoLevel, Inherited: true},
+	}
+}
+
+func (m *MockLogLevels) SetLevel(component string, level zapcore.Level, ttl time.Duration) (logger.LevelStatus, error) {
+	if component != logger.ComponentGlobal && component != logger.ComponentProducer {
+		return logger.LevelStatus{}, logger.ErrUnknownComponent
+	}
+	m.component, m.level, m.ttl = component, level, ttl
+	status := logger.LevelStatus{Component: component, Level: level}
+	if ttl > 0 {
+		status.RevertAt = time.Now().Add(ttl)
+	}
+	return status, nil
+}
+
+func (m *MockLogLevels) ResetLevel(component string) (logger.LevelStatus, error) {
+	return logger.LevelStatus{Component: component, Level: zapcore.InfoLevel, Inherited: true}, nil
+}
+
+func TestLogLevelHandler(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+		expectedTTL    time.Duration
+	}{
+		{name: "list levels", method: "GET", path: "/admin/log-levels", expectedStatus: http.StatusOK, expectedBody: `"component":"producer","level":"info","inherited":true`},
+		{name: "set level", method: "PUT", path: "/admin/log-levels/producer", body: `{"level":"debug"}`, expectedStatus: http.StatusOK, expectedBody: `"level":"debug"`},
+		{name: "set level with ttl", method: "PUT", path: "/admin/log-levels/producer", body: `{"level":"debug","ttl":"15m"}`, expectedStatus: http.StatusOK, expectedBody: `"revert_at"`, expectedTTL: 15 * time.Minute},
+		{name: "invalid level", method: "PUT", path: "/admin/log-levels/producer", body: `{"level":"verbose"}`, expectedStatus: http.StatusBadRequest, expectedBody: "Invalid level"},
+		{name: "invalid ttl", method: "PUT", path: "/admin/log-levels/producer", body: `{"level":"debug","ttl":"-1m"}`, expectedStatus: http.StatusBadRequest, expectedBody: "Invalid ttl"},
+		{name: "unknown component", method: "PUT", path: "/admin/log-levels/kafka", body: `{"level":"debug"}`, expectedStatus: http.StatusNotFound, expectedBody: "unknown log component"},
+		{name: "reset level", method: "DELETE", path: "/admin/log-levels/producer", expectedStatus: http.StatusOK, expectedBody: `"inherited":true`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			levels := &MockLogLevels{}
+			handler := NewLogLevelHandler(levels, logger)
+
+			router := gin.New()
+			router.GET("/admin/log-levels", handler.ListLevels)
+			router.PUT("/admin/log-levels/:component", handler.SetLevel)
+			router.DELETE("/admin/log-levels/:component", handler.ResetLevel)
+
+			req, _ := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d. Response body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.expectedBody) {
+				t.Errorf("Expected body to contain %s, got %s", tt.expectedBody, w.Body.String())
+			}
+			if levels.ttl != tt.expectedTTL {
+				t.Errorf("Expected ttl %v, got %v", tt.expectedTTL, levels.ttl)
+			}
+		})
+	}
+}
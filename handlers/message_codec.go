@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/ugorji/go/codec"
+
+	"kafkaGateway/config"
+	"kafkaGateway/models"
+)
+
+// Типы содержимого двоичных тел запроса
+const (
+	contentTypeMsgpack  = "application/msgpack"
+	contentTypeXMsgpack = "application/x-msgpack"
+	contentTypeCBOR     = "application/cbor"
+)
+
+var (
+	msgpackHandle = newMsgpackHandle()
+	cborHandle    = newCBORHandle()
+)
+
+func newMsgpackHandle() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{}
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	h.RawToString = true
+	h.SignedInteger = true
+	return h
+}
+
+func newCBORHandle() *codec.CborHandle {
+	h := &codec.CborHandle{}
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	h.SignedInteger = true
+	return h
+}
+
+// binaryMessageRequest MessageRequest в MessagePack или CBOR; значение сохраняется в исходной кодировке
+type binaryMessageRequest struct {
+	Topic     string            `codec:"topic"`
+	Key       string            `codec:"key"`
+	Value     codec.Raw         `codec:"value"`
+	Headers   map[string]string `codec:"headers"`
+	Partition *int              `codec:"partition"`
+	Timestamp interface{}       `codec:"timestamp"`
+}
+
+// bindMessageRequest разбирает тело запроса по Content-Type: JSON, MessagePack или CBOR.
+// Для двоичных тел возвращает значение в исходной кодировке и его тип содержимого
+func bindMessageRequest(c *gin.Context) (req models.MessageRequest, rawValue []byte, contentType string, err error) {
+	var handle codec.Handle
+	switch contentType = c.ContentType(); contentType {
+	case contentTypeMsgpack, contentTypeXMsgpack:
+		handle = msgpackHandle
+	case contentTypeCBOR:
+		handle = cborHandle
+	default:
+		err = c.ShouldBindJSON(&req)
+		return req, nil, "", err
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return req, nil, "", err
+	}
+
+	var binaryReq binaryMessageRequest
+	if err := codec.NewDecoderBytes(body, handle).Decode(&binaryReq); err != nil {
+		return req, nil, "", fmt.Errorf("decode %s body: %w", contentType, err)
+	}
+
+	req = models.MessageRequest{Topic: binaryReq.Topic, Key: binaryReq.Key, Headers: binaryReq.Headers, Partition: binaryReq.Partition, Timestamp: binaryReq.Timestamp}
+	if len(binaryReq.Value) > 0 {
+		if err := codec.NewDecoderBytes(binaryReq.Value, handle).Decode(&req.Value); err != nil {
+			return req, nil, "", fmt.Errorf("decode %s value: %w", contentType, err)
+		}
+	}
+
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return req, nil, "", err
+	}
+	return req, binaryReq.Value, contentType, nil
+}
+
+// forwardsBinaryValue сообщает, отправляется ли значение топика в исходной двоичной кодировке
+func (mh *MessageHandler) forwardsBinaryValue(topic string) bool {
+	return mh.topics[topic].BinaryValue == config.BinaryValueForward
+}
+
+// withContentType добавляет заголовок content-type, если клиент не задал его сам
+func withContentType(headers map[string]string, contentType string) map[string]string {
+	if _, ok := headers["content-type"]; ok {
+		return headers
+	}
+
+	result := make(map[string]string, len(headers)+1)
+	for name, value := range headers {
+		result[name] = value
+	}
+	result["content-type"] = contentType
+	return result
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ugorji/go/codec"
+	"go.uber.org/zap"
+
+	"kafkaGateway/config"
+)
+
+func TestMessageHandler_SendMessageBinaryBody(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	topics := map[string]config.TopicConfig{
+		"metrics": {BinaryValue: config.BinaryValueForward},
+	}
+
+	encode := func(handle codec.Handle, v interface{}) []byte {
+		var buf []byte
+		if err := codec.NewEncoderBytes(&buf, handle).Encode(v); err != nil {
+			t.Fatalf("Failed to encode body: %v", err)
+		}
+		return buf
+	}
+	// Ключи map кодируются в порядке сортировки, чтобы байты пересланного значения совпадали
+	canonicalMsgpack := newMsgpackHandle()
+	canonicalMsgpack.Canonical = true
+	canonicalCBOR := newCBORHandle()
+	canonicalCBOR.Canonical = true
+	value := map[string]interface{}{"cpu": 42, "host": "web-1"}
+
+	tests := []struct {
+		name            string
+		contentType     string
+		body            []byte
+		expectedStatus  int
+		expectedValue   []byte
+		expectedHeaders map[string]string
+	}{
+		{
+			name:           "msgpack transcoded to JSON",
+			contentType:    "application/msgpack",
+			body:           encode(canonicalMsgpack, map[string]interface{}{"topic": "events", "key": "k", "value": value}),
+			expectedStatus: http.StatusOK,
+			expectedValue:  []byte(`{"cpu":42,"host":"web-1"}`),
+		},
+		{
+			name:           "cbor transcoded to JSON",
+			contentType:    "application/cbor",
+			body:           encode(canonicalCBOR, map[string]interface{}{"topic": "events", "value": value}),
+			expectedStatus: http.StatusOK,
+			expectedValue:  []byte(`{"cpu":42,"host":"web-1"}`),
+		},
+		{
+			name:            "msgpack forwarded as is",
+			contentType:     "application/x-msgpack",
+			body:            encode(canonicalMsgpack, map[string]interface{}{"topic": "metrics", "value": value}),
+			expectedStatus:  http.StatusOK,
+			expectedValue:   encode(canonicalMsgpack, value),
+			expectedHeaders: map[string]string{"content-type": "application/x-msgpack"},
+		},
+		{
+			name:           "missing value",
+			contentType:    "application/msgpack",
+			body:           encode(canonicalMsgpack, map[string]interface{}{"topic": "events"}),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed body",
+			contentType:    "application/cbor",
+			body:           []byte{0xff, 0x00},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sentValue []byte
+			var sentHeaders map[string]string
+			mockProducer := &ProducerMock{
+				MockSendMessage: func(topic string, key, value []byte) error {
+					sentValue = value
+					return nil
+				},
+				MockSendMessageWithHeaders: func(topic string, key, value []byte, headers map[string]string) error {
+					sentValue, sentHeaders = value, headers
+					return nil
+				},
+			}
+			handler := NewMessageHandler(mockProducer, logger).WithTopics(topics)
+
+			req, _ := http.NewRequest("POST", "/message", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+
+			handler.SendMessage(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d. Response body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			if !bytes.Equal(sentValue, tt.expectedValue) {
+				t.Errorf("Expected value %q, got %q", tt.expectedValue, sentValue)
+			}
+			for name, expected := range tt.expectedHeaders {
+				if sentHeaders[name] != expected {
+					t.Errorf("Expected header %s=%s, got %v", name, expected, sentHeaders)
+				}
+			}
+		})
+	}
+}
@@ -1,14 +1,31 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
+	"kafkaGateway/config"
+	"kafkaGateway/kafka"
 	"kafkaGateway/metrics"
 	"kafkaGateway/models"
+	"kafkaGateway/policy"
+	"kafkaGateway/propagation"
+	"kafkaGateway/requestid"
+	"kafkaGateway/routing"
+	"kafkaGateway/schema"
+	"kafkaGateway/tracing"
+	"kafkaGateway/transform"
 	"kafkaGateway/utils"
 )
 
@@ -16,12 +33,61 @@ import (
 type ProducerInterface interface {
 	SendMessage(topic string, key, value []byte) error
 	SendMessageWithHeaders(topic string, key, value []byte, headers map[string]string) error
+	SendRecord(topic string, record kafka.Record) error
+	SendBatch(topic string, records []kafka.Record) ([]error, error)
 	Close() error
 }
 
+// Интерфейс для политики топиков
+type TopicPolicyInterface interface {
+	Check(ctx context.Context, topic string) error
+}
+
+// Интерфейс для кодирования значений по схемам топиков
+type ValueSerializerInterface interface {
+	Serialize(ctx context.Context, topic string, value interface{}) ([]byte, bool, error)
+}
+
+// Интерфейс для проверки значений по JSON Schema топиков
+type ValueValidatorInterface interface {
+	Validate(topic string, value interface{}) error
+}
+
+// Интерфейс для преобразования сообщений перед проверкой по схеме и отправкой
+type MessageTransformerInterface interface {
+	Transform(msg *transform.Message) error
+}
+
+// Интерфейс для получения ключа сообщения из значения
+type KeyExtractorInterface interface {
+	ExtractKey(topic string, value interface{}) ([]byte, bool, error)
+}
+
+// Интерфейс для получения времени записи из значения
+type TimestampExtractorInterface interface {
+	ExtractTimestamp(topic string, value interface{}) (time.Time, bool, error)
+}
+
+// Интерфейс для переноса заголовков HTTP запроса в заголовки записей
+type HeaderPropagatorInterface interface {
+	Collect(r *http.Request, clientIP, apiKey string) propagation.Headers
+}
+
 type MessageHandler struct {
-	producer ProducerInterface
-	logger   *zap.Logger
+	producer         ProducerInterface
+	topicPolicy      TopicPolicyInterface
+	validator        ValueValidatorInterface
+	serializer       ValueSerializerInterface
+	cloudEventRouter CloudEventRouterInterface
+	csvMappings      CSVMappingInterface
+	messageRouter    MessageRouterInterface
+	transformer      MessageTransformerInterface
+	keyExtractor     KeyExtractorInterface
+	timestamps       TimestampExtractorInterface
+	headerPropagator HeaderPropagatorInterface
+	topics           map[string]config.TopicConfig
+	maxMessageBytes  int64
+	logger           *zap.Logger
 }
 
 func NewMessageHandler(producer ProducerInterface, logger *zap.Logger) *MessageHandler {
@@ -32,107 +98,545 @@ func NewMessageHandler(producer ProducerInterface, logger *zap.Logger) *MessageH
 
 }
 
-func (mh *MessageHandler) SendMessage(c *gin.Context) {
-	startTime := time.Now()
+// WithTopicPolicy включает проверку топиков по политике перед отправкой
+func (mh *MessageHandler) WithTopicPolicy(topicPolicy TopicPolicyInterface) *MessageHandler {
+	mh.topicPolicy = topicPolicy
+	return mh
+}
 
-	var req models.MessageRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		mh.logger.Error("Invalid request format", zap.Error(err))
-		metrics.RequestDuration.WithLabelValues("POST", "/message").Observe(time.Since(startTime).Seconds())
-		metrics.HTTPLatency.WithLabelValues("/message", "POST", "400").Observe(time.Since(startTime).Seconds())
+// WithValidator включает проверку значений по JSON Schema топиков
+func (mh *MessageHandler) WithValidator(validator ValueValidatorInterface) *MessageHandler {
+	mh.validator = validator
+	return mh
+}
 
-		c.JSON(http.StatusBadRequest, models.MessageResponse{
-			Success:   false,
-			Error:     "Invalid request format: " + err.Error(),
-			Timestamp: time.Now(),
-		})
-		metrics.AuthAttempts.WithLabelValues("failed").Inc()
-		return
-	}
+// WithSerializer включает кодирование значений по схемам, привязанным к топикам
+func (mh *MessageHandler) WithSerializer(serializer ValueSerializerInterface) *MessageHandler {
+	mh.serializer = serializer
+	return mh
+}
 
-	// Проверяем валидность топика
-	if !utils.IsValidTopic(req.Topic) {
-		mh.logger.Error("Invalid topic name", zap.String("topic", req.Topic))
-		metrics.RequestDuration.WithLabelValues("POST", "/message").Observe(time.Since(startTime).Seconds())
-		metrics.HTTPLatency.WithLabelValues("/message", "POST", "400").Observe(time.Since(startTime).Seconds())
-
-		c.JSON(http.StatusBadRequest, models.MessageResponse{
-			Success:   false,
-			Error:     "Invalid topic name",
-			Timestamp: time.Now(),
-		})
-		metrics.AuthAttempts.WithLabelValues("failed").Inc()
-		return
-	}
+// WithTransformer задает преобразование сообщений по топикам
+func (mh *MessageHandler) WithTransformer(transformer MessageTransformerInterface) *MessageHandler {
+	mh.transformer = transformer
+	return mh
+}
 
-	// Конвертируем значение в байты
-	valueBytes, err := utils.ConvertInterfaceToBytes(req.Value)
+// WithKeyExtractor задает получение ключа из значения для сообщений без ключа
+func (mh *MessageHandler) WithKeyExtractor(keyExtractor KeyExtractorInterface) *MessageHandler {
+	mh.keyExtractor = keyExtractor
+	return mh
+}
+
+// WithTimestampExtractor задает получение времени записи из значения для сообщений без timestamp
+func (mh *MessageHandler) WithTimestampExtractor(timestamps TimestampExtractorInterface) *MessageHandler {
+	mh.timestamps = timestamps
+	return mh
+}
+
+// WithHeaderPropagator задает перенос заголовков HTTP запроса и метаданных шлюза в записи
+func (mh *MessageHandler) WithHeaderPropagator(propagator HeaderPropagatorInterface) *MessageHandler {
+	mh.headerPropagator = propagator
+	return mh
+}
+
+// WithTopics задает настройки топиков из файла конфигурации
+func (mh *MessageHandler) WithTopics(topics map[string]config.TopicConfig) *MessageHandler {
+	mh.topics = topics
+	return mh
+}
+
+// WithMaxMessageBytes задает максимальный размер записи для топиков без собственного лимита
+func (mh *MessageHandler) WithMaxMessageBytes(maxBytes int64) *MessageHandler {
+	mh.maxMessageBytes = maxBytes
+	return mh
+}
+
+// outgoingMessage сообщение, подготовленное к отправке в Kafka
+type outgoingMessage struct {
+	Topic string
+	Key   []byte
+	// DefaultKey ключ, если клиент его не передал и он не получен из значения по правилу топика
+	DefaultKey []byte
+	// Value разобранное JSON значение для проверки и кодирования по схеме топика
+	Value interface{}
+	// RawValue готовое значение; если задано, отправляется как есть, а Value используется только для проверки
+	RawValue []byte
+	Headers  map[string]string
+	// Partition явно заданная партиция
+	Partition *int
+	// Timestamp время записи; нулевое значение - время отправки
+	Timestamp time.Time
+}
+
+// publishError ошибка отправки сообщения с HTTP статусом для ответа клиенту
+type publishError struct {
+	Status  int
+	Message string
+	Fields  []models.FieldError
+	// Limit превышенный лимит размера в байтах для ответа 413
+	Limit int64
+}
+
+func (e *publishError) Error() string {
+	return e.Message
+}
+
+func (mh *MessageHandler) SendMessage(c *gin.Context) {
+	startTime := time.Now()
+
+	req, rawValue, contentType, err := bindMessageRequest(c)
 	if err != nil {
-		mh.logger.Error("Failed to convert message value to bytes", zap.Error(err))
-		metrics.RequestDuration.WithLabelValues("POST", "/message").Observe(time.Since(startTime).Seconds())
-		metrics.HTTPLatency.WithLabelValues("/message", "POST", "500").Observe(time.Since(startTime).Seconds())
-
-		c.JSON(http.StatusInternalServerError, models.MessageResponse{
-			Success:   false,
-			Error:     "Failed to convert message value: " + err.Error(),
-			Timestamp: time.Now(),
-		})
-		metrics.AuthAttempts.WithLabelValues("failed").Inc()
+		mh.log(c.Request.Context()).Error("Invalid request format", zap.Error(err))
+		mh.respondPublishError(c, startTime, requestBodyError("Invalid request format: ", err))
 		return
 	}
 
+	// Топик запроса для журнала доступа
+	c.Set("topic", req.Topic)
+
 	// Конвертируем ключ в байты, если он есть
 	var keyBytes []byte
 	if req.Key != "" {
 		keyBytes = []byte(req.Key)
 	}
 
-	// Отправляем сообщение в Kafka
-	var sendErr error
-	if len(req.Headers) > 0 {
-		// Преобразуем строки заголовков в байты
-		headerBytes := make(map[string]string)
-		for k, v := range req.Headers {
-			headerBytes[k] = v
+	// Время записи из запроса, например при загрузке исторических событий
+	var timestamp time.Time
+	if req.Timestamp != nil {
+		if timestamp, err = transform.ParseTimestamp(req.Timestamp); err != nil {
+			mh.respondError(c, startTime, http.StatusBadRequest, "Invalid timestamp: "+err.Error())
+			return
+		}
+	}
+
+	// Выбираем топики назначения по правилам маршрутизации и разрешаем псевдонимы
+	topics := []string{req.Topic}
+	if mh.messageRouter != nil {
+		topics = mh.messageRouter.Route(routing.Message{
+			Topic:   req.Topic,
+			Key:     req.Key,
+			Headers: req.Headers,
+			Value:   req.Value,
+		})
+	}
+
+	propagated := mh.requestHeaders(c)
+	sent := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		msg := outgoingMessage{
+			Topic:     topic,
+			Key:       keyBytes,
+			Value:     req.Value,
+			Headers:   req.Headers,
+			Partition: req.Partition,
+			Timestamp: timestamp,
+		}
+
+		// Значение из MessagePack или CBOR отправляется как есть, если так настроен топик
+		if rawValue != nil && mh.forwardsBinaryValue(topic) {
+			msg.RawValue = rawValue
+			msg.Headers = withContentType(req.Headers, contentType)
+		}
+		msg.Headers = propagated.Merge(msg.Headers)
+
+		if err := mh.publish(c.Request.Context(), msg); err != nil {
+			if len(sent) > 0 {
+				err.Message += "; message was already sent to topics: " + strings.Join(sent, ", ")
+			}
+			mh.respondPublishError(c, startTime, err)
+			return
 		}
+		sent = append(sent, topic)
+	}
+
+	mh.respondSuccess(c, startTime, "Message sent to Kafka successfully", sent)
+}
+
+// publish проверяет топик и значение сообщения, кодирует его по схеме топика и отправляет в Kafka
+func (mh *MessageHandler) publish(ctx context.Context, msg outgoingMessage) (err *publishError) {
+	ctx, span := startPublishSpan(ctx, msg.Topic, 1)
+	defer func() { endPublishSpan(span, err) }()
+
+	// Контекст трассировки и идентификатор запроса передаются потребителям в заголовках записи
+	msg.Headers = requestid.Inject(ctx, tracing.Inject(ctx, msg.Headers))
 
-		sendErr = mh.producer.SendMessageWithHeaders(req.Topic, keyBytes, valueBytes, headerBytes)
+	if err := mh.checkTopic(ctx, msg.Topic); err != nil {
+		return err
+	}
+
+	valueBytes, err := mh.encodeValue(ctx, &msg)
+	if err != nil {
+		return err
+	}
+
+	// Отправляем сообщение в Kafka
+	var sendErr error
+	if msg.Partition != nil || !msg.Timestamp.IsZero() {
+		sendErr = mh.producer.SendRecord(msg.Topic, kafka.Record{
+			Key:       msg.Key,
+			Value:     valueBytes,
+			Headers:   msg.Headers,
+			Partition: msg.Partition,
+			Time:      msg.Timestamp,
+		})
+	} else if len(msg.Headers) > 0 {
+		sendErr = mh.producer.SendMessageWithHeaders(msg.Topic, msg.Key, valueBytes, msg.Headers)
 	} else {
-		sendErr = mh.producer.SendMessage(req.Topic, keyBytes, valueBytes)
+		sendErr = mh.producer.SendMessage(msg.Topic, msg.Key, valueBytes)
 	}
 
 	if sendErr != nil {
-		mh.logger.Error("Failed to send message to Kafka",
-			zap.String("topic", req.Topic),
+		mh.log(ctx).Error("Failed to send message to Kafka",
+			zap.String("topic", msg.Topic),
 			zap.Error(sendErr))
 
-		metrics.KafkaErrors.WithLabelValues(req.Topic, "send_error").Inc()
-		metrics.RequestDuration.WithLabelValues("POST", "/message").Observe(time.Since(startTime).Seconds())
-		metrics.HTTPLatency.WithLabelValues("/message", "POST", "500").Observe(time.Since(startTime).Seconds())
-
-		c.JSON(http.StatusInternalServerError, models.MessageResponse{
-			Success:   false,
-			Error:     "Failed to send message to Kafka: " + sendErr.Error(),
-			Timestamp: time.Now(),
-		})
-		metrics.AuthAttempts.WithLabelValues("failed").Inc()
-		return
+		metrics.KafkaErrors.WithLabelValues(msg.Topic, "send_error").Inc()
+		return sendError(sendErr)
 	}
 
 	// Успешная отправка
-	mh.logger.Info("Message sent to Kafka successfully",
-		zap.String("topic", req.Topic),
-		zap.String("key", req.Key),
+	mh.log(ctx).Info("Message sent to Kafka successfully",
+		zap.String("topic", msg.Topic),
+		zap.ByteString("key", msg.Key),
 		zap.Int("value_length", len(valueBytes)))
 
-	metrics.MessagesProcessed.WithLabelValues(req.Topic, "success").Inc()
-	metrics.RequestDuration.WithLabelValues("POST", "/message").Observe(time.Since(startTime).Seconds())
-	metrics.HTTPLatency.WithLabelValues("/message", "POST", "200").Observe(time.Since(startTime).Seconds())
+	metrics.MessagesProcessed.WithLabelValues(msg.Topic, "success").Inc()
+	return nil
+}
+
+// log возвращает логгер с идентификатором запроса из контекста
+func (mh *MessageHandler) log(ctx context.Context) *zap.Logger {
+	return requestid.Logger(ctx, mh.logger)
+}
+
+// startPublishSpan начинает спан отправки записей в топик. Спан записи в Kafka
+// в kafka.Producer становится дочерним через traceparent записи
+func startPublishSpan(ctx context.Context, topic string, records int) (context.Context, trace.Span) {
+	return tracing.Tracer("handlers").Start(ctx, topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypeSend,
+			semconv.MessagingDestinationName(topic),
+			semconv.MessagingBatchMessageCount(records),
+		))
+}
+
+func endPublishSpan(span trace.Span, err *publishError) {
+	if err != nil {
+		span.SetAttributes(semconv.HTTPResponseStatusCode(err.Status))
+		span.SetStatus(codes.Error, err.Message)
+	}
+	span.End()
+}
+
+// checkTopic проверяет имя топика и его допустимость по политике топиков
+func (mh *MessageHandler) checkTopic(ctx context.Context, topic string) *publishError {
+	// Проверяем валидность топика
+	if !utils.IsValidTopic(topic) {
+		mh.log(ctx).Error("Invalid topic name", zap.String("topic", topic))
+		return &publishError{Status: http.StatusBadRequest, Message: "Invalid topic name"}
+	}
+
+	// Проверяем топик по политике
+	if mh.topicPolicy != nil {
+		if err := mh.topicPolicy.Check(ctx, topic); err != nil {
+			mh.log(ctx).Error("Topic rejected by policy", zap.String("topic", topic), zap.Error(err))
+			status, message := topicErrorResponse(err)
+			return &publishError{Status: status, Message: message}
+		}
+	}
+
+	return nil
+}
+
+// encodeValue преобразует сообщение, проверяет значение по схеме топика, кодирует его и проверяет размер записи.
+// Шаги преобразования могут изменить ключ и заголовки msg
+func (mh *MessageHandler) encodeValue(ctx context.Context, msg *outgoingMessage) ([]byte, *publishError) {
+	if msg.Partition == nil && mh.topics[msg.Topic].Partitioner == config.PartitionerExplicit {
+		return nil, &publishError{Status: http.StatusBadRequest, Message: "Topic " + msg.Topic + " requires an explicit partition"}
+	}
+
+	if err := mh.transformMessage(ctx, msg); err != nil {
+		return nil, err
+	}
+	if err := mh.extractKey(ctx, msg); err != nil {
+		return nil, err
+	}
+	if err := mh.extractTimestamp(ctx, msg); err != nil {
+		return nil, err
+	}
+
+	valueBytes, err := mh.serializeValue(ctx, *msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit := mh.messageBytesLimit(msg.Topic); limit > 0 {
+		if size := recordSize(msg.Key, valueBytes, msg.Headers); size > limit {
+			mh.log(ctx).Warn("Message exceeds max record size",
+				zap.String("topic", msg.Topic),
+				zap.Int64("size", size),
+				zap.Int64("limit", limit))
+			return nil, &publishError{
+				Status:  http.StatusRequestEntityTooLarge,
+				Message: fmt.Sprintf("Record size %d exceeds %d bytes allowed for topic %s", size, limit, msg.Topic),
+				Limit:   limit,
+			}
+		}
+	}
+
+	return valueBytes, nil
+}
+
+// transformMessage применяет цепочку преобразований топика. Готовые значения (RawValue)
+// отправляются как есть и не преобразуются
+func (mh *MessageHandler) transformMessage(ctx context.Context, msg *outgoingMessage) *publishError {
+	if mh.transformer == nil || msg.RawValue != nil {
+		return nil
+	}
+
+	transformed := transform.Message{Topic: msg.Topic, Key: msg.Key, Headers: msg.Headers, Value: msg.Value}
+	if err := mh.transformer.Transform(&transformed); err != nil {
+		mh.log(ctx).Warn("Message transformation failed", zap.String("topic", msg.Topic), zap.Error(err))
+		return &publishError{Status: http.StatusUnprocessableEntity, Message: "Message transformation failed: " + err.Error()}
+	}
+
+	msg.Key = transformed.Key
+	msg.Headers = transformed.Headers
+	msg.Value = transformed.Value
+	return nil
+}
+
+// extractKey получает ключ из значения после преобразований, если клиент не передал ключ.
+// Без ключа записи одной сущности распределяются по разным партициям и теряют порядок
+func (mh *MessageHandler) extractKey(ctx context.Context, msg *outgoingMessage) *publishError {
+	if len(msg.Key) > 0 {
+		return nil
+	}
+
+	if mh.keyExtractor != nil && msg.Value != nil {
+		key, found, err := mh.keyExtractor.ExtractKey(msg.Topic, msg.Value)
+		if err != nil {
+			mh.log(ctx).Warn("Failed to extract message key", zap.String("topic", msg.Topic), zap.Error(err))
+			return &publishError{Status: http.StatusUnprocessableEntity, Message: "Failed to extract message key: " + err.Error()}
+		}
+		if found {
+			msg.Key = key
+			return nil
+		}
+	}
+
+	msg.Key = msg.DefaultKey
+	return nil
+}
+
+// extractTimestamp получает время записи из значения после преобразований, если клиент не передал timestamp
+func (mh *MessageHandler) extractTimestamp(ctx context.Context, msg *outgoingMessage) *publishError {
+	if mh.timestamps == nil || !msg.Timestamp.IsZero() || msg.Value == nil {
+		return nil
+	}
+
+	timestamp, found, err := mh.timestamps.ExtractTimestamp(msg.Topic, msg.Value)
+	if err != nil {
+		mh.log(ctx).Warn("Failed to extract record timestamp", zap.String("topic", msg.Topic), zap.Error(err))
+		return &publishError{Status: http.StatusUnprocessableEntity, Message: "Failed to extract record timestamp: " + err.Error()}
+	}
+	if found {
+		msg.Timestamp = timestamp
+	}
+	return nil
+}
+
+// requestHeaders возвращает заголовки HTTP запроса и метаданные шлюза для записей запроса
+func (mh *MessageHandler) requestHeaders(c *gin.Context) propagation.Headers {
+	if mh.headerPropagator == nil {
+		return propagation.Headers{}
+	}
+	return mh.headerPropagator.Collect(c.Request, c.ClientIP(), c.GetString("api_key"))
+}
+
+// messageBytesLimit возвращает лимит размера записи топика или лимит по умолчанию
+func (mh *MessageHandler) messageBytesLimit(topic string) int64 {
+	if limit := mh.topics[topic].MaxMessageBytes; limit > 0 {
+		return limit
+	}
+	return mh.maxMessageBytes
+}
+
+// recordSize оценивает размер записи Kafka по ключу, значению и заголовкам
+func recordSize(key, value []byte, headers map[string]string) int64 {
+	size := int64(len(key) + len(value))
+	for name, headerValue := range headers {
+		size += int64(len(name) + len(headerValue))
+	}
+	return size
+}
+
+// serializeValue проверяет значение по схеме топика и возвращает байты для записи в Kafka
+func (mh *MessageHandler) serializeValue(ctx context.Context, msg outgoingMessage) ([]byte, *publishError) {
+	// Проверяем значение по схеме топика из локального хранилища
+	if mh.validator != nil && msg.Value != nil {
+		if err := mh.validator.Validate(msg.Topic, msg.Value); err != nil {
+			mh.log(ctx).Warn("Message value rejected by schema", zap.String("topic", msg.Topic), zap.Error(err))
+			return nil, schemaError(err)
+		}
+	}
+
+	if msg.RawValue != nil {
+		return msg.RawValue, nil
+	}
+
+	// Кодируем значение по схеме топика, если она задана
+	if mh.serializer != nil {
+		encoded, handled, err := mh.serializer.Serialize(ctx, msg.Topic, msg.Value)
+		if err != nil {
+			mh.log(ctx).Error("Failed to serialize message value", zap.String("topic", msg.Topic), zap.Error(err))
+			return nil, schemaError(err)
+		}
+		if handled {
+			return encoded, nil
+		}
+	}
+
+	// Конвертируем значение в байты
+	valueBytes, err := utils.ConvertInterfaceToBytes(msg.Value)
+	if err != nil {
+		mh.log(ctx).Error("Failed to convert message value to bytes", zap.Error(err))
+		return nil, &publishError{Status: http.StatusInternalServerError, Message: "Failed to convert message value: " + err.Error()}
+	}
+	return valueBytes, nil
+}
+
+// requestBodyError ошибка разбора тела запроса: 413, если превышен допустимый размер тела, иначе 400
+func requestBodyError(prefix string, err error) *publishError {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return &publishError{
+			Status:  http.StatusRequestEntityTooLarge,
+			Message: fmt.Sprintf("Request body exceeds %d bytes", maxBytesErr.Limit),
+			Limit:   maxBytesErr.Limit,
+		}
+	}
+	return &publishError{Status: http.StatusBadRequest, Message: prefix + err.Error()}
+}
+
+// sendError преобразует ошибку записи в Kafka в ошибку отправки
+func sendError(err error) *publishError {
+	if errors.Is(err, kafka.ErrTopicNotFound) {
+		return &publishError{Status: http.StatusNotFound, Message: kafka.ErrTopicNotFound.Error()}
+	}
+	if errors.Is(err, kafka.ErrInvalidPartition) {
+		return &publishError{Status: http.StatusBadRequest, Message: err.Error()}
+	}
+	if errors.Is(err, kafka.ErrMessageTooLarge) {
+		return &publishError{Status: http.StatusRequestEntityTooLarge, Message: err.Error()}
+	}
+	return &publishError{Status: http.StatusInternalServerError, Message: "Failed to send message to Kafka: " + err.Error()}
+}
+
+// respondSuccess записывает метрики успешного запроса и возвращает ответ клиенту
+func (mh *MessageHandler) respondSuccess(c *gin.Context, startTime time.Time, message string, topics []string) {
+	route := metricsRoute(c)
+	metrics.RequestDuration.WithLabelValues("POST", route).Observe(time.Since(startTime).Seconds())
+	metrics.HTTPLatency.WithLabelValues(route, "POST", "200").Observe(time.Since(startTime).Seconds())
 
 	c.JSON(http.StatusOK, models.MessageResponse{
 		Success:   true,
-		Message:   "Message sent to Kafka successfully",
+		Message:   message,
+		Topics:    topics,
+		RequestID: c.GetString(requestid.ContextKey),
 		Timestamp: time.Now(),
 	})
 	metrics.AuthAttempts.WithLabelValues("success").Inc()
 }
+
+// respondError записывает метрики неуспешного запроса и возвращает ошибку клиенту
+func (mh *MessageHandler) respondError(c *gin.Context, startTime time.Time, status int, message string) {
+	mh.respondPublishError(c, startTime, &publishError{Status: status, Message: message})
+}
+
+// respondPublishError записывает метрики неуспешного запроса и возвращает ошибку отправки клиенту
+func (mh *MessageHandler) respondPublishError(c *gin.Context, startTime time.Time, err *publishError) {
+	route := metricsRoute(c)
+	metrics.RequestDuration.WithLabelValues("POST", route).Observe(time.Since(startTime).Seconds())
+	metrics.HTTPLatency.WithLabelValues(route, "POST", strconv.Itoa(err.Status)).Observe(time.Since(startTime).Seconds())
+
+	c.JSON(err.Status, models.MessageResponse{
+		Success:   false,
+		Error:     err.Message,
+		Errors:    err.Fields,
+		Limit:     err.Limit,
+		RequestID: c.GetString(requestid.ContextKey),
+		Timestamp: time.Now(),
+	})
+	metrics.AuthAttempts.WithLabelValues("failed").Inc()
+}
+
+// metricsRoute возвращает шаблон маршрута запроса для меток метрик
+func metricsRoute(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
+	}
+	return c.Request.URL.Path
+}
+
+// schemaError преобразует ошибку проверки или кодирования значения по схеме в ошибку отправки.
+// Ошибки несоответствия схеме отдаются с путями к полям
+func schemaError(err error) *publishError {
+	if fields := validationFieldErrors(err); fields != nil {
+		return &publishError{
+			Status:  http.StatusUnprocessableEntity,
+			Message: "Message value does not match schema: " + err.Error(),
+			Fields:  fields,
+		}
+	}
+
+	status, message := serializeErrorResponse(err)
+	return &publishError{Status: status, Message: message}
+}
+
+// validationFieldErrors преобразует ошибки несоответствия схеме в ответ API, для прочих ошибок возвращает nil
+func validationFieldErrors(err error) []models.FieldError {
+	var validationErrs schema.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fieldErrors := make([]models.FieldError, 0, len(validationErrs))
+		for _, validationErr := range validationErrs {
+			fieldErrors = append(fieldErrors, models.FieldError{Path: validationErr.Path, Message: validationErr.Message})
+		}
+		return fieldErrors
+	}
+
+	var validationErr *schema.ValidationError
+	if errors.As(err, &validationErr) {
+		return []models.FieldError{{Path: validationErr.Path, Message: validationErr.Message}}
+	}
+
+	return nil
+}
+
+// topicErrorResponse возвращает HTTP статус и текст ошибки для отказа политики топиков
+func topicErrorResponse(err error) (int, string) {
+	switch {
+	case errors.Is(err, kafka.ErrTopicNotFound):
+		return http.StatusNotFound, kafka.ErrTopicNotFound.Error()
+	case errors.Is(err, policy.ErrTopicDenied):
+		return http.StatusForbidden, policy.ErrTopicDenied.Error()
+	default:
+		return http.StatusInternalServerError, "Failed to check topic: " + err.Error()
+	}
+}
+
+// serializeErrorResponse возвращает HTTP статус и текст ошибки кодирования значения
+func serializeErrorResponse(err error) (int, string) {
+	switch {
+	case errors.Is(err, schema.ErrSubjectNotFound):
+		return http.StatusInternalServerError, "Schema for topic is not registered: " + err.Error()
+	case errors.Is(err, schema.ErrRegistry):
+		return http.StatusBadGateway, "Schema registry error: " + err.Error()
+	default:
+		// Локальная ошибка кодирования: значение не дошло до реестра
+		return http.StatusInternalServerError, "Failed to encode message value: " + err.Error()
+	}
+}
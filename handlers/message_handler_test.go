@@ -2,22 +2,39 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	otelpropagation "go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/zap"
 
+	"kafkaGateway/config"
 	"kafkaGateway/kafka"
 	"kafkaGateway/models"
+	"kafkaGateway/policy"
+	"kafkaGateway/propagation"
+	"kafkaGateway/requestid"
+	"kafkaGateway/routing"
+	"kafkaGateway/schema"
+	"kafkaGateway/transform"
 )
 
 // MockProducer - имитация Kafka Producer для тестирования
 type MockProducer struct {
 	SendMessageFunc            func(topic string, key, value []byte) error
 	SendMessageWithHeadersFunc func(topic string, key, value []byte, headers map[string]string) error
+	SendRecordFunc             func(topic string, record kafka.Record) error
+	SendBatchFunc              func(topic string, records []kafka.Record) ([]error, error)
 	CloseFunc                  func() error
 }
 
@@ -35,6 +52,20 @@ func (m *MockProducer) SendMessageWithHeaders(topic string, key, value []byte, h
 	return nil
 }
 
+func (m *MockProducer) SendRecord(topic string, record kafka.Record) error {
+	if m.SendRecordFunc != nil {
+		return m.SendRecordFunc(topic, record)
+	}
+	return nil
+}
+
+func (m *MockProducer) SendBatch(topic string, records []kafka.Record) ([]error, error) {
+	if m.SendBatchFunc != nil {
+		return m.SendBatchFunc(topic, records)
+	}
+	return make([]error, len(records)), nil
+}
+
 func (m *MockProducer) Close() error {
 	if m.CloseFunc != nil {
 		return m.CloseFunc()
@@ -47,6 +78,8 @@ type ProducerMock struct {
 	*kafka.Producer
 	MockSendMessage            func(topic string, key, value []byte) error
 	MockSendMessageWithHeaders func(topic string, key, value []byte, headers map[string]string) error
+	MockSendRecord             func(topic string, record kafka.Record) error
+	MockSendBatch              func(topic string, records []kafka.Record) ([]error, error)
 }
 
 func (p *ProducerMock) SendMessage(topic string, key, value []byte) error {
@@ -63,6 +96,20 @@ func (p *ProducerMock) SendMessageWithHeaders(topic string, key, value []byte, h
 	return nil
 }
 
+func (p *ProducerMock) SendRecord(topic string, record kafka.Record) error {
+	if p.MockSendRecord != nil {
+		return p.MockSendRecord(topic, record)
+	}
+	return nil
+}
+
+func (p *ProducerMock) SendBatch(topic string, records []kafka.Record) ([]error, error) {
+	if p.MockSendBatch != nil {
+		return p.MockSendBatch(topic, records)
+	}
+	return make([]error, len(records)), nil
+}
+
 func TestNewMessageHandler(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
 	defer logger.Sync()
@@ -235,3 +282,880 @@ func TestMessageHandler_SendMessageTopicSpecification(t *testing.T) {
 		t.Errorf("Expected status %d, got %d. Response body: %s", http.StatusOK, w.Code, w.Body.String())
 	}
 }
+
+// MockTopicPolicy - имитация политики топиков для тестирования
+type MockTopicPolicy struct {
+	CheckFunc func(ctx context.Context, topic string) error
+}
+
+func (m *MockTopicPolicy) Check(ctx context.Context, topic string) error {
+	if m.CheckFunc != nil {
+		return m.CheckFunc(ctx, topic)
+	}
+	return nil
+}
+
+func TestMessageHandler_SendMessageTopicPolicy(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		policyError    error
+		sendError      error
+		expectedStatus int
+		expectSend     bool
+	}{
+		{
+			name:           "allowed topic",
+			expectedStatus: http.StatusOK,
+			expectSend:     true,
+		},
+		{
+			name:           "unknown topic",
+			policyError:    kafka.ErrTopicNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "denied topic",
+			policyError:    policy.ErrTopicDenied,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "topic deleted after policy check",
+			sendError:      fmt.Errorf("%w: test-topic", kafka.ErrTopicNotFound),
+			expectedStatus: http.StatusNotFound,
+			expectSend:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent := false
+			mockProducer := &ProducerMock{
+				MockSendMessage: func(topic string, key, value []byte) error {
+					sent = true
+					return tt.sendError
+				},
+			}
+			topicPolicy := &MockTopicPolicy{
+				CheckFunc: func(ctx context.Context, topic string) error {
+					return tt.policyError
+				},
+			}
+
+			handler := NewMessageHandler(mockProducer, logger).WithTopicPolicy(topicPolicy)
+
+			jsonData, _ := json.Marshal(models.MessageRequest{Topic: "test-topic", Value: "test-value"})
+			req, _ := http.NewRequest("POST", "/message", bytes.NewBuffer(jsonData))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+
+			handler.SendMessage(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d. Response body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+
+			if sent != tt.expectSend {
+				t.Errorf("Expected send=%v, got %v", tt.expectSend, sent)
+			}
+		})
+	}
+}
+
+// MockSerializer - имитация сериализатора значений для тестирования
+type MockSerializer struct {
+	SerializeFunc func(ctx context.Context, topic string, value interface{}) ([]byte, bool, error)
+}
+
+func (m *MockSerializer) Serialize(ctx context.Context, topic string, value interface{}) ([]byte, bool, error) {
+	if m.SerializeFunc != nil {
+		return m.SerializeFunc(ctx, topic, value)
+	}
+	return nil, false, nil
+}
+
+func TestMessageHandler_SendMessageSerializer(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		encoded        []byte
+		handled        bool
+		serializeError error
+		expectedStatus int
+		expectedValue  []byte
+	}{
+		{
+			name:           "topic without schema",
+			expectedStatus: http.StatusOK,
+			expectedValue:  []byte("test-value"),
+		},
+		{
+			name:           "encoded value",
+			encoded:        []byte{0, 0, 0, 0, 1, 2},
+			handled:        true,
+			expectedStatus: http.StatusOK,
+			expectedValue:  []byte{0, 0, 0, 0, 1, 2},
+		},
+		{
+			name:           "value does not match schema",
+			handled:        true,
+			serializeError: &schema.ValidationError{Path: "/amount", Message: "required field is missing"},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "subject not registered",
+			handled:        true,
+			serializeError: fmt.Errorf("%w: orders-value", schema.ErrSubjectNotFound),
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "registry unavailable",
+			handled:        true,
+			serializeError: fmt.Errorf("%w: request failed: connection refused", schema.ErrRegistry),
+			expectedStatus: http.StatusBadGateway,
+		},
+		{
+			name:           "encoding error",
+			handled:        true,
+			serializeError: errors.New("proto: cannot encode value"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sentValue []byte
+			mockProducer := &ProducerMock{
+				MockSendMessage: func(topic string, key, value []byte) error {
+					sentValue = value
+					return nil
+				},
+			}
+			serializer := &MockSerializer{
+				SerializeFunc: func(ctx context.Context, topic string, value interface{}) ([]byte, bool, error) {
+					return tt.encoded, tt.handled, tt.serializeError
+				},
+			}
+
+			handler := NewMessageHandler(mockProducer, logger).WithSerializer(serializer)
+
+			jsonData, _ := json.Marshal(models.MessageRequest{Topic: "orders", Value: "test-value"})
+			req, _ := http.NewRequest("POST", "/message", bytes.NewBuffer(jsonData))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+
+			handler.SendMessage(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d. Response body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+
+			if !bytes.Equal(sentValue, tt.expectedValue) {
+				t.Errorf("Expected value %v, got %v", tt.expectedValue, sentValue)
+			}
+		})
+	}
+}
+
+// MockValidator - имитация проверки значений по JSON Schema для тестирования
+type MockValidator struct {
+	ValidateFunc func(topic string, value interface{}) error
+}
+
+func (m *MockValidator) Validate(topic string, value interface{}) error {
+	if m.ValidateFunc != nil {
+		return m.ValidateFunc(topic, value)
+	}
+	return nil
+}
+
+func TestMessageHandler_SendMessageValidator(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	sent := false
+	mockProducer := &ProducerMock{
+		MockSendMessage: func(topic string, key, value []byte) error {
+			sent = true
+			return nil
+		},
+	}
+	validator := &MockValidator{
+		ValidateFunc: func(topic string, value interface{}) error {
+			return schema.ValidationErrors{
+				{Path: "/user/age", Message: "got string, want integer"},
+				{Path: "", Message: "missing property 'id'"},
+			}
+		},
+	}
+
+	handler := NewMessageHandler(mockProducer, logger).WithValidator(validator)
+
+	jsonData, _ := json.Marshal(models.MessageRequest{Topic: "users", Value: map[string]interface{}{"user": map[string]interface{}{"age": "ten"}}})
+	req, _ := http.NewRequest("POST", "/message", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	handler.SendMessage(c)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status %d, got %d. Response body: %s", http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	}
+
+	if sent {
+		t.Errorf("Expected invalid message not to be sent")
+	}
+
+	var response models.MessageResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(response.Errors) != 2 || response.Errors[0].Path != "/user/age" || response.Errors[1].Path != "" {
+		t.Errorf("Unexpected validation errors in response: %+v", response.Errors)
+	}
+}
+
+func TestMessageHandler_SendMessageBodyTooLarge(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	handler := NewMessageHandler(&ProducerMock{}, logger)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	body := `{"topic": "test-topic", "value": "` + strings.Repeat("a", 100) + `"}`
+	c.Request, _ = http.NewRequest("POST", "/message", strings.NewReader(body))
+	c.Request.Body = http.MaxBytesReader(w, c.Request.Body, 32)
+
+	handler.SendMessage(c)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("Expected status 413, got %d. Response body: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "32 bytes") {
+		t.Errorf("Expected limit in error, got %s", w.Body.String())
+	}
+}
+
+func TestMessageHandler_SendMessageRecordSize(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	topics := map[string]config.TopicConfig{
+		"large": {MaxMessageBytes: 1000},
+	}
+
+	tests := []struct {
+		name           string
+		topic          string
+		value          string
+		sendErr        error
+		expectedStatus int
+		expectedLimit  int64
+	}{
+		{name: "within default limit", topic: "events", value: strings.Repeat("a", 50), expectedStatus: http.StatusOK},
+		{name: "over default limit", topic: "events", value: strings.Repeat("a", 150), expectedStatus: http.StatusRequestEntityTooLarge, expectedLimit: 100},
+		{name: "within topic limit", topic: "large", value: strings.Repeat("a", 150), expectedStatus: http.StatusOK},
+		{name: "over topic limit", topic: "large", value: strings.Repeat("a", 1500), expectedStatus: http.StatusRequestEntityTooLarge, expectedLimit: 1000},
+		{
+			name:           "rejected by kafka",
+			topic:          "events",
+			value:          "a",
+			sendErr:        fmt.Errorf("%w: broker limit", kafka.ErrMessageTooLarge),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent := false
+			mockProducer := &ProducerMock{
+				MockSendMessage: func(topic string, key, value []byte) error {
+					sent = true
+					return tt.sendErr
+				},
+			}
+			handler := NewMessageHandler(mockProducer, logger).WithTopics(topics).WithMaxMessageBytes(100)
+
+			body, _ := json.Marshal(models.MessageRequest{Topic: tt.topic, Value: tt.value})
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/message", bytes.NewReader(body))
+
+			handler.SendMessage(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d. Response body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedLimit > 0 {
+				if sent {
+					t.Errorf("Expected oversized record not to be produced")
+				}
+				var response models.MessageResponse
+				json.Unmarshal(w.Body.Bytes(), &response)
+				if response.Limit != tt.expectedLimit {
+					t.Errorf("Expected limit %d in response, got %d", tt.expectedLimit, response.Limit)
+				}
+			}
+		})
+	}
+}
+
+func TestMessageHandler_SendMessageRouting(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	router := routing.NewRouter(config.RoutingConfig{Rules: []config.RoutingRule{
+		{
+			Match:  config.RoutingMatch{Topic: "orders", Value: map[string]interface{}{"event_type": "order.created"}},
+			Topics: []string{"orders.created", "analytics"},
+		},
+	}}, "", logger)
+
+	tests := []struct {
+		name           string
+		value          interface{}
+		failTopic      string
+		expectedStatus int
+		expectedTopics []string
+	}{
+		{
+			name:           "fan-out",
+			value:          map[string]interface{}{"event_type": "order.created"},
+			expectedStatus: http.StatusOK,
+			expectedTopics: []string{"orders.created", "analytics"},
+		},
+		{
+			name:           "no rule matched",
+			value:          map[string]interface{}{"event_type": "order.paid"},
+			expectedStatus: http.StatusOK,
+			expectedTopics: []string{"orders"},
+		},
+		{
+			name:           "second topic failed",
+			value:          map[string]interface{}{"event_type": "order.created"},
+			failTopic:      "analytics",
+			expectedStatus: http.StatusInternalServerError,
+			expectedTopics: []string{"orders.created"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sentTopics []string
+			mockProducer := &ProducerMock{
+				MockSendMessage: func(topic string, key, value []byte) error {
+					if topic == tt.failTopic {
+						return fmt.Errorf("broker unavailable")
+					}
+					sentTopics = append(sentTopics, topic)
+					return nil
+				},
+			}
+			handler := NewMessageHandler(mockProducer, logger).WithMessageRouter(router)
+
+			body, _ := json.Marshal(models.MessageRequest{Topic: "orders", Value: tt.value})
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/message", bytes.NewReader(body))
+
+			handler.SendMessage(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d. Response body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if strings.Join(sentTopics, ",") != strings.Join(tt.expectedTopics, ",") {
+				t.Errorf("Expected topics %v, got %v", tt.expectedTopics, sentTopics)
+			}
+
+			var response models.MessageResponse
+			json.Unmarshal(w.Body.Bytes(), &response)
+			if tt.expectedStatus == http.StatusOK && strings.Join(response.Topics, ",") != strings.Join(tt.expectedTopics, ",") {
+				t.Errorf("Expected response topics %v, got %v", tt.expectedTopics, response.Topics)
+			}
+			if tt.failTopic != "" && !strings.Contains(response.Error, "orders.created") {
+				t.Errorf("Expected error to list delivered topics, got %s", response.Error)
+			}
+		})
+	}
+}
+
+func TestMessageHandler_SendMessageTransform(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	pipelines, err := transform.NewPipelines(map[string]config.TopicConfig{
+		"orders": {Transform: []config.TransformStep{
+			{Rename: map[string]string{"userId": "user_id"}},
+			{Cast: map[string]string{"amount": "float"}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		topic          string
+		value          interface{}
+		expectedStatus int
+		expectedValue  string
+	}{
+		{
+			name:           "transformed",
+			topic:          "orders",
+			value:          map[string]interface{}{"userId": 7, "amount": "10.5"},
+			expectedStatus: http.StatusOK,
+			expectedValue:  `{"amount":10.5,"user_id":7}`,
+		},
+		{
+			name:           "topic without pipeline",
+			topic:          "payments",
+			value:          map[string]interface{}{"userId": 7},
+			expectedStatus: http.StatusOK,
+			expectedValue:  `{"userId":7}`,
+		},
+		{
+			name:           "transformation failed",
+			topic:          "orders",
+			value:          map[string]interface{}{"amount": "ten"},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sentValue []byte
+			mockProducer := &ProducerMock{
+				MockSendMessage: func(topic string, key, value []byte) error {
+					sentValue = value
+					return nil
+				},
+			}
+			handler := NewMessageHandler(mockProducer, logger).WithTransformer(pipelines)
+
+			body, _ := json.Marshal(models.MessageRequest{Topic: tt.topic, Value: tt.value})
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/message", bytes.NewReader(body))
+
+			handler.SendMessage(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d. Response body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedValue != "" && string(sentValue) != tt.expectedValue {
+				t.Errorf("Expected value %s, got %s", tt.expectedValue, sentValue)
+			}
+		})
+	}
+}
+
+func TestMessageHandler_SendMessageKeyExtraction(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	extractors, err := transform.NewKeyExtractors(map[string]config.TopicConfig{
+		"users": {Key: &config.KeyConfig{Path: "$.user.id", Required: true}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		key            string
+		value          interface{}
+		expectedStatus int
+		expectedKey    string
+	}{
+		{
+			name:           "key from value",
+			value:          map[string]interface{}{"user": map[string]interface{}{"id": 42}},
+			expectedStatus: http.StatusOK,
+			expectedKey:    "42",
+		},
+		{
+			name:           "explicit key wins",
+			key:            "explicit",
+			value:          map[string]interface{}{"user": map[string]interface{}{"id": 42}},
+			expectedStatus: http.StatusOK,
+			expectedKey:    "explicit",
+		},
+		{
+			name:           "required key missing",
+			value:          map[string]interface{}{"user": map[string]interface{}{}},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sentKey []byte
+			mockProducer := &ProducerMock{
+				MockSendMessage: func(topic string, key, value []byte) error {
+					sentKey = key
+					return nil
+				},
+			}
+			handler := NewMessageHandler(mockProducer, logger).WithKeyExtractor(extractors)
+
+			body, _ := json.Marshal(models.MessageRequest{Topic: "users", Key: tt.key, Value: tt.value})
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/message", bytes.NewReader(body))
+
+			handler.SendMessage(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d. Response body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if string(sentKey) != tt.expectedKey {
+				t.Errorf("Expected key %q, got %q", tt.expectedKey, sentKey)
+			}
+		})
+	}
+}
+
+func TestMessageHandler_SendMessagePartition(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	topics := map[string]config.TopicConfig{
+		"ledger": {Partitioner: config.PartitionerExplicit},
+	}
+
+	tests := []struct {
+		name              string
+		body              string
+		sendErr           error
+		expectedStatus    int
+		expectedPartition int
+	}{
+		{
+			name:              "explicit partition",
+			body:              `{"topic":"orders","value":{"id":1},"partition":3}`,
+			expectedStatus:    http.StatusOK,
+			expectedPartition: 3,
+		},
+		{
+			name:              "partition zero",
+			body:              `{"topic":"ledger","value":{"id":1},"partition":0}`,
+			expectedStatus:    http.StatusOK,
+			expectedPartition: 0,
+		},
+		{
+			name:              "balancer partition",
+			body:              `{"topic":"orders","value":{"id":1}}`,
+			expectedStatus:    http.StatusOK,
+			expectedPartition: -1,
+		},
+		{
+			name:           "negative partition",
+			body:           `{"topic":"orders","value":{"id":1},"partition":-1}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "explicit partitioner without partition",
+			body:           `{"topic":"ledger","value":{"id":1}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "partition does not exist",
+			body:           `{"topic":"orders","value":{"id":1},"partition":99}`,
+			sendErr:        fmt.Errorf("%w: topic orders has no partition 99", kafka.ErrInvalidPartition),
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sentPartition := -1
+			mockProducer := &ProducerMock{
+				MockSendRecord: func(topic string, record kafka.Record) error {
+					sentPartition = *record.Partition
+					return tt.sendErr
+				},
+			}
+			handler := NewMessageHandler(mockProducer, logger).WithTopics(topics)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/message", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			handler.SendMessage(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d. Response body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus == http.StatusOK && sentPartition != tt.expectedPartition {
+				t.Errorf("Expected partition %d, got %d", tt.expectedPartition, sentPartition)
+			}
+		})
+	}
+}
+
+func TestMessageHandler_SendMessageTimestamp(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	timestamps, err := transform.NewTimestampExtractors(map[string]config.TopicConfig{
+		"events": {TimestampPath: "$.occurred_at"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedTime   int64
+	}{
+		{
+			name:           "RFC3339 timestamp",
+			body:           `{"topic":"orders","value":{"id":1},"timestamp":"2021-03-04T05:06:07Z"}`,
+			expectedStatus: http.StatusOK,
+			expectedTime:   1614834367000,
+		},
+		{
+			name:           "epoch millis timestamp",
+			body:           `{"topic":"orders","value":{"id":1},"timestamp":1614834367000}`,
+			expectedStatus: http.StatusOK,
+			expectedTime:   1614834367000,
+		},
+		{
+			name:           "timestamp from value",
+			body:           `{"topic":"events","value":{"occurred_at":1614834367000}}`,
+			expectedStatus: http.StatusOK,
+			expectedTime:   1614834367000,
+		},
+		{
+			name:           "request timestamp wins",
+			body:           `{"topic":"events","value":{"occurred_at":1},"timestamp":1614834367000}`,
+			expectedStatus: http.StatusOK,
+			expectedTime:   1614834367000,
+		},
+		{
+			name:           "invalid request timestamp",
+			body:           `{"topic":"orders","value":{"id":1},"timestamp":"yesterday"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid value timestamp",
+			body:           `{"topic":"events","value":{"occurred_at":"yesterday"}}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sentTime int64
+			mockProducer := &ProducerMock{
+				MockSendRecord: func(topic string, record kafka.Record) error {
+					sentTime = record.Time.UnixMilli()
+					return nil
+				},
+			}
+			handler := NewMessageHandler(mockProducer, logger).WithTimestampExtractor(timestamps)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/message", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			handler.SendMessage(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d. Response body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus == http.StatusOK && sentTime != tt.expectedTime {
+				t.Errorf("Expected record time %d, got %d", tt.expectedTime, sentTime)
+			}
+		})
+	}
+}
+
+func TestMessageHandler_SendMessageHeaderPropagation(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	propagator := propagation.NewPropagator(config.HeadersConfig{
+		Forward:  []config.HeaderForward{{Header: "X-Tenant-*", As: "tenant.*"}},
+		Metadata: config.HeaderMetadata{Prefix: "gateway-", Instance: "gateway-1"},
+	})
+
+	var sentHeaders map[string]string
+	mockProducer := &ProducerMock{
+		MockSendMessageWithHeaders: func(topic string, key, value []byte, headers map[string]string) error {
+			sentHeaders = headers
+			return nil
+		},
+	}
+	handler := NewMessageHandler(mockProducer, logger).WithHeaderPropagator(propagator)
+
+	body := `{"topic":"orders","value":{"id":1},"headers":{"source":"php","gateway-instance":"spoofed"}}`
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/message", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.Header.Set("X-Tenant-Id", "acme")
+	c.Request.Header.Set("X-Internal", "secret")
+	c.Set("api_key", "test-key")
+
+	handler.SendMessage(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Response body: %s", w.Code, w.Body.String())
+	}
+	expected := map[string]string{
+		"source":           "php",
+		"tenant.id":        "acme",
+		"gateway-instance": "gateway-1",
+		"gateway-api-key":  propagation.Fingerprint("test-key"),
+	}
+	for name, value := range expected {
+		if sentHeaders[name] != value {
+			t.Errorf("Expected header %s=%q, got %q", name, value, sentHeaders[name])
+		}
+	}
+	if _, ok := sentHeaders["x-internal"]; ok {
+		t.Errorf("Expected header not in allowlist to be dropped")
+	}
+	if _, ok := sentHeaders["gateway-received-at"]; !ok {
+		t.Errorf("Expected received-at metadata header, got %v", sentHeaders)
+	}
+}
+
+func TestMessageHandler_SendMessageTracing(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(otelpropagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	}()
+
+	var sentHeaders map[string]string
+	mockProducer := &ProducerMock{
+		MockSendMessageWithHeaders: func(topic string, key, value []byte, headers map[string]string) error {
+			sentHeaders = headers
+			return nil
+		},
+	}
+	handler := NewMessageHandler(mockProducer, logger)
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "HTTP POST /message")
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequestWithContext(ctx, "POST", "/message", strings.NewReader(`{"topic":"orders","value":{"id":1}}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.SendMessage(c)
+	parent.End()
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Response body: %s", w.Code, w.Body.String())
+	}
+
+	var publishSpan sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() == "orders publish" {
+			publishSpan = span
+		}
+	}
+	if publishSpan == nil {
+		t.Fatalf("Expected publish span, got %d spans", len(recorder.Ended()))
+	}
+	if publishSpan.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("Expected publish span to be a child of the request span")
+	}
+
+	expected := fmt.Sprintf("00-%s-%s-01", publishSpan.SpanContext().TraceID(), publishSpan.SpanContext().SpanID())
+	if sentHeaders["traceparent"] != expected {
+		t.Errorf("Expected traceparent %q, got %q", expected, sentHeaders["traceparent"])
+	}
+}
+
+func TestMessageHandler_SendMessageRequestID(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	var sentHeaders map[string]string
+	mockProducer := &ProducerMock{
+		MockSendMessageWithHeaders: func(topic string, key, value []byte, headers map[string]string) error {
+			sentHeaders = headers
+			return nil
+		},
+	}
+	handler := NewMessageHandler(mockProducer, logger)
+
+	body := `{"topic":"orders","value":{"id":1},"headers":{"x-request-id":"spoofed"}}`
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequestWithContext(requestid.NewContext(context.Background(), "req-42"), "POST", "/message", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set(requestid.ContextKey, "req-42")
+
+	handler.SendMessage(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Response body: %s", w.Code, w.Body.String())
+	}
+	var response models.MessageResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if response.RequestID != "req-42" {
+		t.Errorf("Expected request_id req-42 in response, got %q", response.RequestID)
+	}
+	if sentHeaders[requestid.RecordHeader] != "req-42" {
+		t.Errorf("Expected record header %s=req-42, got %v", requestid.RecordHeader, sentHeaders)
+	}
+}
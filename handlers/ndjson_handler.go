@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"kafkaGateway/transform"
+)
+
+// maxNDJSONLineSize максимальная длина строки NDJSON, более длинные строки отклоняются
+const maxNDJSONLineSize = 1 << 20
+
+// ndjsonEnvelope строка NDJSON в режиме envelope=true
+type ndjsonEnvelope struct {
+	Key       string            `json:"key"`
+	Value     interface{}       `json:"value"`
+	Headers   map[string]string `json:"headers"`
+	Partition *int              `json:"partition"`
+	Timestamp interface{}       `json:"timestamp"`
+}
+
+// SendNDJSON потоково читает тело запроса в формате NDJSON и отправляет каждую строку
+// отдельной записью в топик. Записи отправляются пакетами, тело целиком в память не загружается
+func (mh *MessageHandler) SendNDJSON(c *gin.Context) {
+	startTime := time.Now()
+	ctx := c.Request.Context()
+	topic := mh.resolveTopic(c.Param("topic"))
+
+	if err := mh.checkTopic(ctx, topic); err != nil {
+		mh.respondPublishError(c, startTime, err)
+		return
+	}
+
+	// В режиме envelope строка содержит key, value и headers, иначе строка - значение сообщения
+	envelope := c.Query("envelope") == "true"
+
+	batch := newBatchWriter(ctx, mh, topic, mh.requestHeaders(c))
+	reader := bufio.NewReaderSize(c.Request.Body, 64*1024)
+
+	for line := 1; ; line++ {
+		if err := ctx.Err(); err != nil {
+			batch.abort(&publishError{Status: http.StatusRequestTimeout, Message: "Request cancelled: " + err.Error()})
+			break
+		}
+
+		data, tooLong, err := readLine(reader, maxNDJSONLineSize)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			batch.abort(requestBodyError("Failed to read request body: ", err))
+			break
+		}
+
+		if tooLong {
+			batch.reject(line, &publishError{Message: fmt.Sprintf("line exceeds %d bytes", maxNDJSONLineSize)})
+			continue
+		}
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+
+		msg, parseErr := parseNDJSONLine(topic, data, envelope)
+		if parseErr != nil {
+			batch.reject(line, parseErr)
+			continue
+		}
+
+		if !batch.add(ctx, line, msg) {
+			break
+		}
+	}
+
+	mh.respondBatch(c, startTime, batch)
+}
+
+func parseNDJSONLine(topic string, data []byte, envelope bool) (outgoingMessage, *publishError) {
+	msg := outgoingMessage{Topic: topic}
+
+	if !envelope {
+		if err := json.Unmarshal(data, &msg.Value); err != nil {
+			return msg, &publishError{Message: "Invalid JSON: " + err.Error()}
+		}
+		return msg, nil
+	}
+
+	var line ndjsonEnvelope
+	if err := json.Unmarshal(data, &line); err != nil {
+		return msg, &publishError{Message: "Invalid JSON: " + err.Error()}
+	}
+	if line.Value == nil {
+		return msg, &publishError{Message: "value is required"}
+	}
+
+	if line.Partition != nil && *line.Partition < 0 {
+		return msg, &publishError{Message: "partition must not be negative"}
+	}
+
+	if line.Timestamp != nil {
+		timestamp, err := transform.ParseTimestamp(line.Timestamp)
+		if err != nil {
+			return msg, &publishError{Message: "Invalid timestamp: " + err.Error()}
+		}
+		msg.Timestamp = timestamp
+	}
+
+	msg.Value = line.Value
+	msg.Headers = line.Headers
+	msg.Partition = line.Partition
+	if line.Key != "" {
+		msg.Key = []byte(line.Key)
+	}
+	return msg, nil
+}
+
+// readLine читает строку без завершающего перевода строки. Строки длиннее max
+// дочитываются без сохранения и отмечаются tooLong
+func readLine(reader *bufio.Reader, max int) ([]byte, bool, error) {
+	var line []byte
+	tooLong := false
+
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if !tooLong {
+			if len(line)+len(chunk) > max+2 {
+				tooLong = true
+				line = nil
+			} else {
+				line = append(line, chunk...)
+			}
+		}
+
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil && (err != io.EOF || (len(line) == 0 && !tooLong)) {
+			return nil, false, err
+		}
+		return bytes.TrimRight(line, "\r\n"), tooLong, nil
+	}
+}
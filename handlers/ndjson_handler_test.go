@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"kafkaGateway/config"
+	"kafkaGateway/kafka"
+	"kafkaGateway/middleware"
+	"kafkaGateway/models"
+	"kafkaGateway/routing"
+)
+
+func TestMessageHandler_SendNDJSON(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		topic          string
+		query          string
+		body           string
+		batchErr       error
+		expectedStatus int
+		expectedSent   int
+		expectedFailed int
+		expectedLines  []int
+		expectedKeys   []string
+	}{
+		{
+			name:           "all lines sent",
+			topic:          "events",
+			body:           "{\"id\": 1}\n{\"id\": 2}\r\n\n{\"id\": 3}",
+			expectedStatus: http.StatusOK,
+			expectedSent:   3,
+		},
+		{
+			name:           "invalid lines reported",
+			topic:          "events",
+			body:           "{\"id\": 1}\nnot json\n{\"id\": 3}\n{\"id\":",
+			expectedStatus: http.StatusOK,
+			expectedSent:   2,
+			expectedFailed: 2,
+			expectedLines:  []int{2, 4},
+		},
+		{
+			name:           "envelope mode",
+			topic:          "events",
+			query:          "?envelope=true",
+			body:           "{\"key\": \"a\", \"value\": {\"id\": 1}}\n{\"key\": \"b\"}\n",
+			expectedStatus: http.StatusOK,
+			expectedSent:   1,
+			expectedFailed: 1,
+			expectedLines:  []int{2},
+			expectedKeys:   []string{"a"},
+		},
+		{
+			name:           "batch error",
+			topic:          "events",
+			body:           "{\"id\": 1}\n{\"id\": 2}\n",
+			batchErr:       errors.New("broker unavailable"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedFailed: 2,
+			expectedLines:  []int{1, 2},
+		},
+		{
+			name:           "topic not found",
+			topic:          "missing",
+			body:           "{\"id\": 1}\n",
+			batchErr:       kafka.ErrTopicNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedFailed: 1,
+			expectedLines:  []int{1},
+		},
+		{
+			name:           "invalid topic",
+			topic:          "bad topic",
+			body:           "{\"id\": 1}\n",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys []string
+			mockProducer := &ProducerMock{
+				MockSendBatch: func(topic string, records []kafka.Record) ([]error, error) {
+					if tt.batchErr != nil {
+						return nil, tt.batchErr
+					}
+					for _, record := range records {
+						if record.Key != nil {
+							keys = append(keys, string(record.Key))
+						}
+					}
+					return make([]error, len(records)), nil
+				},
+			}
+			handler := NewMessageHandler(mockProducer, logger)
+
+			req, _ := http.NewRequest("POST", "/topics/"+tt.topic+"/ndjson"+tt.query, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+			c.Params = gin.Params{{Key: "topic", Value: tt.topic}}
+
+			handler.SendNDJSON(c)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d. Response body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus == http.StatusBadRequest {
+				return
+			}
+
+			var response models.BatchResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+
+			if response.Sent != tt.expectedSent || response.Failed != tt.expectedFailed {
+				t.Errorf("Expected sent=%d failed=%d, got %+v", tt.expectedSent, tt.expectedFailed, response)
+			}
+			if response.Success != (tt.expectedFailed == 0) {
+				t.Errorf("Expected success=%v, got %v", tt.expectedFailed == 0, response.Success)
+			}
+
+			if len(response.Errors) != len(tt.expectedLines) {
+				t.Fatalf("Expected errors for lines %v, got %+v", tt.expectedLines, response.Errors)
+			}
+			for i, line := range tt.expectedLines {
+				if response.Errors[i].Line != line {
+					t.Errorf("Expected error on line %d, got %d", line, response.Errors[i].Line)
+				}
+			}
+
+			if strings.Join(keys, ",") != strings.Join(tt.expectedKeys, ",") {
+				t.Errorf("Expected keys %v, got %v", tt.expectedKeys, keys)
+			}
+		})
+	}
+}
+
+func TestReadLineTooLong(t *testing.T) {
+	body := "short\n" + strings.Repeat("x", 100) + "\nlast"
+	reader := bufio.NewReaderSize(strings.NewReader(body), 16)
+
+	expected := []struct {
+		line    string
+		tooLong bool
+	}{
+		{"short", false},
+		{"", true},
+		{"last", false},
+	}
+
+	for i, want := range expected {
+		line, tooLong, err := readLine(reader, 32)
+		if err != nil {
+			t.Fatalf("Line %d: unexpected error %v", i+1, err)
+		}
+		if string(line) != want.line || tooLong != want.tooLong {
+			t.Errorf("Line %d: expected %q tooLong=%v, got %q tooLong=%v", i+1, want.line, want.tooLong, line, tooLong)
+		}
+	}
+
+	if _, _, err := readLine(reader, 32); err == nil {
+		t.Errorf("Expected EOF after last line")
+	}
+}
+
+func TestMessageHandler_SendNDJSONAlias(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	router := routing.NewRouter(config.RoutingConfig{
+		Prefix:  "prod.",
+		Aliases: map[string]string{"user-events": "users.events.v3"},
+	}, "", logger)
+
+	var sentTopic string
+	mockProducer := &ProducerMock{
+		MockSendBatch: func(topic string, records []kafka.Record) ([]error, error) {
+			sentTopic = topic
+			return make([]error, len(records)), nil
+		},
+	}
+	handler := NewMessageHandler(mockProducer, logger).WithMessageRouter(router)
+
+	req, _ := http.NewRequest("POST", "/topics/user-events/ndjson", strings.NewReader("{\"id\": 1}\n"))
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "topic", Value: "user-events"}}
+
+	handler.SendNDJSON(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Response body: %s", w.Code, w.Body.String())
+	}
+	if sentTopic != "prod.users.events.v3" {
+		t.Errorf("Expected alias to resolve to prod.users.events.v3, got %s", sentTopic)
+	}
+}
+
+func TestMessageHandler_SendNDJSONOverRequestBodyLimit(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	sent := 0
+	mockProducer := &ProducerMock{
+		MockSendBatch: func(topic string, records []kafka.Record) ([]error, error) {
+			sent += len(records)
+			return make([]error, len(records)), nil
+		},
+	}
+	handler := NewMessageHandler(mockProducer, logger)
+
+	// Маршруты подключены как в main: лимит тела запроса только для /message,
+	// у потоковой загрузки лимит отключен
+	const requestLimit = 1024
+	router := gin.New()
+	messages := router.Group("/", middleware.NewBodyLimitMiddleware(requestLimit, logger).Limit,
+		middleware.NewDecompressMiddleware(requestLimit, logger).Decompress)
+	messages.POST("/message", handler.SendMessage)
+	streams := router.Group("/topics", middleware.NewBodyLimitMiddleware(0, logger).Limit,
+		middleware.NewDecompressMiddleware(0, logger).Decompress)
+	streams.POST("/:topic/ndjson", handler.SendNDJSON)
+
+	const lines = 5000
+	var body strings.Builder
+	for i := 0; i < lines; i++ {
+		fmt.Fprintf(&body, "{\"id\": %d, \"payload\": \"%s\"}\n", i, strings.Repeat("x", 32))
+	}
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	gz.Write([]byte(body.String()))
+	gz.Close()
+
+	for _, tt := range []struct {
+		name     string
+		body     []byte
+		encoding string
+	}{
+		{name: "plain", body: []byte(body.String())},
+		{name: "gzip", body: compressed.Bytes(), encoding: "gzip"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sent = 0
+			req, _ := http.NewRequest("POST", "/topics/events/ndjson", bytes.NewReader(tt.body))
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d. Response body: %s", w.Code, w.Body.String())
+			}
+			if sent != lines {
+				t.Errorf("Expected %d records sent, got %d", lines, sent)
+			}
+		})
+	}
+
+	req, _ := http.NewRequest("POST", "/message", strings.NewReader(body.String()))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected /message to keep the request body limit, got %d", w.Code)
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"kafkaGateway/config"
+	"kafkaGateway/requestid"
+	"kafkaGateway/routing"
+)
+
+// Интерфейс для разрешения псевдонимов топиков и выбора топиков сообщения по правилам маршрутизации
+type MessageRouterInterface interface {
+	Resolve(topic string) string
+	Route(msg routing.Message) []string
+}
+
+// WithMessageRouter включает псевдонимы топиков и выбор топиков POST /message по правилам маршрутизации
+func (mh *MessageHandler) WithMessageRouter(router MessageRouterInterface) *MessageHandler {
+	mh.messageRouter = router
+	return mh
+}
+
+// resolveTopic возвращает физический топик для псевдонима
+func (mh *MessageHandler) resolveTopic(topic string) string {
+	if mh.messageRouter == nil {
+		return topic
+	}
+	return mh.messageRouter.Resolve(topic)
+}
+
+// Интерфейс для управления псевдонимами и правилами маршрутизации
+type RoutingRulesInterface interface {
+	Rules() []config.RoutingRule
+	Aliases() map[string]string
+	Reload() error
+}
+
+type RoutingHandler struct {
+	router RoutingRulesInterface
+	logger *zap.Logger
+}
+
+func NewRoutingHandler(router RoutingRulesInterface, logger *zap.Logger) *RoutingHandler {
+	return &RoutingHandler{
+		router: router,
+		logger: logger,
+	}
+}
+
+// ListRules возвращает действующие правила маршрутизации
+func (rh *RoutingHandler) ListRules(c *gin.Context) {
+	rules := rh.router.Rules()
+	if rules == nil {
+		rules = []config.RoutingRule{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rules":     rules,
+		"timestamp": time.Now().Unix(),
+	})
+}
+
+// ListAliases возвращает псевдонимы топиков и соответствующие им физические топики
+func (rh *RoutingHandler) ListAliases(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"aliases":   rh.router.Aliases(),
+		"timestamp": time.Now().Unix(),
+	})
+}
+
+// ReloadRules перечитывает псевдонимы и правила маршрутизации из файла конфигурации
+func (rh *RoutingHandler) ReloadRules(c *gin.Context) {
+	if err := rh.router.Reload(); err != nil {
+		requestid.Logger(c.Request.Context(), rh.logger).Error("Failed to reload routing rules", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to reload routing rules: " + err.Error()})
+		return
+	}
+
+	requestid.Logger(c.Request.Context(), rh.logger).Info("Routing rules reloaded via admin API", auditKey(c))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"rules":   len(rh.router.Rules()),
+		"aliases": len(rh.router.Aliases()),
+	})
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"kafkaGateway/config"
+)
+
+// Мок для правил маршрутизации
+type MockRoutingRules struct {
+	rules     []config.RoutingRule
+	reloadErr error
+}
+
+func (m *MockRoutingRules) Rules() []config.RoutingRule {
+	return m.rules
+}
+
+func (m *MockRoutingRules) Aliases() map[string]string {
+	return map[string]string{"user-events": "prod.users.events.v3"}
+}
+
+func (m *MockRoutingRules) Reload() error {
+	return m.reloadErr
+}
+
+func TestRoutingHandler(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		reloadErr      error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "list rules", method: "GET", path: "/admin/routing/rules", expectedStatus: http.StatusOK, expectedBody: `"orders.v2"`},
+		{name: "list aliases", method: "GET", path: "/admin/routing/aliases", expectedStatus: http.StatusOK, expectedBody: `"user-events":"prod.users.events.v3"`},
+		{name: "reload", method: "POST", path: "/admin/routing/reload", expectedStatus: http.StatusOK, expectedBody: `"rules":1`},
+		{
+			name:           "reload failed",
+			method:         "POST",
+			path:           "/admin/routing/reload",
+			reloadErr:      errors.New("invalid rule"),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid rule",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := &MockRoutingRules{
+				rules:     []config.RoutingRule{{Match: config.RoutingMatch{Topic: "orders"}, Topics: []string{"orders.v2"}}},
+				reloadErr: tt.reloadErr,
+			}
+			handler := NewRoutingHandler(rules, logger)
+
+			router := gin.New()
+			router.GET("/admin/routing/rules", handler.ListRules)
+			router.GET("/admin/routing/aliases", handler.ListAliases)
+			router.POST("/admin/routing/reload", handler.ReloadRules)
+
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if !strings.Contains(w.Body.String(), tt.expectedBody) {
+				t.Errorf("Expected body to contain %s, got %s", tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
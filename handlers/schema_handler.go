@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"kafkaGateway/models"
+	"kafkaGateway/requestid"
+	"kafkaGateway/schema"
+	"kafkaGateway/utils"
+)
+
+// Интерфейс для хранилища схем топиков, чтобы можно было использовать мок
+type SchemaStoreInterface interface {
+	Register(topic, schemaType string, raw []byte) (*models.SchemaVersion, error)
+	Configure(topic, compatibility string, pinnedVersion *int) (*models.TopicSchemaInfo, error)
+	Delete(topic string) error
+	Get(topic string) (json.RawMessage, error)
+	Topics() []models.TopicSchemaInfo
+	Versions(topic string) ([]models.SchemaVersion, error)
+	Version(topic string, version int) (*models.SchemaVersion, error)
+	Diff(topic string, from, to int) (*models.SchemaDiff, error)
+}
+
+type SchemaHandler struct {
+	store  SchemaStoreInterface
+	logger *zap.Logger
+}
+
+func NewSchemaHandler(store SchemaStoreInterface, logger *zap.Logger) *SchemaHandler {
+	return &SchemaHandler{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// ListSchemas возвращает сводку по схемам всех топиков
+func (sh *SchemaHandler) ListSchemas(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"topics":    sh.store.Topics(),
+		"timestamp": time.Now().Unix(),
+	})
+}
+
+// GetSchema возвращает активную версию схемы топика
+func (sh *SchemaHandler) GetSchema(c *gin.Context) {
+	raw, err := sh.store.Get(c.Param("topic"))
+	if err != nil {
+		sh.respondError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/schema+json", raw)
+}
+
+// PutSchema регистрирует новую версию JSON Schema топика из тела запроса
+func (sh *SchemaHandler) PutSchema(c *gin.Context) {
+	raw, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body: " + err.Error()})
+		return
+	}
+
+	sh.register(c, models.SchemaTypeJSON, raw)
+}
+
+// RegisterVersion регистрирует новую версию схемы топика указанного типа
+func (sh *SchemaHandler) RegisterVersion(c *gin.Context) {
+	var req models.RegisterSchemaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format: " + err.Error()})
+		return
+	}
+
+	sh.register(c, req.Type, req.Schema)
+}
+
+// ListVersions возвращает все версии схемы топика
+func (sh *SchemaHandler) ListVersions(c *gin.Context) {
+	versions, err := sh.store.Versions(c.Param("topic"))
+	if err != nil {
+		sh.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"topic":    c.Param("topic"),
+		"versions": versions,
+	})
+}
+
+// GetVersion возвращает версию схемы топика (номер или latest для активной версии)
+func (sh *SchemaHandler) GetVersion(c *gin.Context) {
+	version, ok := parseSchemaVersion(c, c.Param("version"))
+	if !ok {
+		return
+	}
+
+	schemaVersion, err := sh.store.Version(c.Param("topic"), version)
+	if err != nil {
+		sh.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schemaVersion)
+}
+
+// DiffVersions возвращает отличия между версиями from и to (по умолчанию - активная версия)
+func (sh *SchemaHandler) DiffVersions(c *gin.Context) {
+	from, ok := parseSchemaVersion(c, c.Query("from"))
+	if !ok {
+		return
+	}
+	to, ok := parseSchemaVersion(c, c.Query("to"))
+	if !ok {
+		return
+	}
+
+	diff, err := sh.store.Diff(c.Param("topic"), from, to)
+	if err != nil {
+		sh.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// Configure меняет режим совместимости и закрепленную версию схемы топика
+func (sh *SchemaHandler) Configure(c *gin.Context) {
+	topic := c.Param("topic")
+
+	var req models.SchemaConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format: " + err.Error()})
+		return
+	}
+
+	info, err := sh.store.Configure(topic, req.Compatibility, req.PinnedVersion)
+	if err != nil {
+		sh.respondError(c, err)
+		return
+	}
+
+	requestid.Logger(c.Request.Context(), sh.logger).Info("Schema config changed via admin API",
+		zap.String("topic", topic),
+		zap.String("compatibility", info.Compatibility),
+		zap.Int("pinned_version", info.PinnedVersion),
+		auditKey(c))
+
+	c.JSON(http.StatusOK, info)
+}
+
+// DeleteSchema удаляет все версии схемы топика
+func (sh *SchemaHandler) DeleteSchema(c *gin.Context) {
+	topic := c.Param("topic")
+
+	if err := sh.store.Delete(topic); err != nil {
+		sh.respondError(c, err)
+		return
+	}
+
+	requestid.Logger(c.Request.Context(), sh.logger).Info("Schema deleted via admin API",
+		zap.String("topic", topic),
+		auditKey(c))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"topic":   topic,
+	})
+}
+
+func (sh *SchemaHandler) register(c *gin.Context, schemaType string, raw []byte) {
+	topic := c.Param("topic")
+	if !utils.IsValidTopic(topic) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid topic name"})
+		return
+	}
+
+	version, err := sh.store.Register(topic, schemaType, raw)
+	if err != nil {
+		sh.respondError(c, err)
+		return
+	}
+
+	requestid.Logger(c.Request.Context(), sh.logger).Info("Schema registered via admin API",
+		zap.String("topic", topic),
+		zap.Int("version", version.Version),
+		auditKey(c))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"topic":   topic,
+		"version": version.Version,
+	})
+}
+
+// parseSchemaVersion разбирает номер версии; пустое значение и latest означают активную версию
+func parseSchemaVersion(c *gin.Context, value string) (int, bool) {
+	if value == "" || value == "latest" {
+		return 0, true
+	}
+
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schema version: " + value})
+		return 0, false
+	}
+	return version, true
+}
+
+func (sh *SchemaHandler) respondError(c *gin.Context, err error) {
+	var compatibilityErr *schema.CompatibilityError
+	switch {
+	case errors.As(err, &compatibilityErr):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":         "Schema is not " + compatibilityErr.Compatibility + " compatible",
+			"compatibility": compatibilityErr.Compatibility,
+			"problems":      compatibilityErr.Problems,
+		})
+	case errors.Is(err, schema.ErrSchemaNotFound), errors.Is(err, schema.ErrSchemaVersionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, schema.ErrInvalidSchema):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		requestid.Logger(c.Request.Context(), sh.logger).Error("Schema request failed", zap.String("path", c.FullPath()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
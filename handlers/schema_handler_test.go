@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"kafkaGateway/models"
+	"kafkaGateway/schema"
+)
+
+func newSchemaRouter(store SchemaStoreInterface) *gin.Engine {
+	logger, _ := zap.NewDevelopment()
+	gin.SetMode(gin.TestMode)
+
+	handler := NewSchemaHandler(store, logger)
+	router := gin.New()
+	router.GET("/admin/schemas", handler.ListSchemas)
+	router.GET("/admin/schemas/:topic", handler.GetSchema)
+	router.PUT("/admin/schemas/:topic", handler.PutSchema)
+	router.DELETE("/admin/schemas/:topic", handler.DeleteSchema)
+	router.PUT("/admin/schemas/:topic/config", handler.Configure)
+	router.GET("/admin/schemas/:topic/diff", handler.DiffVersions)
+	router.GET("/admin/schemas/:topic/versions", handler.ListVersions)
+	router.POST("/admin/schemas/:topic/versions", handler.RegisterVersion)
+	router.GET("/admin/schemas/:topic/versions/:version", handler.GetVersion)
+	return router
+}
+
+func TestSchemaHandler(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	router := newSchemaRouter(schema.NewStore("", models.CompatibilityBackward, logger))
+
+	// Шаги выполняются последовательно над одним хранилищем
+	steps := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "get missing schema",
+			method:         "GET",
+			path:           "/admin/schemas/orders",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "put invalid schema",
+			method:         "PUT",
+			path:           "/admin/schemas/orders",
+			body:           `{"type": 42}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "put schema for invalid topic",
+			method:         "PUT",
+			path:           "/admin/schemas/.orders",
+			body:           `{"type": "object"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "put schema",
+			method:         "PUT",
+			path:           "/admin/schemas/orders",
+			body:           `{"type": "object"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "get schema",
+			method:         "GET",
+			path:           "/admin/schemas/orders",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"type":"object"}`,
+		},
+		{
+			name:           "register incompatible version",
+			method:         "POST",
+			path:           "/admin/schemas/orders/versions",
+			body:           `{"type": "JSON", "schema": {"type": "object", "required": ["id"]}}`,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "register compatible version",
+			method:         "POST",
+			path:           "/admin/schemas/orders/versions",
+			body:           `{"type": "JSON", "schema": {"type": "object", "properties": {"id": {"type": "string"}}}}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "register unknown schema type",
+			method:         "POST",
+			path:           "/admin/schemas/orders/versions",
+			body:           `{"type": "XML", "schema": {}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "list versions",
+			method:         "GET",
+			path:           "/admin/schemas/orders/versions",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "get version",
+			method:         "GET",
+			path:           "/admin/schemas/orders/versions/1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "get missing version",
+			method:         "GET",
+			path:           "/admin/schemas/orders/versions/9",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "diff versions",
+			method:         "GET",
+			path:           "/admin/schemas/orders/diff?from=1&to=2",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "diff invalid version",
+			method:         "GET",
+			path:           "/admin/schemas/orders/diff?from=first",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "pin version",
+			method:         "PUT",
+			path:           "/admin/schemas/orders/config",
+			body:           `{"compatibility": "FULL", "pinned_version": 1}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "get pinned schema",
+			method:         "GET",
+			path:           "/admin/schemas/orders",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"type":"object"}`,
+		},
+		{
+			name:           "invalid compatibility",
+			method:         "PUT",
+			path:           "/admin/schemas/orders/config",
+			body:           `{"compatibility": "SOMETIMES"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "list schemas",
+			method:         "GET",
+			path:           "/admin/schemas",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "delete schema",
+			method:         "DELETE",
+			path:           "/admin/schemas/orders",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "delete missing schema",
+			method:         "DELETE",
+			path:           "/admin/schemas/orders",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, step := range steps {
+		req, _ := http.NewRequest(step.method, step.path, bytes.NewBufferString(step.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != step.expectedStatus {
+			t.Errorf("%s: expected status %d, got %d. Response body: %s", step.name, step.expectedStatus, w.Code, w.Body.String())
+		}
+
+		if step.expectedBody != "" && w.Body.String() != step.expectedBody {
+			t.Errorf("%s: expected body %s, got %s", step.name, step.expectedBody, w.Body.String())
+		}
+	}
+}
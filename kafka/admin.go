@@ -0,0 +1,254 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+
+	"kafkaGateway/models"
+)
+
+var (
+	// ErrTopicNotFound возвращается, если топик не существует в кластере
+	ErrTopicNotFound = errors.New("topic not found")
+	// ErrTopicAlreadyExists возвращается при попытке создать существующий топик
+	ErrTopicAlreadyExists = errors.New("topic already exists")
+)
+
+// ClientInterface определяет административные вызовы kafka.Client
+type ClientInterface interface {
+	Metadata(ctx context.Context, req *kafka.MetadataRequest) (*kafka.MetadataResponse, error)
+	DescribeConfigs(ctx context.Context, req *kafka.DescribeConfigsRequest) (*kafka.DescribeConfigsResponse, error)
+	CreateTopics(ctx context.Context, req *kafka.CreateTopicsRequest) (*kafka.CreateTopicsResponse, error)
+	CreatePartitions(ctx context.Context, req *kafka.CreatePartitionsRequest) (*kafka.CreatePartitionsResponse, error)
+	DeleteTopics(ctx context.Context, req *kafka.DeleteTopicsRequest) (*kafka.DeleteTopicsResponse, error)
+	ListGroups(ctx context.Context, req *kafka.ListGroupsRequest) (*kafka.ListGroupsResponse, error)
+	DescribeGroups(ctx context.Context, req *kafka.DescribeGroupsRequest) (*kafka.DescribeGroupsResponse, error)
+	OffsetFetch(ctx context.Context, req *kafka.OffsetFetchRequest) (*kafka.OffsetFetchResponse, error)
+	ListOffsets(ctx context.Context, req *kafka.ListOffsetsRequest) (*kafka.ListOffsetsResponse, error)
+	OffsetCommit(ctx context.Context, req *kafka.OffsetCommitRequest) (*kafka.OffsetCommitResponse, error)
+}
+
+// Admin выполняет административные операции над топиками
+type Admin struct {
+	client ClientInterface
+	logger *zap.Logger
+}
+
+func NewAdmin(brokers string, logger *zap.Logger) *Admin {
+	client := &kafka.Client{
+		Addr:    kafka.TCP(brokers),
+		Timeout: 10 * time.Second,
+	}
+
+	return &Admin{
+		client: client,
+		logger: logger,
+	}
+}
+
+// ListTopics возвращает список топиков кластера с количеством партиций и реплик
+func (a *Admin) ListTopics(ctx context.Context) ([]models.TopicInfo, error) {
+	resp, err := a.client.Metadata(ctx, &kafka.MetadataRequest{})
+	if err != nil {
+		a.logger.Error("Failed to fetch topic metadata", zap.Error(err))
+		return nil, err
+	}
+
+	topics := make([]models.TopicInfo, 0, len(resp.Topics))
+	for _, t := range resp.Topics {
+		if t.Error != nil {
+			continue
+		}
+		topics = append(topics, topicInfo(t))
+	}
+
+	sort.Slice(topics, func(i, j int) bool {
+		return topics[i].Name < topics[j].Name
+	})
+
+	return topics, nil
+}
+
+// DescribeTopic возвращает информацию о топике и его конфигурацию
+func (a *Admin) DescribeTopic(ctx context.Context, topic string) (*models.TopicDescription, error) {
+	meta, err := a.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{topic}})
+	if err != nil {
+		a.logger.Error("Failed to fetch topic metadata", zap.String("topic", topic), zap.Error(err))
+		return nil, err
+	}
+	if len(meta.Topics) == 0 || errors.Is(meta.Topics[0].Error, kafka.UnknownTopicOrPartition) {
+		return nil, ErrTopicNotFound
+	}
+	if meta.Topics[0].Error != nil {
+		return nil, meta.Topics[0].Error
+	}
+
+	resp, err := a.client.DescribeConfigs(ctx, &kafka.DescribeConfigsRequest{
+		Resources: []kafka.DescribeConfigRequestResource{{
+			ResourceType: kafka.ResourceTypeTopic,
+			ResourceName: topic,
+		}},
+	})
+	if err != nil {
+		a.logger.Error("Failed to describe topic configs", zap.String("topic", topic), zap.Error(err))
+		return nil, err
+	}
+
+	description := &models.TopicDescription{
+		TopicInfo: topicInfo(meta.Topics[0]),
+		Configs:   []models.TopicConfigEntry{},
+	}
+	for _, resource := range resp.Resources {
+		if resource.Error != nil {
+			return nil, resource.Error
+		}
+		for _, entry := range resource.ConfigEntries {
+			description.Configs = append(description.Configs, models.TopicConfigEntry{
+				Name:        entry.ConfigName,
+				Value:       entry.ConfigValue,
+				ReadOnly:    entry.ReadOnly,
+				IsDefault:   entry.IsDefault,
+				IsSensitive: entry.IsSensitive,
+			})
+		}
+	}
+
+	sort.Slice(description.Configs, func(i, j int) bool {
+		return description.Configs[i].Name < description.Configs[j].Name
+	})
+
+	return description, nil
+}
+
+// CreateTopic создает топик с указанным числом партиций, фактором репликации и конфигурацией
+func (a *Admin) CreateTopic(ctx context.Context, req models.CreateTopicRequest) error {
+	entries := make([]kafka.ConfigEntry, 0, len(req.Configs))
+	for name, value := range req.Configs {
+		entries = append(entries, kafka.ConfigEntry{ConfigName: name, ConfigValue: value})
+	}
+
+	resp, err := a.client.CreateTopics(ctx, &kafka.CreateTopicsRequest{
+		Topics: []kafka.TopicConfig{{
+			Topic:             req.Name,
+			NumPartitions:     req.Partitions,
+			ReplicationFactor: req.ReplicationFactor,
+			ConfigEntries:     entries,
+		}},
+	})
+	if err != nil {
+		a.logger.Error("Failed to create topic", zap.String("topic", req.Name), zap.Error(err))
+		return err
+	}
+
+	if err := resp.Errors[req.Name]; err != nil {
+		if errors.Is(err, kafka.TopicAlreadyExists) {
+			return ErrTopicAlreadyExists
+		}
+		return err
+	}
+
+	a.logger.Info("Topic created",
+		zap.String("topic", req.Name),
+		zap.Int("partitions", req.Partitions),
+		zap.Int("replication_factor", req.ReplicationFactor))
+
+	return nil
+}
+
+// CreatePartitions увеличивает число партиций топика до count
+func (a *Admin) CreatePartitions(ctx context.Context, topic string, count int) error {
+	resp, err := a.client.CreatePartitions(ctx, &kafka.CreatePartitionsRequest{
+		Topics: []kafka.TopicPartitionsConfig{{
+			Name:  topic,
+			Count: int32(count),
+		}},
+	})
+	if err != nil {
+		a.logger.Error("Failed to create partitions", zap.String("topic", topic), zap.Error(err))
+		return err
+	}
+
+	if err := resp.Errors[topic]; err != nil {
+		if errors.Is(err, kafka.UnknownTopicOrPartition) {
+			return ErrTopicNotFound
+		}
+		return err
+	}
+
+	a.logger.Info("Topic partitions updated", zap.String("topic", topic), zap.Int("partitions", count))
+
+	return nil
+}
+
+// DeleteTopic удаляет топик
+func (a *Admin) DeleteTopic(ctx context.Context, topic string) error {
+	resp, err := a.client.DeleteTopics(ctx, &kafka.DeleteTopicsRequest{Topics: []string{topic}})
+	if err != nil {
+		a.logger.Error("Failed to delete topic", zap.String("topic", topic), zap.Error(err))
+		return err
+	}
+
+	if err := resp.Errors[topic]; err != nil {
+		if errors.Is(err, kafka.UnknownTopicOrPartition) {
+			return ErrTopicNotFound
+		}
+		return err
+	}
+
+	a.logger.Info("Topic deleted", zap.String("topic", topic))
+
+	return nil
+}
+
+// TopicExists проверяет, существует ли топик в кластере
+func (a *Admin) TopicExists(ctx context.Context, topic string) (bool, error) {
+	resp, err := a.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{topic}})
+	if err != nil {
+		a.logger.Error("Failed to fetch topic metadata", zap.String("topic", topic), zap.Error(err))
+		return false, err
+	}
+
+	for _, t := range resp.Topics {
+		if t.Name != topic {
+			continue
+		}
+		if errors.Is(t.Error, kafka.UnknownTopicOrPartition) {
+			return false, nil
+		}
+		return t.Error == nil, t.Error
+	}
+
+	return false, nil
+}
+
+// TopicNames возвращает имена топиков кластера
+func (a *Admin) TopicNames(ctx context.Context) ([]string, error) {
+	topics, err := a.ListTopics(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(topics))
+	for _, t := range topics {
+		names = append(names, t.Name)
+	}
+
+	return names, nil
+}
+
+func topicInfo(t kafka.Topic) models.TopicInfo {
+	info := models.TopicInfo{
+		Name:       t.Name,
+		Partitions: len(t.Partitions),
+		Internal:   t.Internal,
+	}
+	if len(t.Partitions) > 0 {
+		info.ReplicationFactor = len(t.Partitions[0].Replicas)
+	}
+	return info
+}
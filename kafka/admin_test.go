@@ -0,0 +1,283 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+
+	"kafkaGateway/models"
+)
+
+// Мок-объект для административного клиента
+type MockClient struct {
+	MetadataFunc         func(ctx context.Context, req *kafka.MetadataRequest) (*kafka.MetadataResponse, error)
+	DescribeConfigsFunc  func(ctx context.Context, req *kafka.DescribeConfigsRequest) (*kafka.DescribeConfigsResponse, error)
+	CreateTopicsFunc     func(ctx context.Context, req *kafka.CreateTopicsRequest) (*kafka.CreateTopicsResponse, error)
+	CreatePartitionsFunc func(ctx context.Context, req *kafka.CreatePartitionsRequest) (*kafka.CreatePartitionsResponse, error)
+	DeleteTopicsFunc     func(ctx context.Context, req *kafka.DeleteTopicsRequest) (*kafka.DeleteTopicsResponse, error)
+	ListGroupsFunc       func(ctx context.Context, req *kafka.ListGroupsRequest) (*kafka.ListGroupsResponse, error)
+	DescribeGroupsFunc   func(ctx context.Context, req *kafka.DescribeGroupsRequest) (*kafka.DescribeGroupsResponse, error)
+	OffsetFetchFunc      func(ctx context.Context, req *kafka.OffsetFetchRequest) (*kafka.OffsetFetchResponse, error)
+	ListOffsetsFunc      func(ctx context.Context, req *kafka.ListOffsetsRequest) (*kafka.ListOffsetsResponse, error)
+	OffsetCommitFunc     func(ctx context.Context, req *kafka.OffsetCommitRequest) (*kafka.OffsetCommitResponse, error)
+}
+
+func (m *MockClient) Metadata(ctx context.Context, req *kafka.MetadataRequest) (*kafka.MetadataResponse, error) {
+	if m.MetadataFunc != nil {
+		return m.MetadataFunc(ctx, req)
+	}
+	return &kafka.MetadataResponse{}, nil
+}
+
+func (m *MockClient) DescribeConfigs(ctx context.Context, req *kafka.DescribeConfigsRequest) (*kafka.DescribeConfigsResponse, error) {
+	if m.DescribeConfigsFunc != nil {
+		return m.DescribeConfigsFunc(ctx, req)
+	}
+	return &kafka.DescribeConfigsResponse{}, nil
+}
+
+func (m *MockClient) CreateTopics(ctx context.Context, req *kafka.CreateTopicsRequest) (*kafka.CreateTopicsResponse, error) {
+	if m.CreateTopicsFunc != nil {
+		return m.CreateTopicsFunc(ctx, req)
+	}
+	return &kafka.CreateTopicsResponse{}, nil
+}
+
+func (m *MockClient) CreatePartitions(ctx context.Context, req *kafka.CreatePartitionsRequest) (*kafka.CreatePartitionsResponse, error) {
+	if m.CreatePartitionsFunc != nil {
+		return m.CreatePartitionsFunc(ctx, req)
+	}
+	return &kafka.CreatePartitionsResponse{}, nil
+}
+
+func (m *MockClient) DeleteTopics(ctx context.Context, req *kafka.DeleteTopicsRequest) (*kafka.DeleteTopicsResponse, error) {
+	if m.DeleteTopicsFunc != nil {
+		return m.DeleteTopicsFunc(ctx, req)
+	}
+	return &kafka.DeleteTopicsResponse{}, nil
+}
+
+func (m *MockClient) ListGroups(ctx context.Context, req *kafka.ListGroupsRequest) (*kafka.ListGroupsResponse, error) {
+	if m.ListGroupsFunc != nil {
+		return m.ListGroupsFunc(ctx, req)
+	}
+	return &kafka.ListGroupsResponse{}, nil
+}
+
+func (m *MockClient) DescribeGroups(ctx context.Context, req *kafka.DescribeGroupsRequest) (*kafka.DescribeGroupsResponse, error) {
+	if m.DescribeGroupsFunc != nil {
+		return m.DescribeGroupsFunc(ctx, req)
+	}
+	return &kafka.DescribeGroupsResponse{}, nil
+}
+
+func (m *MockClient) OffsetFetch(ctx context.Context, req *kafka.OffsetFetchRequest) (*kafka.OffsetFetchResponse, error) {
+	if m.OffsetFetchFunc != nil {
+		return m.OffsetFetchFunc(ctx, req)
+	}
+	return &kafka.OffsetFetchResponse{}, nil
+}
+
+func (m *MockClient) ListOffsets(ctx context.Context, req *kafka.ListOffsetsRequest) (*kafka.ListOffsetsResponse, error) {
+	if m.ListOffsetsFunc != nil {
+		return m.ListOffsetsFunc(ctx, req)
+	}
+	return &kafka.ListOffsetsResponse{}, nil
+}
+
+func (m *MockClient) OffsetCommit(ctx context.Context, req *kafka.OffsetCommitRequest) (*kafka.OffsetCommitResponse, error) {
+	if m.OffsetCommitFunc != nil {
+		return m.OffsetCommitFunc(ctx, req)
+	}
+	return &kafka.OffsetCommitResponse{}, nil
+}
+
+func TestAdminListTopics(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	client := &MockClient{
+		MetadataFunc: func(ctx context.Context, req *kafka.MetadataRequest) (*kafka.MetadataResponse, error) {
+			return &kafka.MetadataResponse{
+				Topics: []kafka.Topic{
+					{
+						Name: "users",
+						Partitions: []kafka.Partition{
+							{ID: 0, Replicas: []kafka.Broker{{ID: 1}, {ID: 2}}},
+							{ID: 1, Replicas: []kafka.Broker{{ID: 2}, {ID: 3}}},
+						},
+					},
+					{
+						Name:       "orders",
+						Partitions: []kafka.Partition{{ID: 0, Replicas: []kafka.Broker{{ID: 1}}}},
+					},
+					{
+						Name:  "broken",
+						Error: kafka.LeaderNotAvailable,
+					},
+				},
+			}, nil
+		},
+	}
+
+	admin := &Admin{client: client, logger: logger}
+
+	topics, err := admin.ListTopics(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(topics) != 2 {
+		t.Fatalf("Expected 2 topics, got %d", len(topics))
+	}
+
+	// Топики отсортированы по имени
+	if topics[0].Name != "orders" || topics[1].Name != "users" {
+		t.Errorf("Expected topics [orders users], got %v", topics)
+	}
+
+	if topics[1].Partitions != 2 || topics[1].ReplicationFactor != 2 {
+		t.Errorf("Expected users to have 2 partitions and replication factor 2, got %+v", topics[1])
+	}
+}
+
+func TestAdminDescribeTopicNotFound(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	client := &MockClient{
+		MetadataFunc: func(ctx context.Context, req *kafka.MetadataRequest) (*kafka.MetadataResponse, error) {
+			return &kafka.MetadataResponse{
+				Topics: []kafka.Topic{{Name: req.Topics[0], Error: kafka.UnknownTopicOrPartition}},
+			}, nil
+		},
+	}
+
+	admin := &Admin{client: client, logger: logger}
+
+	_, err := admin.DescribeTopic(context.Background(), "missing")
+	if !errors.Is(err, ErrTopicNotFound) {
+		t.Errorf("Expected ErrTopicNotFound, got %v", err)
+	}
+}
+
+func TestAdminDescribeTopic(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	client := &MockClient{
+		MetadataFunc: func(ctx context.Context, req *kafka.MetadataRequest) (*kafka.MetadataResponse, error) {
+			return &kafka.MetadataResponse{
+				Topics: []kafka.Topic{{
+					Name:       "users",
+					Partitions: []kafka.Partition{{ID: 0, Replicas: []kafka.Broker{{ID: 1}}}},
+				}},
+			}, nil
+		},
+		DescribeConfigsFunc: func(ctx context.Context, req *kafka.DescribeConfigsRequest) (*kafka.DescribeConfigsResponse, error) {
+			if req.Resources[0].ResourceName != "users" {
+				t.Errorf("Expected configs for topic users, got %s", req.Resources[0].ResourceName)
+			}
+			return &kafka.DescribeConfigsResponse{
+				Resources: []kafka.DescribeConfigResponseResource{{
+					ResourceName: "users",
+					ConfigEntries: []kafka.DescribeConfigResponseConfigEntry{
+						{ConfigName: "retention.ms", ConfigValue: "604800000"},
+						{ConfigName: "cleanup.policy", ConfigValue: "delete", IsDefault: true},
+					},
+				}},
+			}, nil
+		},
+	}
+
+	admin := &Admin{client: client, logger: logger}
+
+	description, err := admin.DescribeTopic(context.Background(), "users")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if description.Partitions != 1 {
+		t.Errorf("Expected 1 partition, got %d", description.Partitions)
+	}
+
+	if len(description.Configs) != 2 || description.Configs[0].Name != "cleanup.policy" {
+		t.Errorf("Expected sorted configs, got %v", description.Configs)
+	}
+}
+
+func TestAdminCreateTopic(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	var received kafka.TopicConfig
+	client := &MockClient{
+		CreateTopicsFunc: func(ctx context.Context, req *kafka.CreateTopicsRequest) (*kafka.CreateTopicsResponse, error) {
+			received = req.Topics[0]
+			return &kafka.CreateTopicsResponse{}, nil
+		},
+	}
+
+	admin := &Admin{client: client, logger: logger}
+
+	err := admin.CreateTopic(context.Background(), models.CreateTopicRequest{
+		Name:              "users",
+		Partitions:        6,
+		ReplicationFactor: 3,
+		Configs:           map[string]string{"retention.ms": "86400000"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if received.Topic != "users" || received.NumPartitions != 6 || received.ReplicationFactor != 3 {
+		t.Errorf("Unexpected topic config: %+v", received)
+	}
+
+	if len(received.ConfigEntries) != 1 || received.ConfigEntries[0].ConfigName != "retention.ms" {
+		t.Errorf("Expected retention.ms config entry, got %v", received.ConfigEntries)
+	}
+}
+
+func TestAdminCreateTopicAlreadyExists(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	client := &MockClient{
+		CreateTopicsFunc: func(ctx context.Context, req *kafka.CreateTopicsRequest) (*kafka.CreateTopicsResponse, error) {
+			return &kafka.CreateTopicsResponse{
+				Errors: map[string]error{"users": kafka.TopicAlreadyExists},
+			}, nil
+		},
+	}
+
+	admin := &Admin{client: client, logger: logger}
+
+	err := admin.CreateTopic(context.Background(), models.CreateTopicRequest{Name: "users", Partitions: 1, ReplicationFactor: 1})
+	if !errors.Is(err, ErrTopicAlreadyExists) {
+		t.Errorf("Expected ErrTopicAlreadyExists, got %v", err)
+	}
+}
+
+func TestAdminDeleteTopicNotFound(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	client := &MockClient{
+		DeleteTopicsFunc: func(ctx context.Context, req *kafka.DeleteTopicsRequest) (*kafka.DeleteTopicsResponse, error) {
+			return &kafka.DeleteTopicsResponse{
+				Errors: map[string]error{"missing": kafka.UnknownTopicOrPartition},
+			}, nil
+		},
+	}
+
+	admin := &Admin{client: client, logger: logger}
+
+	err := admin.DeleteTopic(context.Background(), "missing")
+	if !errors.Is(err, ErrTopicNotFound) {
+		t.Errorf("Expected ErrTopicNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,384 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+
+	"kafkaGateway/models"
+)
+
+var (
+	// ErrConsumerGroupNotFound возвращается, если группа потребителей не существует
+	ErrConsumerGroupNotFound = errors.New("consumer group not found")
+	// ErrConsumerGroupActive возвращается при попытке сбросить смещения активной группы
+	ErrConsumerGroupActive = errors.New("consumer group is active")
+	// ErrInvalidOffsetReset возвращается при некорректных параметрах сброса смещений
+	ErrInvalidOffsetReset = errors.New("invalid offset reset request")
+)
+
+// Состояния группы, в которых ее смещения можно менять
+const (
+	groupStateEmpty = "Empty"
+	groupStateDead  = "Dead"
+)
+
+// ListConsumerGroups возвращает список групп потребителей кластера
+func (a *Admin) ListConsumerGroups(ctx context.Context) ([]models.ConsumerGroupInfo, error) {
+	resp, err := a.client.ListGroups(ctx, &kafka.ListGroupsRequest{})
+	if err != nil {
+		a.logger.Error("Failed to list consumer groups", zap.Error(err))
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+
+	if len(resp.Groups) == 0 {
+		return []models.ConsumerGroupInfo{}, nil
+	}
+
+	groupIDs := make([]string, 0, len(resp.Groups))
+	for _, g := range resp.Groups {
+		groupIDs = append(groupIDs, g.GroupID)
+	}
+
+	described, err := a.client.DescribeGroups(ctx, &kafka.DescribeGroupsRequest{GroupIDs: groupIDs})
+	if err != nil {
+		a.logger.Error("Failed to describe consumer groups", zap.Error(err))
+		return nil, err
+	}
+
+	groups := make([]models.ConsumerGroupInfo, 0, len(described.Groups))
+	for _, g := range described.Groups {
+		groups = append(groups, models.ConsumerGroupInfo{
+			GroupID: g.GroupID,
+			State:   g.GroupState,
+			Members: len(g.Members),
+		})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].GroupID < groups[j].GroupID
+	})
+
+	return groups, nil
+}
+
+// DescribeConsumerGroup возвращает участников группы, ее смещения и отставание по партициям
+func (a *Admin) DescribeConsumerGroup(ctx context.Context, groupID string) (*models.ConsumerGroupDescription, error) {
+	group, err := a.describeGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	committed, err := a.client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{GroupID: groupID})
+	if err != nil {
+		a.logger.Error("Failed to fetch committed offsets", zap.String("group", groupID), zap.Error(err))
+		return nil, err
+	}
+	if committed.Error != nil {
+		return nil, committed.Error
+	}
+
+	if group.GroupState == groupStateDead && len(committed.Topics) == 0 {
+		return nil, ErrConsumerGroupNotFound
+	}
+
+	description := &models.ConsumerGroupDescription{
+		GroupID:    groupID,
+		State:      group.GroupState,
+		Members:    make([]models.ConsumerGroupMember, 0, len(group.Members)),
+		Partitions: []models.PartitionLag{},
+	}
+
+	// Владелец каждой партиции и все топики, которые читает группа
+	owners := make(map[string]map[int]string)
+	topics := make(map[string]bool)
+	for _, member := range group.Members {
+		assignments := make(map[string][]int)
+		for _, t := range member.MemberAssignments.Topics {
+			assignments[t.Topic] = t.Partitions
+			topics[t.Topic] = true
+			if owners[t.Topic] == nil {
+				owners[t.Topic] = make(map[int]string)
+			}
+			for _, p := range t.Partitions {
+				owners[t.Topic][p] = member.MemberID
+			}
+		}
+		description.Members = append(description.Members, models.ConsumerGroupMember{
+			MemberID:    member.MemberID,
+			ClientID:    member.ClientID,
+			ClientHost:  member.ClientHost,
+			Assignments: assignments,
+		})
+	}
+
+	commits := make(map[string]map[int]int64)
+	for topic, partitions := range committed.Topics {
+		topics[topic] = true
+		commits[topic] = make(map[int]int64, len(partitions))
+		for _, p := range partitions {
+			if p.Error == nil {
+				commits[topic][p.Partition] = p.CommittedOffset
+			}
+		}
+	}
+
+	for topic := range topics {
+		partitions, err := a.topicPartitions(ctx, topic)
+		if errors.Is(err, ErrTopicNotFound) {
+			// Топик удален, но смещения группы по нему еще хранятся
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		offsets, err := a.logOffsets(ctx, topic, partitions)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range partitions {
+			lag := models.PartitionLag{
+				Topic:           topic,
+				Partition:       p,
+				CommittedOffset: -1,
+				LogStartOffset:  offsets[p].FirstOffset,
+				LogEndOffset:    offsets[p].LastOffset,
+				MemberID:        owners[topic][p],
+			}
+
+			committedOffset, ok := commits[topic][p]
+			if ok && committedOffset >= 0 {
+				lag.CommittedOffset = committedOffset
+				lag.Lag = lag.LogEndOffset - committedOffset
+			} else {
+				// Без зафиксированного смещения группа прочитает все, что осталось в логе
+				lag.Lag = lag.LogEndOffset - lag.LogStartOffset
+			}
+			if lag.Lag < 0 {
+				lag.Lag = 0
+			}
+
+			description.TotalLag += lag.Lag
+			description.Partitions = append(description.Partitions, lag)
+		}
+	}
+
+	sort.Slice(description.Partitions, func(i, j int) bool {
+		pi, pj := description.Partitions[i], description.Partitions[j]
+		if pi.Topic != pj.Topic {
+			return pi.Topic < pj.Topic
+		}
+		return pi.Partition < pj.Partition
+	})
+
+	return description, nil
+}
+
+// DescribeAllConsumerGroups возвращает описание всех групп потребителей кластера
+func (a *Admin) DescribeAllConsumerGroups(ctx context.Context) ([]models.ConsumerGroupDescription, error) {
+	groups, err := a.ListConsumerGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptions := make([]models.ConsumerGroupDescription, 0, len(groups))
+	for _, g := range groups {
+		description, err := a.DescribeConsumerGroup(ctx, g.GroupID)
+		if errors.Is(err, ErrConsumerGroupNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		descriptions = append(descriptions, *description)
+	}
+
+	return descriptions, nil
+}
+
+// ResetConsumerGroupOffsets переставляет смещения неактивной группы на начало, конец,
+// момент времени или конкретное смещение
+func (a *Admin) ResetConsumerGroupOffsets(ctx context.Context, groupID string, req models.ResetOffsetsRequest) ([]models.PartitionOffset, error) {
+	group, err := a.describeGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if group.GroupState != groupStateEmpty && group.GroupState != groupStateDead {
+		return nil, fmt.Errorf("%w: state is %s", ErrConsumerGroupActive, group.GroupState)
+	}
+
+	partitions := req.Partitions
+	if len(partitions) == 0 {
+		partitions, err = a.topicPartitions(ctx, req.Topic)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	offsets, err := a.logOffsets(ctx, req.Topic, partitions)
+	if err != nil {
+		return nil, err
+	}
+
+	var timeOffsets map[int]kafka.PartitionOffsets
+	if req.Strategy == models.ResetToTimestamp {
+		if req.Timestamp == nil {
+			return nil, fmt.Errorf("%w: timestamp is required", ErrInvalidOffsetReset)
+		}
+		timeOffsets, err = a.listOffsets(ctx, req.Topic, partitions, func(p int) []kafka.OffsetRequest {
+			return []kafka.OffsetRequest{kafka.TimeOffsetOf(p, *req.Timestamp)}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if req.Strategy == models.ResetToOffset && req.Offset == nil {
+		return nil, fmt.Errorf("%w: offset is required", ErrInvalidOffsetReset)
+	}
+
+	commits := make([]kafka.OffsetCommit, 0, len(partitions))
+	result := make([]models.PartitionOffset, 0, len(partitions))
+	for _, p := range partitions {
+		bounds, ok := offsets[p]
+		if !ok {
+			return nil, fmt.Errorf("%w: partition %d does not exist", ErrInvalidOffsetReset, p)
+		}
+
+		var offset int64
+		switch req.Strategy {
+		case models.ResetToEarliest:
+			offset = bounds.FirstOffset
+		case models.ResetToLatest:
+			offset = bounds.LastOffset
+		case models.ResetToTimestamp:
+			// Если после момента времени сообщений нет, переходим в конец лога
+			offset = bounds.LastOffset
+			for o := range timeOffsets[p].Offsets {
+				if o >= 0 {
+					offset = o
+				}
+			}
+		case models.ResetToOffset:
+			offset = *req.Offset
+			if offset < bounds.FirstOffset {
+				offset = bounds.FirstOffset
+			}
+			if offset > bounds.LastOffset {
+				offset = bounds.LastOffset
+			}
+		default:
+			return nil, fmt.Errorf("%w: unknown strategy %q", ErrInvalidOffsetReset, req.Strategy)
+		}
+
+		commits = append(commits, kafka.OffsetCommit{Partition: p, Offset: offset})
+		result = append(result, models.PartitionOffset{Topic: req.Topic, Partition: p, Offset: offset})
+	}
+
+	resp, err := a.client.OffsetCommit(ctx, &kafka.OffsetCommitRequest{
+		GroupID:      groupID,
+		GenerationID: -1,
+		Topics:       map[string][]kafka.OffsetCommit{req.Topic: commits},
+	})
+	if err != nil {
+		a.logger.Error("Failed to commit offsets", zap.String("group", groupID), zap.Error(err))
+		return nil, err
+	}
+	for _, partitions := range resp.Topics {
+		for _, p := range partitions {
+			if p.Error != nil {
+				return nil, fmt.Errorf("partition %d: %w", p.Partition, p.Error)
+			}
+		}
+	}
+
+	a.logger.Info("Consumer group offsets reset",
+		zap.String("group", groupID),
+		zap.String("topic", req.Topic),
+		zap.String("strategy", req.Strategy))
+
+	return result, nil
+}
+
+func (a *Admin) describeGroup(ctx context.Context, groupID string) (*kafka.DescribeGroupsResponseGroup, error) {
+	resp, err := a.client.DescribeGroups(ctx, &kafka.DescribeGroupsRequest{GroupIDs: []string{groupID}})
+	if err != nil {
+		a.logger.Error("Failed to describe consumer group", zap.String("group", groupID), zap.Error(err))
+		return nil, err
+	}
+	if len(resp.Groups) == 0 {
+		return nil, ErrConsumerGroupNotFound
+	}
+
+	group := resp.Groups[0]
+	if group.Error != nil {
+		if errors.Is(group.Error, kafka.GroupIdNotFound) {
+			return nil, ErrConsumerGroupNotFound
+		}
+		return nil, group.Error
+	}
+
+	return &group, nil
+}
+
+// topicPartitions возвращает номера партиций топика
+func (a *Admin) topicPartitions(ctx context.Context, topic string) ([]int, error) {
+	meta, err := a.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{topic}})
+	if err != nil {
+		return nil, err
+	}
+	if len(meta.Topics) == 0 || errors.Is(meta.Topics[0].Error, kafka.UnknownTopicOrPartition) {
+		return nil, ErrTopicNotFound
+	}
+	if meta.Topics[0].Error != nil {
+		return nil, meta.Topics[0].Error
+	}
+
+	partitions := make([]int, 0, len(meta.Topics[0].Partitions))
+	for _, p := range meta.Topics[0].Partitions {
+		partitions = append(partitions, p.ID)
+	}
+	sort.Ints(partitions)
+
+	return partitions, nil
+}
+
+// logOffsets возвращает начальное и конечное смещения партиций топика
+func (a *Admin) logOffsets(ctx context.Context, topic string, partitions []int) (map[int]kafka.PartitionOffsets, error) {
+	return a.listOffsets(ctx, topic, partitions, func(p int) []kafka.OffsetRequest {
+		return []kafka.OffsetRequest{kafka.FirstOffsetOf(p), kafka.LastOffsetOf(p)}
+	})
+}
+
+func (a *Admin) listOffsets(ctx context.Context, topic string, partitions []int, requests func(p int) []kafka.OffsetRequest) (map[int]kafka.PartitionOffsets, error) {
+	offsetRequests := make([]kafka.OffsetRequest, 0, len(partitions)*2)
+	for _, p := range partitions {
+		offsetRequests = append(offsetRequests, requests(p)...)
+	}
+
+	resp, err := a.client.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics: map[string][]kafka.OffsetRequest{topic: offsetRequests},
+	})
+	if err != nil {
+		a.logger.Error("Failed to list offsets", zap.String("topic", topic), zap.Error(err))
+		return nil, err
+	}
+
+	result := make(map[int]kafka.PartitionOffsets, len(partitions))
+	for _, p := range resp.Topics[topic] {
+		if p.Error != nil {
+			return nil, fmt.Errorf("partition %d: %w", p.Partition, p.Error)
+		}
+		result[p.Partition] = p
+	}
+
+	return result, nil
+}
@@ -0,0 +1,224 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+
+	"kafkaGateway/models"
+)
+
+// newConsumerGroupClient создает мок кластера с топиком orders из двух партиций
+// и группой billing в указанном состоянии
+func newConsumerGroupClient(state string) *MockClient {
+	return &MockClient{
+		MetadataFunc: func(ctx context.Context, req *kafka.MetadataRequest) (*kafka.MetadataResponse, error) {
+			if len(req.Topics) == 1 && req.Topics[0] != "orders" {
+				return &kafka.MetadataResponse{
+					Topics: []kafka.Topic{{Name: req.Topics[0], Error: kafka.UnknownTopicOrPartition}},
+				}, nil
+			}
+			return &kafka.MetadataResponse{
+				Topics: []kafka.Topic{{
+					Name:       "orders",
+					Partitions: []kafka.Partition{{ID: 0}, {ID: 1}},
+				}},
+			}, nil
+		},
+		DescribeGroupsFunc: func(ctx context.Context, req *kafka.DescribeGroupsRequest) (*kafka.DescribeGroupsResponse, error) {
+			group := kafka.DescribeGroupsResponseGroup{GroupID: req.GroupIDs[0], GroupState: state}
+			if state == "Stable" {
+				group.Members = []kafka.DescribeGroupsResponseMember{{
+					MemberID: "member-1",
+					ClientID: "billing-service",
+					MemberAssignments: kafka.DescribeGroupsResponseAssignments{
+						Topics: []kafka.GroupMemberTopic{{Topic: "orders", Partitions: []int{0, 1}}},
+					},
+				}}
+			}
+			return &kafka.DescribeGroupsResponse{Groups: []kafka.DescribeGroupsResponseGroup{group}}, nil
+		},
+		OffsetFetchFunc: func(ctx context.Context, req *kafka.OffsetFetchRequest) (*kafka.OffsetFetchResponse, error) {
+			return &kafka.OffsetFetchResponse{
+				Topics: map[string][]kafka.OffsetFetchPartition{
+					"orders": {{Partition: 0, CommittedOffset: 90}},
+				},
+			}, nil
+		},
+		ListOffsetsFunc: func(ctx context.Context, req *kafka.ListOffsetsRequest) (*kafka.ListOffsetsResponse, error) {
+			offsets := map[int]*kafka.PartitionOffsets{}
+			for _, r := range req.Topics["orders"] {
+				p, ok := offsets[r.Partition]
+				if !ok {
+					p = &kafka.PartitionOffsets{Partition: r.Partition, FirstOffset: -1, LastOffset: -1, Offsets: map[int64]time.Time{}}
+					offsets[r.Partition] = p
+				}
+				switch r.Timestamp {
+				case kafka.FirstOffset:
+					p.FirstOffset = 10
+				case kafka.LastOffset:
+					p.LastOffset = 100
+				default:
+					p.Offsets[50] = time.UnixMilli(r.Timestamp)
+				}
+			}
+			resp := &kafka.ListOffsetsResponse{Topics: map[string][]kafka.PartitionOffsets{}}
+			for _, p := range offsets {
+				resp.Topics["orders"] = append(resp.Topics["orders"], *p)
+			}
+			return resp, nil
+		},
+	}
+}
+
+func TestAdminDescribeConsumerGroup(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	admin := &Admin{client: newConsumerGroupClient("Stable"), logger: logger}
+
+	description, err := admin.DescribeConsumerGroup(context.Background(), "billing")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if description.State != "Stable" || len(description.Members) != 1 {
+		t.Errorf("Unexpected group description: %+v", description)
+	}
+
+	if len(description.Partitions) != 2 {
+		t.Fatalf("Expected 2 partitions, got %d", len(description.Partitions))
+	}
+
+	// Партиция 0: зафиксировано 90 из 100
+	p0 := description.Partitions[0]
+	if p0.CommittedOffset != 90 || p0.LogEndOffset != 100 || p0.Lag != 10 || p0.MemberID != "member-1" {
+		t.Errorf("Unexpected partition 0 lag: %+v", p0)
+	}
+
+	// Партиция 1: смещение не зафиксировано, отставание равно размеру лога
+	p1 := description.Partitions[1]
+	if p1.CommittedOffset != -1 || p1.Lag != 90 {
+		t.Errorf("Unexpected partition 1 lag: %+v", p1)
+	}
+
+	if description.TotalLag != 100 {
+		t.Errorf("Expected total lag 100, got %d", description.TotalLag)
+	}
+}
+
+func TestAdminDescribeConsumerGroupNotFound(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	client := newConsumerGroupClient("Dead")
+	client.OffsetFetchFunc = func(ctx context.Context, req *kafka.OffsetFetchRequest) (*kafka.OffsetFetchResponse, error) {
+		return &kafka.OffsetFetchResponse{}, nil
+	}
+	admin := &Admin{client: client, logger: logger}
+
+	_, err := admin.DescribeConsumerGroup(context.Background(), "missing")
+	if !errors.Is(err, ErrConsumerGroupNotFound) {
+		t.Errorf("Expected ErrConsumerGroupNotFound, got %v", err)
+	}
+}
+
+func TestAdminResetConsumerGroupOffsets(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	offset := int64(500)
+	timestamp := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		state       string
+		request     models.ResetOffsetsRequest
+		expected    int64
+		expectedErr error
+	}{
+		{
+			name:     "earliest",
+			state:    "Empty",
+			request:  models.ResetOffsetsRequest{Topic: "orders", Strategy: models.ResetToEarliest},
+			expected: 10,
+		},
+		{
+			name:     "latest",
+			state:    "Empty",
+			request:  models.ResetOffsetsRequest{Topic: "orders", Strategy: models.ResetToLatest},
+			expected: 100,
+		},
+		{
+			name:     "timestamp",
+			state:    "Empty",
+			request:  models.ResetOffsetsRequest{Topic: "orders", Strategy: models.ResetToTimestamp, Timestamp: &timestamp},
+			expected: 50,
+		},
+		{
+			name:     "offset beyond log end is clamped",
+			state:    "Empty",
+			request:  models.ResetOffsetsRequest{Topic: "orders", Strategy: models.ResetToOffset, Offset: &offset},
+			expected: 100,
+		},
+		{
+			name:        "offset strategy without offset",
+			state:       "Empty",
+			request:     models.ResetOffsetsRequest{Topic: "orders", Strategy: models.ResetToOffset},
+			expectedErr: ErrInvalidOffsetReset,
+		},
+		{
+			name:        "active group",
+			state:       "Stable",
+			request:     models.ResetOffsetsRequest{Topic: "orders", Strategy: models.ResetToEarliest},
+			expectedErr: ErrConsumerGroupActive,
+		},
+		{
+			name:        "unknown topic",
+			state:       "Empty",
+			request:     models.ResetOffsetsRequest{Topic: "missing", Strategy: models.ResetToEarliest},
+			expectedErr: ErrTopicNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var committed []kafka.OffsetCommit
+			client := newConsumerGroupClient(tt.state)
+			client.OffsetCommitFunc = func(ctx context.Context, req *kafka.OffsetCommitRequest) (*kafka.OffsetCommitResponse, error) {
+				committed = req.Topics["orders"]
+				return &kafka.OffsetCommitResponse{}, nil
+			}
+			admin := &Admin{client: client, logger: logger}
+
+			offsets, err := admin.ResetConsumerGroupOffsets(context.Background(), "billing", tt.request)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+				}
+				if committed != nil {
+					t.Errorf("Expected no offsets to be committed, got %v", committed)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if len(offsets) != 2 || len(committed) != 2 {
+				t.Fatalf("Expected offsets for 2 partitions, got %v", offsets)
+			}
+
+			for _, c := range committed {
+				if c.Offset != tt.expected {
+					t.Errorf("Expected partition %d offset %d, got %d", c.Partition, tt.expected, c.Offset)
+				}
+			}
+		})
+	}
+}
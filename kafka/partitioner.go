@@ -0,0 +1,81 @@
+package kafka
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"github.com/segmentio/kafka-go"
+
+	"kafkaGateway/config"
+)
+
+// ErrInvalidPartition возвращается, если явно заданной партиции нет в топике
+var ErrInvalidPartition = errors.New("invalid partition")
+
+// partitionHint передается балансировщику в kafka.Message.WriterData: Writer не использует
+// поле Partition сообщения, партицию всегда выбирает Balancer
+type partitionHint struct {
+	partition int
+	// outOfRange отмечается балансировщиком, если в топике нет такой партиции
+	outOfRange atomic.Bool
+}
+
+// topicBalancer выбирает партицию по стратегии топика. Явно заданная партиция имеет приоритет
+type topicBalancer struct {
+	defaultBalancer kafka.Balancer
+	topics          map[string]kafka.Balancer
+}
+
+func newTopicBalancer(defaultPartitioner string, topics map[string]config.TopicConfig) *topicBalancer {
+	b := &topicBalancer{
+		defaultBalancer: newBalancer(defaultPartitioner),
+		topics:          make(map[string]kafka.Balancer),
+	}
+	for topic, topicConfig := range topics {
+		if topicConfig.Partitioner != "" {
+			b.topics[topic] = newBalancer(topicConfig.Partitioner)
+		}
+	}
+	return b
+}
+
+// newBalancer создает балансировщик стратегии. Имена проверяются при загрузке конфигурации,
+// для неизвестных используется hash
+func newBalancer(partitioner string) kafka.Balancer {
+	switch partitioner {
+	case config.PartitionerMurmur2:
+		return kafka.Murmur2Balancer{}
+	case config.PartitionerCRC32:
+		return kafka.CRC32Balancer{}
+	case config.PartitionerRoundRobin:
+		return &kafka.RoundRobin{}
+	case config.PartitionerLeastBytes:
+		return &kafka.LeastBytes{}
+	default:
+		// Для explicit сообщения без партиции отклоняются обработчиком до отправки
+		return &kafka.Hash{}
+	}
+}
+
+func (b *topicBalancer) Balance(msg kafka.Message, partitions ...int) int {
+	if hint, ok := msg.WriterData.(*partitionHint); ok {
+		if !containsPartition(partitions, hint.partition) {
+			hint.outOfRange.Store(true)
+		}
+		return hint.partition
+	}
+
+	if balancer, ok := b.topics[msg.Topic]; ok {
+		return balancer.Balance(msg, partitions...)
+	}
+	return b.defaultBalancer.Balance(msg, partitions...)
+}
+
+func containsPartition(partitions []int, partition int) bool {
+	for _, p := range partitions {
+		if p == partition {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+
+	"kafkaGateway/config"
+)
+
+func TestTopicBalancer(t *testing.T) {
+	balancer := newTopicBalancer(config.PartitionerHash, map[string]config.TopicConfig{
+		"java-orders": {Partitioner: config.PartitionerMurmur2},
+		"rdkafka":     {Partitioner: config.PartitionerCRC32},
+	})
+	partitions := []int{0, 1, 2, 3, 4, 5, 6, 7}
+
+	differs := false
+	for i := 0; i < 50; i++ {
+		key := []byte(fmt.Sprintf("user-%d", i))
+
+		murmur2 := balancer.Balance(kafka.Message{Topic: "java-orders", Key: key}, partitions...)
+		if expected := (kafka.Murmur2Balancer{}).Balance(kafka.Message{Key: key}, partitions...); murmur2 != expected {
+			t.Errorf("Expected murmur2 partition %d for key %s, got %d", expected, key, murmur2)
+		}
+
+		crc32 := balancer.Balance(kafka.Message{Topic: "rdkafka", Key: key}, partitions...)
+		if expected := (kafka.CRC32Balancer{}).Balance(kafka.Message{Key: key}, partitions...); crc32 != expected {
+			t.Errorf("Expected crc32 partition %d for key %s, got %d", expected, key, crc32)
+		}
+
+		hash := balancer.Balance(kafka.Message{Topic: "other", Key: key}, partitions...)
+		if hash != murmur2 {
+			differs = true
+		}
+	}
+	if !differs {
+		t.Errorf("Expected default hash balancer to differ from murmur2")
+	}
+
+	// Партиции DefaultPartitioner Java клиента для 8 партиций: toPositive(Utils.murmur2(key)) % 8,
+	// хэши ключей взяты из UtilsTest.testMurmur2 Apache Kafka
+	javaPartitions := []struct {
+		key       string
+		partition int
+	}{
+		{key: "21", partition: 4},                                               // murmur2 -973932308
+		{key: "foobar", partition: 6},                                           // murmur2 -790332482
+		{key: "a-little-bit-long-string", partition: 0},                         // murmur2 -985981536
+		{key: "a-little-bit-longer-string", partition: 3},                       // murmur2 -1486304829
+		{key: "lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8", partition: 5}, // murmur2 -58897971
+		{key: "abc", partition: 3},                                              // murmur2 479470107
+	}
+	for _, tt := range javaPartitions {
+		if partition := balancer.Balance(kafka.Message{Topic: "java-orders", Key: []byte(tt.key)}, partitions...); partition != tt.partition {
+			t.Errorf("Expected Java partition %d for key %s, got %d", tt.partition, tt.key, partition)
+		}
+	}
+
+	hint := &partitionHint{partition: 5}
+	if partition := balancer.Balance(kafka.Message{Topic: "java-orders", Key: []byte("a"), WriterData: hint}, partitions...); partition != 5 || hint.outOfRange.Load() {
+		t.Errorf("Expected explicit partition 5, got %d", partition)
+	}
+
+	hint = &partitionHint{partition: 8}
+	balancer.Balance(kafka.Message{Topic: "java-orders", WriterData: hint}, partitions...)
+	if !hint.outOfRange.Load() {
+		t.Errorf("Expected partition 8 to be out of range")
+	}
+}
+
+func TestProducerSendRecordPartition(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	balancer := newTopicBalancer(config.PartitionerHash, nil)
+	var chosen int
+	mockWriter := &MockWriter{
+		WriteMessagesFunc: func(ctx context.Context, msgs ...kafka.Message) error {
+			chosen = balancer.Balance(msgs[0], 0, 1, 2)
+			if chosen > 2 {
+				return kafka.WriteErrors{kafka.UnknownTopicOrPartition}
+			}
+			return nil
+		},
+	}
+	producer := &Producer{writer: mockWriter, logger: logger}
+
+	partition := 2
+	if err := producer.SendRecord("orders", Record{Value: []byte("v"), Partition: &partition}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if chosen != 2 {
+		t.Errorf("Expected partition 2, got %d", chosen)
+	}
+
+	partition = 3
+	err := producer.SendRecord("orders", Record{Value: []byte("v"), Partition: &partition})
+	if !errors.Is(err, ErrInvalidPartition) {
+		t.Errorf("Expected ErrInvalidPartition, got %v", err)
+	}
+}
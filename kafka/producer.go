@@ -2,10 +2,21 @@ package kafka
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+
+	"kafkaGateway/config"
+	"kafkaGateway/models"
+	"kafkaGateway/requestid"
+	"kafkaGateway/tracing"
 )
 
 // WriterInterface определяет интерфейс для Kafka Writer
@@ -14,20 +25,41 @@ type WriterInterface interface {
 	Close() error
 }
 
+// ErrMessageTooLarge возвращается, если запись превышает допустимый размер
+var ErrMessageTooLarge = errors.New("message too large")
+
+// Record запись для отправки в топик
+type Record struct {
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
+	// Partition явно заданная партиция; nil - партицию выбирает стратегия топика
+	Partition *int
+	// Time время записи; нулевое значение - время отправки
+	Time time.Time
+}
+
 type Producer struct {
 	writer WriterInterface
 	logger *zap.Logger
+
+	// Writer не ведет статистику по топикам, поэтому producer считает записи сам
+	statsMu    sync.Mutex
+	topicStats map[string]*models.TopicWriteStats
 }
 
 func NewProducer(brokers string, logger *zap.Logger) *Producer {
 	writer := &kafka.Writer{
-		Addr:                   kafka.TCP(brokers),
-		Balancer:               &kafka.Hash{},
-		WriteTimeout:           10 * time.Second,
-		ReadTimeout:            10 * time.Second,
-		RequiredAcks:           kafka.RequireAll,
-		MaxAttempts:            3,
-		AllowAutoTopicCreation: true,
+		Addr:         kafka.TCP(brokers),
+		Balancer:     &kafka.Hash{},
+		WriteTimeout: 10 * time.Second,
+		ReadTimeout:  10 * time.Second,
+		RequiredAcks: kafka.RequireAll,
+		MaxAttempts:  3,
+		// Синхронная запись ждет неполный пакет не дольше BatchTimeout
+		BatchTimeout: 10 * time.Millisecond,
+		// Топики создаются только через политику топиков или административный API
+		AllowAutoTopicCreation: false,
 		// Указываем топик как пустую строку, так как будем указывать его в каждом сообщении
 	}
 
@@ -37,6 +69,22 @@ func NewProducer(brokers string, logger *zap.Logger) *Producer {
 	}
 }
 
+// WithMaxMessageBytes задает максимальный размер записи, которую принимает Writer (по умолчанию 1 МиБ)
+func (p *Producer) WithMaxMessageBytes(maxBytes int64) *Producer {
+	if writer, ok := p.writer.(*kafka.Writer); ok {
+		writer.BatchBytes = maxBytes
+	}
+	return p
+}
+
+// WithPartitioners задает стратегию выбора партиции по умолчанию и стратегии топиков из конфигурации
+func (p *Producer) WithPartitioners(defaultPartitioner string, topics map[string]config.TopicConfig) *Producer {
+	if writer, ok := p.writer.(*kafka.Writer); ok {
+		writer.Balancer = newTopicBalancer(defaultPartitioner, topics)
+	}
+	return p
+}
+
 func (p *Producer) SendMessage(topic string, key, value []byte) error {
 	message := kafka.Message{
 		Topic: topic, // Указываем топик в сообщении
@@ -45,12 +93,15 @@ func (p *Producer) SendMessage(topic string, key, value []byte) error {
 		Time:  time.Now(),
 	}
 
-	err := p.writer.WriteMessages(context.Background(), message)
+	ctx, span := startSpan(topic, nil, 1)
+	err := p.writer.WriteMessages(ctx, message)
+	endSpan(span, err)
+	p.countSend(topic, message, err)
 	if err != nil {
 		p.logger.Error("Failed to send message to Kafka",
 			zap.String("topic", topic),
 			zap.Error(err))
-		return err
+		return wrapWriteError(topic, err)
 	}
 
 	p.logger.Info("Message sent to Kafka",
@@ -61,38 +112,278 @@ func (p *Producer) SendMessage(topic string, key, value []byte) error {
 }
 
 func (p *Producer) SendMessageWithHeaders(topic string, key, value []byte, headers map[string]string) error {
-	// Преобразуем map[string]string в []kafka.Header
-	kafkaHeaders := make([]kafka.Header, 0, len(headers))
-	for k, v := range headers {
-		kafkaHeaders = append(kafkaHeaders, kafka.Header{
-			Key:   k,
-			Value: []byte(v),
-		})
-	}
-
 	message := kafka.Message{
 		Topic:   topic, // Указываем топик в сообщении
 		Key:     key,
 		Value:   value,
-		Headers: kafkaHeaders,
+		Headers: toKafkaHeaders(headers),
 		Time:    time.Now(),
 	}
 
-	err := p.writer.WriteMessages(context.Background(), message)
+	ctx, span := startSpan(topic, headers, 1)
+	err := p.writer.WriteMessages(ctx, message)
+	endSpan(span, err)
+	p.countSend(topic, message, err)
 	if err != nil {
 		p.logger.Error("Failed to send message with headers to Kafka",
 			zap.String("topic", topic),
+			requestid.FromHeaders(headers),
 			zap.Error(err))
-		return err
+		return wrapWriteError(topic, err)
 	}
 
 	p.logger.Info("Message with headers sent to Kafka",
 		zap.String("topic", topic),
+		requestid.FromHeaders(headers),
 		zap.Int("value_length", len(value)))
 
 	return nil
 }
 
+// SendRecord отправляет запись, в том числе в явно заданную партицию и с заданным временем
+func (p *Producer) SendRecord(topic string, record Record) error {
+	message := toKafkaMessage(topic, record, time.Now())
+
+	ctx, span := startSpan(topic, record.Headers, 1)
+	err := p.writer.WriteMessages(ctx, message)
+	endSpan(span, err)
+	p.countSend(topic, message, err)
+	if err != nil {
+		p.logger.Error("Failed to send record to Kafka",
+			zap.String("topic", topic),
+			requestid.FromHeaders(record.Headers),
+			zap.Error(err))
+		return wrapRecordError(topic, message, err)
+	}
+
+	p.logger.Info("Record sent to Kafka",
+		zap.String("topic", topic),
+		requestid.FromHeaders(record.Headers),
+		zap.Int("value_length", len(record.Value)))
+
+	return nil
+}
+
+// SendBatch отправляет записи в топик одним вызовом Writer. Возвращает ошибки по каждой записи
+// (nil для успешных) или общую ошибку, если пакет не был записан
+func (p *Producer) SendBatch(topic string, records []Record) ([]error, error) {
+	now := time.Now()
+	messages := make([]kafka.Message, 0, len(records))
+	for _, record := range records {
+		messages = append(messages, toKafkaMessage(topic, record, now))
+	}
+
+	// Записи пакета относятся к одному HTTP запросу, родительский спан и идентификатор запроса берутся из первой
+	var parentHeaders map[string]string
+	if len(records) > 0 {
+		parentHeaders = records[0].Headers
+	}
+
+	recordErrors := make([]error, len(records))
+	ctx, span := startSpan(topic, parentHeaders, len(records))
+	err := p.writer.WriteMessages(ctx, messages...)
+	endSpan(span, err)
+	if err == nil {
+		p.countWrite(topic, messages...)
+		p.logger.Info("Batch sent to Kafka",
+			zap.String("topic", topic),
+			requestid.FromHeaders(parentHeaders),
+			zap.Int("records", len(records)))
+		return recordErrors, nil
+	}
+
+	var writeErrors kafka.WriteErrors
+	if !errors.As(err, &writeErrors) || len(writeErrors) != len(records) {
+		p.countErrors(topic, len(messages))
+		p.logger.Error("Failed to send batch to Kafka",
+			zap.String("topic", topic),
+			requestid.FromHeaders(parentHeaders),
+			zap.Int("records", len(records)),
+			zap.Error(err))
+		return nil, wrapWriteError(topic, err)
+	}
+
+	written := make([]kafka.Message, 0, len(messages)-writeErrors.Count())
+	for i, writeErr := range writeErrors {
+		if writeErr != nil {
+			recordErrors[i] = wrapRecordError(topic, messages[i], writeErr)
+		} else {
+			written = append(written, messages[i])
+		}
+	}
+	p.countWrite(topic, written...)
+	p.countErrors(topic, writeErrors.Count())
+
+	p.logger.Warn("Batch partially sent to Kafka",
+		zap.String("topic", topic),
+		requestid.FromHeaders(parentHeaders),
+		zap.Int("records", len(records)),
+		zap.Int("failed", writeErrors.Count()))
+
+	return recordErrors, nil
+}
+
+// Stats возвращает статистику Writer с предыдущего вызова; для подмененного Writer - нулевую
+func (p *Producer) Stats() kafka.WriterStats {
+	if writer, ok := p.writer.(*kafka.Writer); ok {
+		return writer.Stats()
+	}
+	return kafka.WriterStats{}
+}
+
+// TopicStats возвращает накопленную с запуска статистику записи по топикам
+func (p *Producer) TopicStats() map[string]models.TopicWriteStats {
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+
+	stats := make(map[string]models.TopicWriteStats, len(p.topicStats))
+	for topic, topicStats := range p.topicStats {
+		stats[topic] = *topicStats
+	}
+	return stats
+}
+
+// countSend учитывает результат отправки одного сообщения
+func (p *Producer) countSend(topic string, message kafka.Message, err error) {
+	if err != nil {
+		p.countErrors(topic, 1)
+		return
+	}
+	p.countWrite(topic, message)
+}
+
+// countWrite учитывает записанные в топик сообщения
+func (p *Producer) countWrite(topic string, messages ...kafka.Message) {
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+
+	stats := p.topicStatsLocked(topic)
+	for _, message := range messages {
+		stats.Messages++
+		stats.Bytes += messageBytes(message)
+	}
+}
+
+// countErrors учитывает сообщения, которые не удалось записать в топик
+func (p *Producer) countErrors(topic string, count int) {
+	if count == 0 {
+		return
+	}
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+
+	p.topicStatsLocked(topic).Errors += int64(count)
+}
+
+func (p *Producer) topicStatsLocked(topic string) *models.TopicWriteStats {
+	if p.topicStats == nil {
+		p.topicStats = make(map[string]*models.TopicWriteStats)
+	}
+	stats, ok := p.topicStats[topic]
+	if !ok {
+		stats = &models.TopicWriteStats{}
+		p.topicStats[topic] = stats
+	}
+	return stats
+}
+
+// messageBytes объем сообщения: ключ, значение и заголовки
+func messageBytes(message kafka.Message) int64 {
+	size := len(message.Key) + len(message.Value)
+	for _, header := range message.Headers {
+		size += len(header.Key) + len(header.Value)
+	}
+	return int64(size)
+}
+
 func (p *Producer) Close() error {
 	return p.writer.Close()
 }
+
+func toKafkaMessage(topic string, record Record, now time.Time) kafka.Message {
+	message := kafka.Message{
+		Topic:   topic,
+		Key:     record.Key,
+		Value:   record.Value,
+		Headers: toKafkaHeaders(record.Headers),
+		Time:    now,
+	}
+	if !record.Time.IsZero() {
+		message.Time = record.Time
+	}
+	if record.Partition != nil {
+		message.WriterData = &partitionHint{partition: *record.Partition}
+	}
+	return message
+}
+
+// wrapRecordError дополняет wrapWriteError ошибкой несуществующей явно заданной партиции
+func wrapRecordError(topic string, message kafka.Message, err error) error {
+	if hint, ok := message.WriterData.(*partitionHint); ok && hint.outOfRange.Load() {
+		return fmt.Errorf("%w: topic %s has no partition %d", ErrInvalidPartition, topic, hint.partition)
+	}
+	return wrapWriteError(topic, err)
+}
+
+// startSpan начинает спан записи в Kafka. Родительский контекст берется из заголовка traceparent,
+// который обработчик добавляет в запись
+func startSpan(topic string, headers map[string]string, records int) (context.Context, trace.Span) {
+	ctx := tracing.Extract(context.Background(), headers)
+	return tracing.Tracer("kafka").Start(ctx, "kafka write "+topic,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypeSend,
+			semconv.MessagingDestinationName(topic),
+			semconv.MessagingBatchMessageCount(records),
+		))
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// toKafkaHeaders преобразует map[string]string в []kafka.Header
+func toKafkaHeaders(headers map[string]string) []kafka.Header {
+	kafkaHeaders := make([]kafka.Header, 0, len(headers))
+	for k, v := range headers {
+		kafkaHeaders = append(kafkaHeaders, kafka.Header{
+			Key:   k,
+			Value: []byte(v),
+		})
+	}
+	return kafkaHeaders
+}
+
+// wrapWriteError приводит ошибку отсутствующего топика к ErrTopicNotFound,
+// а ошибку превышения размера записи - к ErrMessageTooLarge
+func wrapWriteError(topic string, err error) error {
+	var writeErrors kafka.WriteErrors
+	if errors.As(err, &writeErrors) {
+		for _, e := range writeErrors {
+			if errors.Is(e, kafka.UnknownTopicOrPartition) {
+				return fmt.Errorf("%w: %s", ErrTopicNotFound, topic)
+			}
+		}
+		for _, e := range writeErrors {
+			if errors.Is(e, kafka.MessageSizeTooLarge) {
+				return fmt.Errorf("%w: %v", ErrMessageTooLarge, e)
+			}
+		}
+	}
+
+	if errors.Is(err, kafka.UnknownTopicOrPartition) {
+		return fmt.Errorf("%w: %s", ErrTopicNotFound, topic)
+	}
+
+	var tooLargeErr kafka.MessageTooLargeError
+	if errors.As(err, &tooLargeErr) || errors.Is(err, kafka.MessageSizeTooLarge) {
+		return fmt.Errorf("%w: %v", ErrMessageTooLarge, err)
+	}
+
+	return err
+}
@@ -2,10 +2,14 @@ package kafka
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/segmentio/kafka-go"
 	"go.uber.org/zap"
+
+	"kafkaGateway/models"
 )
 
 func TestNewProducer(t *testing.T) {
@@ -135,3 +139,191 @@ func TestClose(t *testing.T) {
 	// Не проверяем ошибку, так как может быть ошибка подключения
 	_ = err
 }
+
+func TestProducerSendMessageUnknownTopic(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	tests := []struct {
+		name     string
+		writeErr error
+		notFound bool
+	}{
+		{name: "direct error", writeErr: kafka.UnknownTopicOrPartition, notFound: true},
+		{name: "batch error", writeErr: kafka.WriteErrors{kafka.UnknownTopicOrPartition}, notFound: true},
+		{name: "other error", writeErr: kafka.RequestTimedOut, notFound: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			producer := &Producer{
+				writer: &MockWriter{
+					WriteMessagesFunc: func(ctx context.Context, msgs ...kafka.Message) error {
+						return tt.writeErr
+					},
+				},
+				logger: logger,
+			}
+
+			err := producer.SendMessage("missing-topic", nil, []byte("value"))
+			if errors.Is(err, ErrTopicNotFound) != tt.notFound {
+				t.Errorf("Expected ErrTopicNotFound=%v, got %v", tt.notFound, err)
+			}
+		})
+	}
+}
+
+func TestProducerSendBatch(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	records := []Record{
+		{Key: []byte("k1"), Value: []byte("v1")},
+		{Key: []byte("k2"), Value: []byte("v2"), Headers: map[string]string{"h": "1"}},
+	}
+
+	tests := []struct {
+		name         string
+		writeErr     error
+		expectErr    bool
+		failedRecord int
+	}{
+		{name: "all sent", failedRecord: -1},
+		{name: "one record failed", writeErr: kafka.WriteErrors{nil, kafka.MessageSizeTooLarge}, failedRecord: 1},
+		{name: "batch failed", writeErr: kafka.RequestTimedOut, expectErr: true, failedRecord: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var written []kafka.Message
+			producer := &Producer{
+				writer: &MockWriter{
+					WriteMessagesFunc: func(ctx context.Context, msgs ...kafka.Message) error {
+						written = msgs
+						return tt.writeErr
+					},
+				},
+				logger: logger,
+			}
+
+			recordErrors, err := producer.SendBatch("batch-topic", records)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("Expected error=%v, got %v", tt.expectErr, err)
+			}
+			if len(written) != len(records) {
+				t.Fatalf("Expected %d messages written in one call, got %d", len(records), len(written))
+			}
+			if written[1].Topic != "batch-topic" || len(written[1].Headers) != 1 {
+				t.Errorf("Unexpected message: %+v", written[1])
+			}
+			if tt.expectErr {
+				return
+			}
+
+			for i, recordErr := range recordErrors {
+				if (recordErr != nil) != (i == tt.failedRecord) {
+					t.Errorf("Record %d: unexpected error %v", i, recordErr)
+				}
+			}
+		})
+	}
+}
+
+func TestProducerSendMessageTooLarge(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	tests := []struct {
+		name     string
+		writeErr error
+	}{
+		{name: "writer batch bytes", writeErr: kafka.MessageTooLargeError{}},
+		{name: "broker error", writeErr: kafka.WriteErrors{kafka.MessageSizeTooLarge}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			producer := &Producer{
+				writer: &MockWriter{
+					WriteMessagesFunc: func(ctx context.Context, msgs ...kafka.Message) error {
+						return tt.writeErr
+					},
+				},
+				logger: logger,
+			}
+
+			err := producer.SendMessage("events", nil, []byte("value"))
+			if !errors.Is(err, ErrMessageTooLarge) {
+				t.Errorf("Expected ErrMessageTooLarge, got %v", err)
+			}
+		})
+	}
+}
+
+func TestProducerSendRecordTime(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	var sent kafka.Message
+	mockWriter := &MockWriter{
+		WriteMessagesFunc: func(ctx context.Context, msgs ...kafka.Message) error {
+			sent = msgs[0]
+			return nil
+		},
+	}
+	producer := &Producer{writer: mockWriter, logger: logger}
+
+	timestamp := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	if err := producer.SendRecord("events", Record{Value: []byte("v"), Time: timestamp}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !sent.Time.Equal(timestamp) {
+		t.Errorf("Expected record time %v, got %v", timestamp, sent.Time)
+	}
+
+	before := time.Now()
+	producer.SendRecord("events", Record{Value: []byte("v")})
+	if sent.Time.Before(before) {
+		t.Errorf("Expected send time for record without time, got %v", sent.Time)
+	}
+}
+
+func TestProducerTopicStats(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	var writeErr error
+	producer := &Producer{
+		writer: &MockWriter{
+			WriteMessagesFunc: func(ctx context.Context, msgs ...kafka.Message) error {
+				return writeErr
+			},
+		},
+		logger: logger,
+	}
+
+	// Ключ, значение и заголовки: 2 + 5 + 2 байта
+	producer.SendRecord("orders", Record{Key: []byte("k1"), Value: []byte("value"), Headers: map[string]string{"h": "1"}})
+	producer.SendMessage("users", []byte("k"), []byte("v"))
+
+	writeErr = kafka.WriteErrors{nil, kafka.MessageSizeTooLarge, nil}
+	producer.SendBatch("orders", []Record{{Value: []byte("abc")}, {Value: []byte("too-large")}, {Value: []byte("de")}})
+
+	writeErr = kafka.RequestTimedOut
+	producer.SendRecord("users", Record{Value: []byte("v")})
+	producer.SendBatch("users", []Record{{Value: []byte("a")}, {Value: []byte("b")}})
+
+	expected := map[string]models.TopicWriteStats{
+		"orders": {Messages: 3, Bytes: 14, Errors: 1},
+		"users":  {Messages: 1, Bytes: 2, Errors: 3},
+	}
+	stats := producer.TopicStats()
+	if len(stats) != len(expected) {
+		t.Fatalf("Expected stats for %d topics, got %v", len(expected), stats)
+	}
+	for topic, want := range expected {
+		if stats[topic] != want {
+			t.Errorf("Topic %s: expected %+v, got %+v", topic, want, stats[topic])
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package logger
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ComponentGlobal имя общего уровня логирования, который наследуют компоненты без собственного уровня
+const ComponentGlobal = "global"
+
+// ErrUnknownComponent у компонента нет управляемого уровня логирования
+var ErrUnknownComponent = errors.New("unknown log component")
+
+// components компоненты с собственным уровнем логирования
+var components = []string{ComponentHTTP, ComponentProducer, ComponentAuth}
+
+// LevelStatus текущий уровень логирования компонента
+type LevelStatus struct {
+	Component string
+	Level     zapcore.Level
+	// Inherited компонент использует общий уровень
+	Inherited bool
+	// RevertAt время возврата к предыдущему уровню; нулевое, если уровень задан без TTL
+	RevertAt time.Time
+}
+
+// levelState уровень логирования общий или компонента
+type levelState struct {
+	level     zap.AtomicLevel
+	inherited bool
+	revert    *levelRevert
+}
+
+// levelRevert отложенный возврат к уровню, действовавшему до временного изменения
+type levelRevert struct {
+	timer     *time.Timer
+	at        time.Time
+	level     zapcore.Level
+	inherited bool
+}
+
+// levels уровни логирования фабрики
+type levels struct {
+	mu         sync.Mutex
+	configured zapcore.Level
+	global     *levelState
+	components map[string]*levelState
+}
+
+func newLevels(level zapcore.Level) *levels {
+	l := &levels{
+		configured: level,
+		global:     &levelState{level: zap.NewAtomicLevelAt(level)},
+		components: make(map[string]*levelState, len(components)),
+	}
+	for _, component := range components {
+		l.components[component] = &levelState{level: zap.NewAtomicLevelAt(level), inherited: true}
+	}
+	return l
+}
+
+// enabler возвращает уровень компонента; для неизвестных компонентов - общий уровень
+func (l *levels) enabler(component string) zapcore.LevelEnabler {
+	if state, ok := l.components[component]; ok {
+		return state.level
+	}
+	return l.global.level
+}
+
+func (l *levels) state(component string) (*levelState, error) {
+	if component == ComponentGlobal {
+		return l.global, nil
+	}
+	if state, ok := l.components[component]; ok {
+		return state, nil
+	}
+	return nil, ErrUnknownComponent
+}
+
+// Levels возвращает общий уровень и уровни компонентов
+func (l *levels) Levels() []LevelStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	statuses := []LevelStatus{l.status(ComponentGlobal, l.global)}
+	names := make([]string, 0, len(l.components))
+	for name := range l.components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		statuses = append(statuses, l.status(name, l.components[name]))
+	}
+	return statuses
+}
+
+// SetLevel задает уровень компонента или общий уровень (ComponentGlobal). При ttl > 0 уровень
+// возвращается к предыдущему по истечении ttl; повторное временное изменение продлевает срок,
+// но возвращает к уровню, действовавшему до первого из них
+func (l *levels) SetLevel(component string, level zapcore.Level, ttl time.Duration) (LevelStatus, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state, err := l.state(component)
+	if err != nil {
+		return LevelStatus{}, err
+	}
+
+	previous := state.revert
+	if previous != nil {
+		previous.timer.Stop()
+		state.revert = nil
+	}
+	if ttl > 0 {
+		revert := &levelRevert{at: time.Now().Add(ttl), level: state.level.Level(), inherited: state.inherited}
+		if previous != nil {
+			revert.level, revert.inherited = previous.level, previous.inherited
+		}
+		revert.timer = time.AfterFunc(ttl, func() { l.expire(component, revert) })
+		state.revert = revert
+	}
+
+	l.apply(component, state, level, false)
+	return l.status(component, state), nil
+}
+
+// ResetLevel возвращает компонент к общему уровню, а общий уровень - к заданному при запуске
+func (l *levels) ResetLevel(component string) (LevelStatus, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state, err := l.state(component)
+	if err != nil {
+		return LevelStatus{}, err
+	}
+	if state.revert != nil {
+		state.revert.timer.Stop()
+		state.revert = nil
+	}
+
+	if component == ComponentGlobal {
+		l.apply(component, state, l.configured, false)
+	} else {
+		l.apply(component, state, l.global.level.Level(), true)
+	}
+	return l.status(component, state), nil
+}
+
+// expire возвращает уровень по истечении TTL, если он не был изменен повторно
+func (l *levels) expire(component string, revert *levelRevert) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state, err := l.state(component)
+	if err != nil || state.revert != revert {
+		return
+	}
+	state.revert = nil
+
+	level := revert.level
+	if revert.inherited {
+		level = l.global.level.Level()
+	}
+	l.apply(component, state, level, revert.inherited)
+}
+
+// apply задает уровень; изменение общего уровня распространяется на наследующие его компоненты
+func (l *levels) apply(component string, state *levelState, level zapcore.Level, inherited bool) {
+	state.level.SetLevel(level)
+	if component != ComponentGlobal {
+		state.inherited = inherited
+		return
+	}
+	for _, componentState := range l.components {
+		if componentState.inherited {
+			componentState.level.SetLevel(level)
+		}
+	}
+}
+
+func (l *levels) status(component string, state *levelState) LevelStatus {
+	status := LevelStatus{
+		Component: component,
+		Level:     state.level.Level(),
+		Inherited: state.inherited,
+	}
+	if state.revert != nil {
+		status.RevertAt = state.revert.at
+	}
+	return status
+}
+
+// levelCore пропускает записи по уровню компонента. Базовое ядро фабрики пропускает все уровни
+type levelCore struct {
+	zapcore.Core
+	level zapcore.LevelEnabler
+}
+
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return c.level.Enabled(level)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.level.Enabled(entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
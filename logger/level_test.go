@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func newTestFactory(t *testing.T) *Factory {
+	opts := DefaultOptions()
+	opts.OutputPaths = []string{"stderr"}
+	factory, err := NewFactory(opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return factory
+}
+
+func TestFactorySetLevel(t *testing.T) {
+	factory := newTestFactory(t)
+	producer := factory.Named(ComponentProducer)
+	http := factory.Named(ComponentHTTP)
+
+	if producer.Core().Enabled(zapcore.DebugLevel) {
+		t.Fatalf("Expected debug to be disabled at info level")
+	}
+
+	if _, err := factory.SetLevel(ComponentProducer, zapcore.DebugLevel, 0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !producer.Core().Enabled(zapcore.DebugLevel) {
+		t.Errorf("Expected producer debug to be enabled")
+	}
+	if http.Core().Enabled(zapcore.DebugLevel) || factory.Logger().Core().Enabled(zapcore.DebugLevel) {
+		t.Errorf("Expected other loggers to keep info level")
+	}
+
+	// Общий уровень наследуют только компоненты без собственного уровня
+	if _, err := factory.SetLevel(ComponentGlobal, zapcore.ErrorLevel, 0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if http.Core().Enabled(zapcore.WarnLevel) || !producer.Core().Enabled(zapcore.DebugLevel) {
+		t.Errorf("Expected global level to apply only to inherited components")
+	}
+
+	status, err := factory.ResetLevel(ComponentProducer)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !status.Inherited || status.Level != zapcore.ErrorLevel || producer.Core().Enabled(zapcore.WarnLevel) {
+		t.Errorf("Expected producer to inherit error level, got %+v", status)
+	}
+
+	status, _ = factory.ResetLevel(ComponentGlobal)
+	if status.Level != zapcore.InfoLevel || !http.Core().Enabled(zapcore.InfoLevel) {
+		t.Errorf("Expected global level to reset to info, got %+v", status)
+	}
+
+	if _, err := factory.SetLevel("kafka", zapcore.DebugLevel, 0); !errors.Is(err, ErrUnknownComponent) {
+		t.Errorf("Expected ErrUnknownComponent, got %v", err)
+	}
+}
+
+func TestFactorySetLevelTTL(t *testing.T) {
+	factory := newTestFactory(t)
+	auth := factory.Named(ComponentAuth)
+
+	if _, err := factory.SetLevel(ComponentAuth, zapcore.WarnLevel, time.Hour); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Повторное временное изменение возвращает к уровню до первого из них
+	status, err := factory.SetLevel(ComponentAuth, zapcore.DebugLevel, 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if status.RevertAt.IsZero() || status.Inherited {
+		t.Errorf("Expected temporary component level, got %+v", status)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for auth.Core().Enabled(zapcore.DebugLevel) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	levels := factory.Levels()
+	if len(levels) != 4 || levels[0].Component != ComponentGlobal {
+		t.Fatalf("Expected global and three component levels, got %+v", levels)
+	}
+	for _, level := range levels {
+		if level.Component != ComponentAuth {
+			continue
+		}
+		if level.Level != zapcore.InfoLevel || !level.Inherited || !level.RevertAt.IsZero() {
+			t.Errorf("Expected auth level to revert to inherited info, got %+v", level)
+		}
+	}
+}
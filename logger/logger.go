@@ -1,51 +1,146 @@
 package logger
 
 import (
+	"fmt"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
+// Компоненты шлюза с собственными именованными логгерами
+const (
+	ComponentHTTP     = "http"
+	ComponentProducer = "producer"
+	ComponentAuth     = "auth"
+)
+
+// Options настройки логгеров
+type Options struct {
+	Level zapcore.Level
+	// Encoding формат записей: json или console
+	Encoding string
+	// OutputPaths пути вывода: stdout, stderr или файлы
+	OutputPaths []string
+	// SamplingInitial и SamplingThereafter: из одинаковых записей за секунду пишутся первые
+	// SamplingInitial, затем каждая SamplingThereafter; 0 отключает сэмплирование
+	SamplingInitial    int
+	SamplingThereafter int
+}
+
+// DefaultOptions настройки по умолчанию: info, JSON в stdout, сэмплирование как в zap.NewProduction
+func DefaultOptions() Options {
+	return Options{
+		Level:              zapcore.InfoLevel,
+		Encoding:           "json",
+		OutputPaths:        []string{"stdout"},
+		SamplingInitial:    100,
+		SamplingThereafter: 100,
+	}
+}
+
+// Factory создает логгеры шлюза с общими настройками. Уровни общего логгера и компонентов
+// меняются во время работы (SetLevel, ResetLevel)
+type Factory struct {
+	*levels
+	// base пропускает все уровни, уровень применяется в логгерах фабрики
+	base *zap.Logger
+	root *zap.Logger
+}
+
+func NewFactory(opts Options) (*Factory, error) {
+	if opts.Encoding != "json" && opts.Encoding != "console" {
+		return nil, fmt.Errorf("unknown log encoding %q", opts.Encoding)
+	}
+	if len(opts.OutputPaths) == 0 {
+		opts.OutputPaths = []string{"stdout"}
+	}
+
+	config := zap.Config{
+		Level:            zap.NewAtomicLevelAt(zapcore.DebugLevel),
+		Development:      false,
+		Encoding:         opts.Encoding,
+		EncoderConfig:    encoderConfig(),
+		OutputPaths:      opts.OutputPaths,
+		ErrorOutputPaths: []string{"stderr"},
+	}
+	if opts.SamplingInitial > 0 && opts.SamplingThereafter > 0 {
+		config.Sampling = &zap.SamplingConfig{
+			Initial:    opts.SamplingInitial,
+			Thereafter: opts.SamplingThereafter,
+		}
+	}
+
+	base, err := config.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Factory{levels: newLevels(opts.Level), base: base}
+	f.root = f.withLevel(base, f.global.level)
+	return f, nil
+}
+
+// Logger возвращает корневой логгер
+func (f *Factory) Logger() *zap.Logger {
+	return f.root
+}
+
+// Named возвращает логгер компонента; имя компонента пишется в поле logger. Компоненты http,
+// producer и auth имеют собственный уровень, остальные используют общий
+func (f *Factory) Named(component string) *zap.Logger {
+	return f.withLevel(f.base.Named(component), f.enabler(component))
+}
+
+func (f *Factory) withLevel(logger *zap.Logger, level zapcore.LevelEnabler) *zap.Logger {
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &levelCore{Core: core, level: level}
+	}))
+}
+
+// NewLogger создает логгер с уровнем в формате KAFKA_LOG_LEVEL (0 - fatal ... 4 - debug)
 func NewLogger(level int) (*zap.Logger, error) {
-	var zapLevel zapcore.Level
+	opts := DefaultOptions()
+	opts.Level = LevelFromInt(level)
+	opts.SamplingInitial = 0
+
+	factory, err := NewFactory(opts)
+	if err != nil {
+		return nil, err
+	}
+	return factory.Logger(), nil
+}
 
-	// Преобразуем уровень логирования из конфига в zap уровень
+// LevelFromInt преобразует уровень логирования из конфига в zap уровень
+func LevelFromInt(level int) zapcore.Level {
 	switch level {
 	case 0:
-		zapLevel = zapcore.FatalLevel
+		return zapcore.FatalLevel
 	case 1:
-		zapLevel = zapcore.ErrorLevel
+		return zapcore.ErrorLevel
 	case 2:
-		zapLevel = zapcore.WarnLevel
+		return zapcore.WarnLevel
 	case 3:
-		zapLevel = zapcore.InfoLevel
+		return zapcore.InfoLevel
 	case 4:
-		zapLevel = zapcore.DebugLevel
+		return zapcore.DebugLevel
 	default:
-		zapLevel = zapcore.InfoLevel
+		return zapcore.InfoLevel
 	}
+}
 
-	// Создаем конфигурацию для логгера
-	config := zap.Config{
-		Level:       zap.NewAtomicLevelAt(zapLevel),
-		Development: false,
-		Encoding:    "json",
-		EncoderConfig: zapcore.EncoderConfig{
-			TimeKey:        "timestamp",
-			LevelKey:       "level",
-			NameKey:        "logger",
-			CallerKey:      "caller",
-			FunctionKey:    zapcore.OmitKey,
-			MessageKey:     "message",
-			StacktraceKey:  "stacktrace",
-			LineEnding:     zapcore.DefaultLineEnding,
-			EncodeLevel:    zapcore.LowercaseLevelEncoder,
-			EncodeTime:     zapcore.ISO8601TimeEncoder,
-			EncodeDuration: zapcore.SecondsDurationEncoder,
-			EncodeCaller:   zapcore.ShortCallerEncoder,
-		},
-		OutputPaths:      []string{"stdout"},
-		ErrorOutputPaths: []string{"stderr"},
+func encoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "timestamp",
+		LevelKey:       "level",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		FunctionKey:    zapcore.OmitKey,
+		MessageKey:     "message",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
 	}
-
-	return config.Build()
 }
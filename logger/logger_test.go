@@ -1,7 +1,13 @@
 package logger
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"go.uber.org/zap/zapcore"
 )
 
 func TestNewLogger(t *testing.T) {
@@ -62,3 +68,40 @@ func TestNewLogger(t *testing.T) {
 		})
 	}
 }
+
+func TestNewFactory(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "gateway.log")
+
+	opts := DefaultOptions()
+	opts.Level = zapcore.WarnLevel
+	opts.OutputPaths = []string{output}
+	factory, err := NewFactory(opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	factory.Named(ComponentProducer).Info("Filtered by level")
+	factory.Named(ComponentProducer).Warn("Producer warning")
+	factory.Logger().Sync()
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected one log line, got %q", data)
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("Expected JSON log line, got %q", lines[0])
+	}
+	if entry["logger"] != ComponentProducer || entry["message"] != "Producer warning" {
+		t.Errorf("Expected producer warning, got %v", entry)
+	}
+
+	opts.Encoding = "logfmt"
+	if _, err := NewFactory(opts); err == nil {
+		t.Errorf("Expected error for unknown encoding")
+	}
+}
@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"context"
 	"net/http"
 	"sync"
 	"time"
@@ -28,15 +29,48 @@ type MessageInfo struct {
 	Status    string    `json:"status"`
 }
 
+// TopicLister возвращает имена топиков, существующих в кластере
+type TopicLister func(ctx context.Context) ([]string, error)
+
 // MetricsStore структура для хранения информации о топиках и сообщениях
 type MetricsStore struct {
-	mu sync.RWMutex
+	mu          sync.RWMutex
+	topicLister TopicLister
+	topicsList  []string
 }
 
-// UpdateDemoData обновляет демонстрационные данные для симуляции активности
-func UpdateDemoData() {
-	// В реальном приложении здесь будет логика обновления данных из Prometheus метрик
-	// Пока оставляем пустой, так как данные будут получаться напрямую из Prometheus
+var store = &MetricsStore{}
+
+// SetTopicLister задает источник реального списка топиков кластера.
+// Пока источник не задан, список топиков строится по меткам счетчиков
+func SetTopicLister(lister TopicLister) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.topicLister = lister
+}
+
+// RefreshTopics обновляет кэшированный список топиков из кластера через источник SetTopicLister
+func RefreshTopics() {
+	store.mu.RLock()
+	lister := store.topicLister
+	store.mu.RUnlock()
+
+	if lister == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	topics, err := lister(ctx)
+	if err != nil {
+		// Оставляем предыдущий список, чтобы UI не мигал при временной недоступности брокера
+		return
+	}
+
+	store.mu.Lock()
+	store.topicsList = topics
+	store.mu.Unlock()
 }
 
 // GetMetrics возвращает метрики в формате JSON для UI
@@ -165,13 +199,27 @@ func GetTopics(c *gin.Context) {
 		}
 	}
 
-	// Преобразуем map в slice
+	// Если известен реальный список топиков кластера, показываем только его
+	store.mu.RLock()
+	hasLister := store.topicLister != nil
+	clusterTopics := store.topicsList
+	store.mu.RUnlock()
+
 	var topicsList []TopicInfo
-	for topic, count := range topicsMap {
-		topicsList = append(topicsList, TopicInfo{
-			Name:         topic,
-			MessageCount: count,
-		})
+	if hasLister {
+		for _, topic := range clusterTopics {
+			topicsList = append(topicsList, TopicInfo{
+				Name:         topic,
+				MessageCount: topicsMap[topic],
+			})
+		}
+	} else {
+		for topic, count := range topicsMap {
+			topicsList = append(topicsList, TopicInfo{
+				Name:         topic,
+				MessageCount: count,
+			})
+		}
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -69,21 +70,34 @@ func TestGetRecentMessages(t *testing.T) {
 	assert.NotEmpty(t, w.Body.String())
 }
 
-func TestUpdateDemoData(t *testing.T) {
+func TestRefreshTopics(t *testing.T) {
 	// Сохраняем начальные значения
 	initialTopics := len(store.topicsList)
 
-	// Вызываем функцию обновления данных
-	UpdateDemoData()
-
-	// Проверяем, что количество топиков не изменилось
+	// Без источника топиков обновление ничего не меняет
+	RefreshTopics()
 	assert.Equal(t, initialTopics, len(store.topicsList))
 
-	// Проверяем, что количество сообщений не превышает 10
-	assert.True(t, len(store.recentMessages) <= 10)
+	// С источником список топиков берется из кластера
+	SetTopicLister(func(ctx context.Context) ([]string, error) {
+		return []string{"orders", "users"}, nil
+	})
+	defer func() {
+		SetTopicLister(nil)
+		store.topicsList = nil
+	}()
+
+	RefreshTopics()
+	assert.Equal(t, []string{"orders", "users"}, store.topicsList)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/topics", nil)
+	GetTopics(c)
 
-	// Проверяем, что хотя бы одно сообщение есть
-	assert.True(t, len(store.recentMessages) >= 1)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"orders"`)
+	assert.Contains(t, w.Body.String(), `"name":"users"`)
 }
 
 func TestMetricsStoreConcurrency(t *testing.T) {
@@ -113,7 +127,7 @@ func TestMetricsStoreConcurrency(t *testing.T) {
 	for i := 0; i < 3; i++ {
 		go func() {
 			for j := 0; j < 10; j++ {
-				UpdateDemoData()
+				RefreshTopics()
 				time.Sleep(1 * time.Millisecond) // Небольшая задержка для лучшего чередования
 			}
 			done <- true
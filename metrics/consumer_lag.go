@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"kafkaGateway/models"
+)
+
+// ConsumerGroupSource возвращает описание всех групп потребителей кластера
+type ConsumerGroupSource func(ctx context.Context) ([]models.ConsumerGroupDescription, error)
+
+var (
+	consumerGroupMu     sync.RWMutex
+	consumerGroupSource ConsumerGroupSource
+)
+
+// SetConsumerGroupSource задает источник данных об отставании групп потребителей
+func SetConsumerGroupSource(source ConsumerGroupSource) {
+	consumerGroupMu.Lock()
+	defer consumerGroupMu.Unlock()
+	consumerGroupSource = source
+}
+
+// UpdateConsumerLag обновляет метрики отставания групп потребителей
+func UpdateConsumerLag() error {
+	consumerGroupMu.RLock()
+	source := consumerGroupSource
+	consumerGroupMu.RUnlock()
+
+	if source == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	groups, err := source(ctx)
+	if err != nil {
+		return err
+	}
+
+	SetConsumerLag(groups)
+	return nil
+}
+
+// SetConsumerLag заменяет значения метрик отставания переданными группами
+func SetConsumerLag(groups []models.ConsumerGroupDescription) {
+	// Сбрасываем метрики, чтобы удаленные группы и партиции не висели с последним значением
+	ConsumerGroupLag.Reset()
+	ConsumerGroupCommittedOffset.Reset()
+
+	for _, group := range groups {
+		for _, p := range group.Partitions {
+			partition := strconv.Itoa(p.Partition)
+			ConsumerGroupLag.WithLabelValues(group.GroupID, p.Topic, partition).Set(float64(p.Lag))
+			if p.CommittedOffset >= 0 {
+				ConsumerGroupCommittedOffset.WithLabelValues(group.GroupID, p.Topic, partition).Set(float64(p.CommittedOffset))
+			}
+		}
+	}
+}
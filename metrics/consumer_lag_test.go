@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"kafkaGateway/models"
+)
+
+func TestUpdateConsumerLag(t *testing.T) {
+	SetConsumerGroupSource(func(ctx context.Context) ([]models.ConsumerGroupDescription, error) {
+		return []models.ConsumerGroupDescription{{
+			GroupID: "billing",
+			Partitions: []models.PartitionLag{
+				{Topic: "orders", Partition: 0, CommittedOffset: 90, LogEndOffset: 100, Lag: 10},
+				{Topic: "orders", Partition: 1, CommittedOffset: -1, LogEndOffset: 40, Lag: 40},
+			},
+		}}, nil
+	})
+	defer SetConsumerGroupSource(nil)
+
+	assert.NoError(t, UpdateConsumerLag())
+
+	assert.Equal(t, 10.0, testutil.ToFloat64(ConsumerGroupLag.WithLabelValues("billing", "orders", "0")))
+	assert.Equal(t, 40.0, testutil.ToFloat64(ConsumerGroupLag.WithLabelValues("billing", "orders", "1")))
+	assert.Equal(t, 90.0, testutil.ToFloat64(ConsumerGroupCommittedOffset.WithLabelValues("billing", "orders", "0")))
+
+	// Группа исчезла - метрики по ней сбрасываются
+	SetConsumerLag(nil)
+	assert.Equal(t, 0, testutil.CollectAndCount(ConsumerGroupLag))
+}
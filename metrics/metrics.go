@@ -52,4 +52,22 @@ var (
 		},
 		[]string{"endpoint", "method", "status_code"},
 	)
+
+	// ConsumerGroupLag Отставание групп потребителей по партициям
+	ConsumerGroupLag = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kafka_gateway_consumer_group_lag",
+			Help: "Number of messages a consumer group is behind the log end offset",
+		},
+		[]string{"group", "topic", "partition"},
+	)
+
+	// ConsumerGroupCommittedOffset Зафиксированные смещения групп потребителей
+	ConsumerGroupCommittedOffset = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kafka_gateway_consumer_group_committed_offset",
+			Help: "Committed offset of a consumer group",
+		},
+		[]string{"group", "topic", "partition"},
+	)
 )
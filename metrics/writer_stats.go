@@ -0,0 +1,175 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/segmentio/kafka-go"
+
+	"kafkaGateway/models"
+)
+
+// WriterStatsSource возвращает статистику kafka.Writer. Writer.Stats() сбрасывает счетчики
+// и сводки при каждом вызове, поэтому значения - приращения с предыдущего вызова
+type WriterStatsSource func() kafka.WriterStats
+
+// TopicStatsSource возвращает накопленную статистику записи Writer по топикам
+type TopicStatsSource func() map[string]models.TopicWriteStats
+
+var (
+	writerWritesDesc   = writerDesc("kafka_gateway_writer_writes_total", "Total number of write requests sent to Kafka brokers")
+	writerMessagesDesc = writerDesc("kafka_gateway_writer_messages_total", "Total number of messages written by the Kafka writer")
+	writerBytesDesc    = writerDesc("kafka_gateway_writer_bytes_total", "Total number of message bytes written by the Kafka writer")
+	writerErrorsDesc   = writerDesc("kafka_gateway_writer_errors_total", "Total number of failed writes of the Kafka writer")
+	writerRetriesDesc  = writerDesc("kafka_gateway_writer_retries_total", "Total number of write retries of the Kafka writer")
+
+	writerBatchTimeDesc      = writerDesc("kafka_gateway_writer_batch_seconds", "Time from creating a batch to writing it to Kafka")
+	writerBatchQueueTimeDesc = writerDesc("kafka_gateway_writer_batch_queue_seconds", "Time a batch waited in the writer queue")
+	writerWriteTimeDesc      = writerDesc("kafka_gateway_writer_write_seconds", "Time of a single write request to a Kafka broker")
+	writerWaitTimeDesc       = writerDesc("kafka_gateway_writer_wait_seconds", "Time waiting for a broker connection")
+	writerBatchSizeDesc      = writerDesc("kafka_gateway_writer_batch_messages", "Number of messages in written batches")
+	writerBatchBytesDesc     = writerDesc("kafka_gateway_writer_batch_bytes", "Size of written batches in bytes")
+
+	writerMaxAttemptsDesc  = writerDesc("kafka_gateway_writer_max_attempts", "Configured maximum number of write attempts")
+	writerMaxBatchSizeDesc = writerDesc("kafka_gateway_writer_batch_max_messages", "Configured maximum number of messages in a batch")
+	writerBatchTimeoutDesc = writerDesc("kafka_gateway_writer_batch_timeout_seconds", "Configured time limit for filling a batch")
+	writerRequiredAcksDesc = writerDesc("kafka_gateway_writer_required_acks", "Configured number of required acknowledgements")
+
+	writerTopicMessagesDesc = writerTopicDesc("kafka_gateway_writer_topic_messages_total", "Total number of messages written to the topic")
+	writerTopicBytesDesc    = writerTopicDesc("kafka_gateway_writer_topic_bytes_total", "Total number of message bytes written to the topic")
+	writerTopicErrorsDesc   = writerTopicDesc("kafka_gateway_writer_topic_errors_total", "Total number of messages that failed to be written to the topic")
+)
+
+func writerDesc(name, help string) *prometheus.Desc {
+	return prometheus.NewDesc(name, help, []string{"writer"}, nil)
+}
+
+func writerTopicDesc(name, help string) *prometheus.Desc {
+	return prometheus.NewDesc(name, help, []string{"writer", "topic"}, nil)
+}
+
+// writerSummary накопленные количество и сумма наблюдений сводки Writer
+type writerSummary struct {
+	count uint64
+	sum   float64
+}
+
+func (s *writerSummary) addDuration(stats kafka.DurationStats) {
+	s.count += uint64(stats.Count)
+	s.sum += stats.Sum.Seconds()
+}
+
+func (s *writerSummary) add(stats kafka.SummaryStats) {
+	s.count += uint64(stats.Count)
+	s.sum += float64(stats.Sum)
+}
+
+// WriterCollector экспортирует статистику kafka.Writer в Prometheus при каждом сборе метрик.
+// Статистика kafka-go общая для всех топиков Writer и размечена его именем; сообщения, байты
+// и ошибки по топикам берутся из источника WithTopicStats и размечены также топиком
+type WriterCollector struct {
+	name        string
+	source      WriterStatsSource
+	topicSource TopicStatsSource
+
+	mu                                       sync.Mutex
+	writes, messages, bytes, errors, retries float64
+	batchTime, batchQueueTime, writeTime     writerSummary
+	waitTime, batchSize, batchBytes          writerSummary
+	maxAttempts, maxBatchSize, requiredAcks  float64
+	batchTimeout                             time.Duration
+}
+
+func NewWriterCollector(name string, source WriterStatsSource) *WriterCollector {
+	return &WriterCollector{
+		name:   name,
+		source: source,
+	}
+}
+
+// WithTopicStats добавляет статистику записи по топикам
+func (wc *WriterCollector) WithTopicStats(source TopicStatsSource) *WriterCollector {
+	wc.topicSource = source
+	return wc
+}
+
+// RegisterWriter регистрирует сборщик статистики Writer в реестре Prometheus по умолчанию;
+// topicSource может быть nil, тогда метрики по топикам не экспортируются
+func RegisterWriter(name string, source WriterStatsSource, topicSource TopicStatsSource) error {
+	return prometheus.Register(NewWriterCollector(name, source).WithTopicStats(topicSource))
+}
+
+func (wc *WriterCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		writerWritesDesc, writerMessagesDesc, writerBytesDesc, writerErrorsDesc, writerRetriesDesc,
+		writerBatchTimeDesc, writerBatchQueueTimeDesc, writerWriteTimeDesc, writerWaitTimeDesc,
+		writerBatchSizeDesc, writerBatchBytesDesc,
+		writerMaxAttemptsDesc, writerMaxBatchSizeDesc, writerBatchTimeoutDesc, writerRequiredAcksDesc,
+		writerTopicMessagesDesc, writerTopicBytesDesc, writerTopicErrorsDesc,
+	} {
+		ch <- desc
+	}
+}
+
+func (wc *WriterCollector) Collect(ch chan<- prometheus.Metric) {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+
+	// Накапливаем приращения: сбор метрик вызывается и из /metrics, и из /api/metrics
+	stats := wc.source()
+	wc.writes += float64(stats.Writes)
+	wc.messages += float64(stats.Messages)
+	wc.bytes += float64(stats.Bytes)
+	wc.errors += float64(stats.Errors)
+	wc.retries += float64(stats.Retries)
+	wc.batchTime.addDuration(stats.BatchTime)
+	wc.batchQueueTime.addDuration(stats.BatchQueueTime)
+	wc.writeTime.addDuration(stats.WriteTime)
+	wc.waitTime.addDuration(stats.WaitTime)
+	wc.batchSize.add(stats.BatchSize)
+	wc.batchBytes.add(stats.BatchBytes)
+	wc.maxAttempts = float64(stats.MaxAttempts)
+	wc.maxBatchSize = float64(stats.MaxBatchSize)
+	wc.requiredAcks = float64(stats.RequiredAcks)
+	wc.batchTimeout = stats.BatchTimeout
+
+	for desc, value := range map[*prometheus.Desc]float64{
+		writerWritesDesc:   wc.writes,
+		writerMessagesDesc: wc.messages,
+		writerBytesDesc:    wc.bytes,
+		writerErrorsDesc:   wc.errors,
+		writerRetriesDesc:  wc.retries,
+	} {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value, wc.name)
+	}
+
+	for desc, summary := range map[*prometheus.Desc]writerSummary{
+		writerBatchTimeDesc:      wc.batchTime,
+		writerBatchQueueTimeDesc: wc.batchQueueTime,
+		writerWriteTimeDesc:      wc.writeTime,
+		writerWaitTimeDesc:       wc.waitTime,
+		writerBatchSizeDesc:      wc.batchSize,
+		writerBatchBytesDesc:     wc.batchBytes,
+	} {
+		ch <- prometheus.MustNewConstSummary(desc, summary.count, summary.sum, nil, wc.name)
+	}
+
+	for desc, value := range map[*prometheus.Desc]float64{
+		writerMaxAttemptsDesc:  wc.maxAttempts,
+		writerMaxBatchSizeDesc: wc.maxBatchSize,
+		writerBatchTimeoutDesc: wc.batchTimeout.Seconds(),
+		writerRequiredAcksDesc: wc.requiredAcks,
+	} {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, wc.name)
+	}
+
+	if wc.topicSource == nil {
+		return
+	}
+	for topic, stats := range wc.topicSource() {
+		ch <- prometheus.MustNewConstMetric(writerTopicMessagesDesc, prometheus.CounterValue, float64(stats.Messages), wc.name, topic)
+		ch <- prometheus.MustNewConstMetric(writerTopicBytesDesc, prometheus.CounterValue, float64(stats.Bytes), wc.name, topic)
+		ch <- prometheus.MustNewConstMetric(writerTopicErrorsDesc, prometheus.CounterValue, float64(stats.Errors), wc.name, topic)
+	}
+}
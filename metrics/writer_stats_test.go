@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+
+	"kafkaGateway/models"
+)
+
+func TestWriterCollector(t *testing.T) {
+	// Writer.Stats() возвращает приращения с предыдущего вызова
+	snapshots := []kafka.WriterStats{
+		{
+			Writes:       2,
+			Messages:     10,
+			Bytes:        1000,
+			Retries:      1,
+			BatchTime:    kafka.DurationStats{Count: 2, Sum: 30 * time.Millisecond},
+			BatchSize:    kafka.SummaryStats{Count: 2, Sum: 10},
+			MaxBatchSize: 100,
+			BatchTimeout: 10 * time.Millisecond,
+			RequiredAcks: -1,
+		},
+		{
+			Writes:       1,
+			Messages:     5,
+			Bytes:        500,
+			Errors:       1,
+			BatchTime:    kafka.DurationStats{Count: 1, Sum: 10 * time.Millisecond},
+			BatchSize:    kafka.SummaryStats{Count: 1, Sum: 5},
+			MaxBatchSize: 100,
+			BatchTimeout: 10 * time.Millisecond,
+			RequiredAcks: -1,
+		},
+	}
+	calls := 0
+	collector := NewWriterCollector("producer", func() kafka.WriterStats {
+		stats := snapshots[calls]
+		calls++
+		return stats
+	})
+
+	registry := prometheus.NewRegistry()
+	assert.NoError(t, registry.Register(collector))
+
+	assert.Equal(t, 15, testutil.CollectAndCount(collector))
+
+	expected := `
+# HELP kafka_gateway_writer_messages_total Total number of messages written by the Kafka writer
+# TYPE kafka_gateway_writer_messages_total counter
+kafka_gateway_writer_messages_total{writer="producer"} 15
+# HELP kafka_gateway_writer_errors_total Total number of failed writes of the Kafka writer
+# TYPE kafka_gateway_writer_errors_total counter
+kafka_gateway_writer_errors_total{writer="producer"} 1
+# HELP kafka_gateway_writer_batch_seconds Time from creating a batch to writing it to Kafka
+# TYPE kafka_gateway_writer_batch_seconds summary
+kafka_gateway_writer_batch_seconds_sum{writer="producer"} 0.04
+kafka_gateway_writer_batch_seconds_count{writer="producer"} 3
+# HELP kafka_gateway_writer_batch_timeout_seconds Configured time limit for filling a batch
+# TYPE kafka_gateway_writer_batch_timeout_seconds gauge
+kafka_gateway_writer_batch_timeout_seconds{writer="producer"} 0.01
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"kafka_gateway_writer_messages_total",
+		"kafka_gateway_writer_errors_total",
+		"kafka_gateway_writer_batch_seconds",
+		"kafka_gateway_writer_batch_timeout_seconds",
+	))
+}
+
+func TestWriterCollectorTopicStats(t *testing.T) {
+	collector := NewWriterCollector("producer", func() kafka.WriterStats {
+		return kafka.WriterStats{}
+	}).WithTopicStats(func() map[string]models.TopicWriteStats {
+		return map[string]models.TopicWriteStats{
+			"orders": {Messages: 10, Bytes: 1000, Errors: 1},
+			"users":  {Messages: 3, Bytes: 90},
+		}
+	})
+
+	registry := prometheus.NewRegistry()
+	assert.NoError(t, registry.Register(collector))
+
+	// 15 метрик Writer и по 3 на каждый топик
+	assert.Equal(t, 21, testutil.CollectAndCount(collector))
+
+	expected := `
+# HELP kafka_gateway_writer_topic_messages_total Total number of messages written to the topic
+# TYPE kafka_gateway_writer_topic_messages_total counter
+kafka_gateway_writer_topic_messages_total{topic="orders",writer="producer"} 10
+kafka_gateway_writer_topic_messages_total{topic="users",writer="producer"} 3
+# HELP kafka_gateway_writer_topic_bytes_total Total number of message bytes written to the topic
+# TYPE kafka_gateway_writer_topic_bytes_total counter
+kafka_gateway_writer_topic_bytes_total{topic="orders",writer="producer"} 1000
+kafka_gateway_writer_topic_bytes_total{topic="users",writer="producer"} 90
+# HELP kafka_gateway_writer_topic_errors_total Total number of messages that failed to be written to the topic
+# TYPE kafka_gateway_writer_topic_errors_total counter
+kafka_gateway_writer_topic_errors_total{topic="orders",writer="producer"} 1
+kafka_gateway_writer_topic_errors_total{topic="users",writer="producer"} 0
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"kafka_gateway_writer_topic_messages_total",
+		"kafka_gateway_writer_topic_bytes_total",
+		"kafka_gateway_writer_topic_errors_total",
+	))
+}
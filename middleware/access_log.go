@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"kafkaGateway/propagation"
+	"kafkaGateway/requestid"
+)
+
+// AccessLogMiddleware пишет журнал HTTP запросов в zap
+type AccessLogMiddleware struct {
+	Logger *zap.Logger
+	// SampleRatio доля успешных запросов в журнале; запросы со статусом 400 и выше пишутся всегда
+	SampleRatio float64
+	// APIKeyName возвращает имя API ключа для журнала; сам ключ в журнал не попадает
+	APIKeyName func(apiKey string) string
+}
+
+// NewAccessLogMiddleware создает журнал доступа. Без apiKeyName в журнал пишется отпечаток ключа
+func NewAccessLogMiddleware(logger *zap.Logger, sampleRatio float64, apiKeyName func(string) string) *AccessLogMiddleware {
+	if apiKeyName == nil {
+		apiKeyName = propagation.Fingerprint
+	}
+	return &AccessLogMiddleware{
+		Logger:      logger,
+		SampleRatio: sampleRatio,
+		APIKeyName:  apiKeyName,
+	}
+}
+
+func (al *AccessLogMiddleware) Log(c *gin.Context) {
+	start := time.Now()
+	c.Next()
+
+	status := c.Writer.Status()
+	if status < http.StatusBadRequest && al.SampleRatio < 1 && rand.Float64() >= al.SampleRatio {
+		return
+	}
+
+	level := zapcore.InfoLevel
+	switch {
+	case status >= http.StatusInternalServerError:
+		level = zapcore.ErrorLevel
+	case status >= http.StatusBadRequest:
+		level = zapcore.WarnLevel
+	}
+	entry := al.Logger.Check(level, "HTTP request")
+	if entry == nil {
+		return
+	}
+
+	path := c.FullPath()
+	if path == "" {
+		path = c.Request.URL.Path
+	}
+	fields := []zap.Field{
+		zap.String("method", c.Request.Method),
+		zap.String("path", path),
+		zap.Int("status", status),
+		zap.Duration("latency", time.Since(start)),
+		zap.Int64("bytes_in", max(c.Request.ContentLength, 0)),
+		zap.Int("bytes_out", max(c.Writer.Size(), 0)),
+		zap.String("client_ip", c.ClientIP()),
+		zap.String(requestid.LogField, c.GetString(requestid.ContextKey)),
+	}
+	if apiKey := c.GetString("api_key"); apiKey != "" {
+		fields = append(fields, zap.String("api_key", al.APIKeyName(apiKey)))
+	}
+	// Топик берется из пути (NDJSON, CSV, администрирование) или из тела запроса, если его разобрал обработчик
+	topic := c.Param("topic")
+	if topic == "" {
+		topic = c.GetString("topic")
+	}
+	if topic != "" {
+		fields = append(fields, zap.String("topic", topic))
+	}
+	if errs := c.Errors.ByType(gin.ErrorTypePrivate).String(); errs != "" {
+		fields = append(fields, zap.String("errors", errs))
+	}
+	entry.Write(fields...)
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"kafkaGateway/propagation"
+	"kafkaGateway/requestid"
+)
+
+func TestAccessLogMiddleware_Log(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	accessLogMiddleware := NewAccessLogMiddleware(zap.New(core), 0, nil)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(requestid.ContextKey, "req-1")
+		c.Set("api_key", "secret-key")
+		c.Next()
+	})
+	router.Use(accessLogMiddleware.Log)
+	router.POST("/topics/:topic/ndjson", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	router.POST("/message", func(c *gin.Context) {
+		c.Set("topic", "orders")
+		c.String(http.StatusNotFound, "topic not found")
+	})
+
+	// Успешные запросы при SampleRatio 0 не пишутся
+	req, _ := http.NewRequest("POST", "/topics/events/ndjson", strings.NewReader("{}"))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	if logs.Len() != 0 {
+		t.Fatalf("Expected successful request to be sampled out, got %d entries", logs.Len())
+	}
+
+	req, _ = http.NewRequest("POST", "/message", strings.NewReader(`{"topic":"orders"}`))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	if logs.Len() != 1 {
+		t.Fatalf("Expected error request to be logged, got %d entries", logs.Len())
+	}
+
+	entry := logs.All()[0]
+	if entry.Level != zapcore.WarnLevel {
+		t.Errorf("Expected warn level for 404, got %s", entry.Level)
+	}
+	fields := entry.ContextMap()
+	expected := map[string]interface{}{
+		"method":     "POST",
+		"path":       "/message",
+		"status":     int64(http.StatusNotFound),
+		"bytes_in":   int64(18),
+		"bytes_out":  int64(len("topic not found")),
+		"request_id": "req-1",
+		"api_key":    propagation.Fingerprint("secret-key"),
+		"topic":      "orders",
+	}
+	for name, value := range expected {
+		if fields[name] != value {
+			t.Errorf("Expected field %s=%v, got %v", name, value, fields[name])
+		}
+	}
+	if _, ok := fields["latency"]; !ok {
+		t.Errorf("Expected latency field, got %v", fields)
+	}
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+
+	"kafkaGateway/requestid"
 )
 
 type AuthMiddleware struct {
@@ -24,7 +26,7 @@ func (am *AuthMiddleware) AuthRequired(c *gin.Context) {
 	authHeader := c.GetHeader("Authorization")
 
 	if authHeader == "" {
-		am.Logger.Info("Missing authorization header")
+		requestid.Logger(c.Request.Context(), am.Logger).Info("Missing authorization header")
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing authorization header"})
 		c.Abort()
 		return
@@ -48,7 +50,7 @@ func (am *AuthMiddleware) AuthRequired(c *gin.Context) {
 	}
 
 	if !isValid {
-		am.Logger.Info("Invalid API key provided", zap.String("api_key", apiKey))
+		requestid.Logger(c.Request.Context(), am.Logger).Info("Invalid API key provided", zap.String("api_key", apiKey))
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
 		c.Abort()
 		return
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"kafkaGateway/requestid"
+)
+
+// BodyLimitMiddleware ограничивает размер тела запроса до его чтения обработчиками
+type BodyLimitMiddleware struct {
+	// MaxSize максимальный размер тела; 0 - без ограничения
+	MaxSize int64
+	Logger  *zap.Logger
+}
+
+func NewBodyLimitMiddleware(maxSize int64, logger *zap.Logger) *BodyLimitMiddleware {
+	return &BodyLimitMiddleware{
+		MaxSize: maxSize,
+		Logger:  logger,
+	}
+}
+
+func (bm *BodyLimitMiddleware) Limit(c *gin.Context) {
+	if bm.MaxSize <= 0 {
+		c.Next()
+		return
+	}
+
+	// Запросы с заведомо большим телом отклоняются без чтения
+	if c.Request.ContentLength > bm.MaxSize {
+		requestid.Logger(c.Request.Context(), bm.Logger).Info("Request body too large",
+			zap.String("path", c.Request.URL.Path),
+			zap.Int64("content_length", c.Request.ContentLength),
+			zap.Int64("limit", bm.MaxSize))
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error": fmt.Sprintf("Request body exceeds %d bytes", bm.MaxSize),
+			"limit": bm.MaxSize,
+		})
+		c.Abort()
+		return
+	}
+
+	// Тело без Content-Length (chunked) обрезается при чтении: обработчик получит *http.MaxBytesError
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, bm.MaxSize)
+	c.Next()
+}
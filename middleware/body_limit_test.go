@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func TestBodyLimitMiddleware_Limit(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	bodyLimitMiddleware := NewBodyLimitMiddleware(16, logger)
+
+	router := gin.New()
+	router.Use(bodyLimitMiddleware.Limit)
+	router.POST("/message", func(c *gin.Context) {
+		_, err := io.ReadAll(c.Request.Body)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.Status(http.StatusRequestEntityTooLarge)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name           string
+		body           string
+		chunked        bool
+		expectedStatus int
+	}{
+		{name: "within limit", body: "small body", expectedStatus: http.StatusOK},
+		{name: "content length over limit", body: strings.Repeat("a", 17), expectedStatus: http.StatusRequestEntityTooLarge},
+		{name: "chunked over limit", body: strings.Repeat("a", 17), chunked: true, expectedStatus: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/message", strings.NewReader(tt.body))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if w.Code == http.StatusRequestEntityTooLarge && !tt.chunked && !strings.Contains(w.Body.String(), `"limit":16`) {
+				t.Errorf("Expected limit in response, got %s", w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/flate"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zlib"
+	"github.com/klauspost/compress/zstd"
+	"go.uber.org/zap"
+
+	"kafkaGateway/requestid"
+)
+
+// DecompressMiddleware распаковывает тела запросов с Content-Encoding gzip, deflate или zstd
+type DecompressMiddleware struct {
+	// MaxSize максимальный размер распакованного тела, защищает от zip-бомб; 0 - без ограничения
+	// (для потоковых загрузок, которые читают тело построчно)
+	MaxSize int64
+	Logger  *zap.Logger
+}
+
+func NewDecompressMiddleware(maxSize int64, logger *zap.Logger) *DecompressMiddleware {
+	return &DecompressMiddleware{
+		MaxSize: maxSize,
+		Logger:  logger,
+	}
+}
+
+func (dm *DecompressMiddleware) Decompress(c *gin.Context) {
+	encodings := contentEncodings(c.GetHeader("Content-Encoding"))
+	if len(encodings) == 0 {
+		c.Next()
+		return
+	}
+
+	// Кодировки применялись в порядке перечисления, поэтому снимаются с конца
+	body := c.Request.Body
+	closers := []io.Closer{body}
+	for i := len(encodings) - 1; i >= 0; i-- {
+		reader, err := decoderFor(encodings[i], body, dm.MaxSize)
+		if err != nil {
+			requestid.Logger(c.Request.Context(), dm.Logger).Info("Failed to decompress request body",
+				zap.String("content_encoding", encodings[i]),
+				zap.Error(err))
+			status := http.StatusBadRequest
+			if errors.Is(err, errUnsupportedEncoding) {
+				status = http.StatusUnsupportedMediaType
+			}
+			c.JSON(status, gin.H{"error": fmt.Sprintf("Failed to decompress request body (%s): %v", encodings[i], err)})
+			c.Abort()
+			return
+		}
+		body = reader
+		closers = append(closers, reader)
+	}
+
+	var reader io.Reader = body
+	if dm.MaxSize > 0 {
+		reader = &limitedReader{reader: body, remaining: dm.MaxSize, limit: dm.MaxSize}
+	}
+	c.Request.Body = &decompressedBody{
+		Reader:  reader,
+		closers: closers,
+	}
+	c.Request.Header.Del("Content-Encoding")
+	c.Request.Header.Del("Content-Length")
+	c.Request.ContentLength = -1
+
+	c.Next()
+}
+
+var errUnsupportedEncoding = errors.New("unsupported content encoding")
+
+// contentEncodings разбирает заголовок Content-Encoding, отбрасывая identity
+func contentEncodings(header string) []string {
+	var encodings []string
+	for _, encoding := range strings.Split(header, ",") {
+		encoding = strings.ToLower(strings.TrimSpace(encoding))
+		if encoding != "" && encoding != "identity" {
+			encodings = append(encodings, encoding)
+		}
+	}
+	return encodings
+}
+
+func decoderFor(encoding string, body io.ReadCloser, maxSize int64) (io.ReadCloser, error) {
+	switch encoding {
+	case "gzip", "x-gzip":
+		return gzip.NewReader(body)
+	case "deflate":
+		return newDeflateReader(body)
+	case "zstd":
+		options := []zstd.DOption{zstd.WithDecoderConcurrency(1)}
+		if maxSize > 0 {
+			options = append(options, zstd.WithDecoderMaxMemory(uint64(maxSize)))
+		}
+		decoder, err := zstd.NewReader(body, options...)
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	}
+	return nil, errUnsupportedEncoding
+}
+
+// newDeflateReader читает deflate в формате zlib (RFC 1950), как требует HTTP,
+// и "сырой" deflate (RFC 1951), который отправляют некоторые клиенты
+func newDeflateReader(body io.Reader) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(body)
+	header, err := buffered.Peek(2)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(buffered)
+	}
+	return flate.NewReader(buffered), nil
+}
+
+// decompressedBody распакованное тело запроса, закрывающее все декодеры и исходное тело
+type decompressedBody struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (b *decompressedBody) Close() error {
+	var firstErr error
+	for i := len(b.closers) - 1; i >= 0; i-- {
+		if err := b.closers[i].Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// limitedReader возвращает *http.MaxBytesError, если данных больше limit
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+	limit     int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		var probe [1]byte
+		n, err := l.reader.Read(probe[:])
+		if n > 0 {
+			return 0, &http.MaxBytesError{Limit: l.limit}
+		}
+		return 0, err
+	}
+
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.reader.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
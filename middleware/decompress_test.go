@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/flate"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zlib"
+	"github.com/klauspost/compress/zstd"
+	"go.uber.org/zap"
+)
+
+func TestDecompressMiddleware_Decompress(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	gin.SetMode(gin.TestMode)
+
+	payload := []byte(`{"topic":"events","value":"` + strings.Repeat("a", 1000) + `"}`)
+
+	compress := func(newWriter func(io.Writer) io.WriteCloser, data []byte) []byte {
+		var buf bytes.Buffer
+		w := newWriter(&buf)
+		w.Write(data)
+		w.Close()
+		return buf.Bytes()
+	}
+	gzipWriter := func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }
+	zlibWriter := func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }
+	flateWriter := func(w io.Writer) io.WriteCloser {
+		fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+		return fw
+	}
+	zstdWriter := func(w io.Writer) io.WriteCloser {
+		zw, _ := zstd.NewWriter(w)
+		return zw
+	}
+
+	tests := []struct {
+		name           string
+		encoding       string
+		body           []byte
+		maxSize        int64
+		expectedStatus int
+	}{
+		{name: "no encoding", body: payload, maxSize: 4096, expectedStatus: http.StatusOK},
+		{name: "gzip", encoding: "gzip", body: compress(gzipWriter, payload), maxSize: 4096, expectedStatus: http.StatusOK},
+		{name: "deflate zlib", encoding: "deflate", body: compress(zlibWriter, payload), maxSize: 4096, expectedStatus: http.StatusOK},
+		{name: "deflate raw", encoding: "deflate", body: compress(flateWriter, payload), maxSize: 4096, expectedStatus: http.StatusOK},
+		{name: "zstd", encoding: "zstd", body: compress(zstdWriter, payload), maxSize: 4096, expectedStatus: http.StatusOK},
+		{
+			name:           "gzip then zstd",
+			encoding:       "gzip, zstd",
+			body:           compress(zstdWriter, compress(gzipWriter, payload)),
+			maxSize:        4096,
+			expectedStatus: http.StatusOK,
+		},
+		{name: "too large", encoding: "gzip", body: compress(gzipWriter, payload), maxSize: 100, expectedStatus: http.StatusRequestEntityTooLarge},
+		{name: "unsupported encoding", encoding: "br", body: payload, maxSize: 4096, expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "corrupt gzip", encoding: "gzip", body: payload, maxSize: 4096, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decompressMiddleware := NewDecompressMiddleware(tt.maxSize, logger)
+
+			router := gin.New()
+			router.Use(decompressMiddleware.Decompress)
+			router.POST("/message", func(c *gin.Context) {
+				body, err := io.ReadAll(c.Request.Body)
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					c.Status(http.StatusRequestEntityTooLarge)
+					return
+				}
+				if err != nil || !bytes.Equal(body, payload) {
+					t.Errorf("Unexpected body %q, error %v", body, err)
+				}
+				if c.GetHeader("Content-Encoding") != "" {
+					t.Errorf("Expected Content-Encoding to be removed")
+				}
+				c.Status(http.StatusOK)
+			})
+
+			req, _ := http.NewRequest("POST", "/message", bytes.NewReader(tt.body))
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d. Response body: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
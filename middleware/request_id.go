@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"kafkaGateway/requestid"
+)
+
+// RequestIDMiddleware присваивает запросу идентификатор: принимает X-Request-ID клиента
+// или создает новый, если заголовка нет или он некорректен
+type RequestIDMiddleware struct {
+	Logger *zap.Logger
+}
+
+func NewRequestIDMiddleware(logger *zap.Logger) *RequestIDMiddleware {
+	return &RequestIDMiddleware{
+		Logger: logger,
+	}
+}
+
+func (rm *RequestIDMiddleware) Assign(c *gin.Context) {
+	id := c.GetHeader(requestid.Header)
+	if id != "" && !requestid.Valid(id) {
+		rm.Logger.Debug("Invalid request ID replaced", zap.Int("length", len(id)))
+		id = ""
+	}
+	if id == "" {
+		id = requestid.New()
+	}
+
+	c.Set(requestid.ContextKey, id)
+	c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), id))
+	c.Header(requestid.Header, id)
+	c.Next()
+}
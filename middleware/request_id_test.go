@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"kafkaGateway/requestid"
+)
+
+func TestRequestIDMiddleware_Assign(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	requestIDMiddleware := NewRequestIDMiddleware(zap.NewNop())
+
+	var contextID string
+	router := gin.New()
+	router.Use(requestIDMiddleware.Assign)
+	router.GET("/", func(c *gin.Context) {
+		contextID = requestid.FromContext(c.Request.Context())
+		c.String(http.StatusOK, c.GetString(requestid.ContextKey))
+	})
+
+	tests := []struct {
+		name      string
+		header    string
+		preserved bool
+	}{
+		{name: "client ID", header: "php-req-42", preserved: true},
+		{name: "missing ID", header: ""},
+		{name: "invalid ID", header: "bad id\twith tab"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set(requestid.Header, tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			id := w.Header().Get(requestid.Header)
+			if tt.preserved && id != tt.header {
+				t.Errorf("Expected request ID %q, got %q", tt.header, id)
+			}
+			if !tt.preserved && (id == tt.header || !requestid.Valid(id)) {
+				t.Errorf("Expected generated request ID, got %q", id)
+			}
+			if w.Body.String() != id || contextID != id {
+				t.Errorf("Expected request ID %q in gin and request context, got %q and %q", id, w.Body.String(), contextID)
+			}
+		})
+	}
+}
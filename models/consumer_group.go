@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Стратегии сброса смещений группы потребителей
+const (
+	ResetToEarliest  = "earliest"
+	ResetToLatest    = "latest"
+	ResetToTimestamp = "timestamp"
+	ResetToOffset    = "offset"
+)
+
+// ConsumerGroupInfo краткая информация о группе потребителей
+type ConsumerGroupInfo struct {
+	GroupID string `json:"group_id"`
+	State   string `json:"state"`
+	Members int    `json:"members"`
+}
+
+// ConsumerGroupMember участник группы и назначенные ему партиции
+type ConsumerGroupMember struct {
+	MemberID    string           `json:"member_id"`
+	ClientID    string           `json:"client_id"`
+	ClientHost  string           `json:"client_host"`
+	Assignments map[string][]int `json:"assignments"`
+}
+
+// PartitionLag смещения и отставание группы по одной партиции
+type PartitionLag struct {
+	Topic           string `json:"topic"`
+	Partition       int    `json:"partition"`
+	CommittedOffset int64  `json:"committed_offset"`
+	LogStartOffset  int64  `json:"log_start_offset"`
+	LogEndOffset    int64  `json:"log_end_offset"`
+	Lag             int64  `json:"lag"`
+	MemberID        string `json:"member_id,omitempty"`
+}
+
+// ConsumerGroupDescription подробная информация о группе потребителей
+type ConsumerGroupDescription struct {
+	GroupID    string                `json:"group_id"`
+	State      string                `json:"state"`
+	Members    []ConsumerGroupMember `json:"members"`
+	Partitions []PartitionLag        `json:"partitions"`
+	TotalLag   int64                 `json:"total_lag"`
+}
+
+type ResetOffsetsRequest struct {
+	Topic      string     `json:"topic" binding:"required"`
+	Partitions []int      `json:"partitions,omitempty"`
+	Strategy   string     `json:"strategy" binding:"required,oneof=earliest latest timestamp offset"`
+	Timestamp  *time.Time `json:"timestamp,omitempty"`
+	Offset     *int64     `json:"offset,omitempty"`
+}
+
+// PartitionOffset новое смещение группы по партиции
+type PartitionOffset struct {
+	Topic     string `json:"topic"`
+	Partition int    `json:"partition"`
+	Offset    int64  `json:"offset"`
+}
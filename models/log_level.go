@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// LogLevelRequest изменение уровня логирования; TTL в формате Go duration ("15m"), пустой - без возврата
+type LogLevelRequest struct {
+	Level string `json:"level" binding:"required"`
+	TTL   string `json:"ttl,omitempty"`
+}
+
+// LogLevel уровень логирования общий (component = global) или компонента
+type LogLevel struct {
+	Component string `json:"component"`
+	Level     string `json:"level"`
+	// Inherited компонент использует общий уровень
+	Inherited bool       `json:"inherited,omitempty"`
+	RevertAt  *time.Time `json:"revert_at,omitempty"`
+}
@@ -7,15 +7,54 @@ type MessageRequest struct {
 	Key     string            `json:"key,omitempty"`
 	Value   interface{}       `json:"value" binding:"required"`
 	Headers map[string]string `json:"headers,omitempty"`
+	// Partition явно заданная партиция; без нее партицию выбирает стратегия топика
+	Partition *int `json:"partition,omitempty" binding:"omitempty,min=0"`
+	// Timestamp время записи: строка RFC3339 или число миллисекунд Unix epoch
+	Timestamp interface{} `json:"timestamp,omitempty"`
 }
 
 type MessageResponse struct {
-	Success   bool      `json:"success"`
-	Message   string    `json:"message,omitempty"`
-	Error     string    `json:"error,omitempty"`
+	Success bool         `json:"success"`
+	Message string       `json:"message,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Topics  []string     `json:"topics,omitempty"`
+	Errors  []FieldError `json:"errors,omitempty"`
+	Limit   int64        `json:"limit,omitempty"`
+	// RequestID идентификатор запроса из X-Request-ID, он же в логах и заголовке x-request-id записей
+	RequestID string    `json:"request_id,omitempty"`
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// FieldError ошибка проверки значения сообщения с путем к полю в формате JSON Pointer
+type FieldError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// BatchResponse итог пакетной загрузки сообщений
+type BatchResponse struct {
+	Success bool   `json:"success"`
+	Topic   string `json:"topic"`
+	Total   int    `json:"total"`
+	Sent    int    `json:"sent"`
+	Failed  int    `json:"failed"`
+	// Error причина прерывания загрузки, если она остановлена до конца тела запроса
+	Error  string        `json:"error,omitempty"`
+	Errors []RecordError `json:"errors,omitempty"`
+	// ErrorsTruncated сообщает, что в ответ попали не все ошибки записей
+	ErrorsTruncated bool      `json:"errors_truncated,omitempty"`
+	RequestID       string    `json:"request_id,omitempty"`
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+// RecordError ошибка отдельной записи пакетной загрузки
+type RecordError struct {
+	// Line номер строки тела запроса, начиная с 1
+	Line   int          `json:"line"`
+	Error  string       `json:"error"`
+	Fields []FieldError `json:"fields,omitempty"`
+}
+
 type KafkaMessage struct {
 	Topic     string
 	Key       []byte
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Типы схем локального хранилища
+const (
+	SchemaTypeJSON = "JSON"
+	SchemaTypeAvro = "AVRO"
+)
+
+// Режимы совместимости новых версий схемы с последней зарегистрированной
+const (
+	// CompatibilityBackward новая схема читает данные, записанные по предыдущей
+	CompatibilityBackward = "BACKWARD"
+	// CompatibilityForward предыдущая схема читает данные, записанные по новой
+	CompatibilityForward = "FORWARD"
+	// CompatibilityFull совместимость в обе стороны
+	CompatibilityFull = "FULL"
+	// CompatibilityNone без проверки
+	CompatibilityNone = "NONE"
+)
+
+// SchemaVersion версия схемы топика
+type SchemaVersion struct {
+	Version   int             `json:"version"`
+	Type      string          `json:"type"`
+	Schema    json.RawMessage `json:"schema"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// TopicSchemaInfo сводка по схемам топика
+type TopicSchemaInfo struct {
+	Topic         string `json:"topic"`
+	Type          string `json:"type"`
+	Compatibility string `json:"compatibility"`
+	// PinnedVersion версия, закрепленная за топиком; 0 - используется последняя
+	PinnedVersion int   `json:"pinned_version,omitempty"`
+	ActiveVersion int   `json:"active_version"`
+	Versions      []int `json:"versions"`
+}
+
+// RegisterSchemaRequest запрос на регистрацию новой версии схемы
+type RegisterSchemaRequest struct {
+	Type   string          `json:"type" binding:"omitempty,oneof=JSON AVRO"`
+	Schema json.RawMessage `json:"schema" binding:"required"`
+}
+
+// SchemaConfigRequest изменение режима совместимости и закрепленной версии топика
+type SchemaConfigRequest struct {
+	Compatibility string `json:"compatibility" binding:"omitempty,oneof=BACKWARD FORWARD FULL NONE"`
+	// PinnedVersion закрепляет версию, 0 снимает закрепление
+	PinnedVersion *int `json:"pinned_version" binding:"omitempty,min=0"`
+}
+
+// SchemaChange отличие между двумя версиями схемы
+type SchemaChange struct {
+	Path   string      `json:"path"`
+	Change string      `json:"change"`
+	From   interface{} `json:"from,omitempty"`
+	To     interface{} `json:"to,omitempty"`
+}
+
+// SchemaDiff отличия между версиями схемы топика
+type SchemaDiff struct {
+	Topic   string         `json:"topic"`
+	From    int            `json:"from"`
+	To      int            `json:"to"`
+	Changes []SchemaChange `json:"changes"`
+}
@@ -0,0 +1,45 @@
+package models
+
+// TopicInfo краткая информация о топике Kafka
+type TopicInfo struct {
+	Name              string `json:"name"`
+	Partitions        int    `json:"partitions"`
+	ReplicationFactor int    `json:"replication_factor"`
+	Internal          bool   `json:"internal"`
+}
+
+// TopicConfigEntry параметр конфигурации топика
+type TopicConfigEntry struct {
+	Name        string `json:"name"`
+	Value       string `json:"value"`
+	ReadOnly    bool   `json:"read_only"`
+	IsDefault   bool   `json:"is_default"`
+	IsSensitive bool   `json:"is_sensitive"`
+}
+
+// TopicDescription подробная информация о топике вместе с его конфигурацией
+type TopicDescription struct {
+	TopicInfo
+	Configs []TopicConfigEntry `json:"configs"`
+}
+
+// TopicWriteStats накопленная статистика записи producer в топик
+type TopicWriteStats struct {
+	// Messages записанные сообщения
+	Messages int64 `json:"messages"`
+	// Bytes объем записанных сообщений: ключи, значения и заголовки
+	Bytes int64 `json:"bytes"`
+	// Errors сообщения, которые не удалось записать
+	Errors int64 `json:"errors"`
+}
+
+type CreateTopicRequest struct {
+	Name              string            `json:"name" binding:"required"`
+	Partitions        int               `json:"partitions" binding:"required,min=1"`
+	ReplicationFactor int               `json:"replication_factor" binding:"required,min=1"`
+	Configs           map[string]string `json:"configs,omitempty"`
+}
+
+type CreatePartitionsRequest struct {
+	Count int `json:"count" binding:"required,min=1"`
+}
@@ -0,0 +1,146 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"kafkaGateway/config"
+	"kafkaGateway/kafka"
+	"kafkaGateway/models"
+	"kafkaGateway/requestid"
+)
+
+// ErrTopicDenied возвращается, если политика запрещает отправку в топик
+var ErrTopicDenied = errors.New("topic is not allowed")
+
+// Время, в течение которого топик считается существующим без повторного запроса метаданных
+const knownTopicTTL = 5 * time.Minute
+
+// AdminInterface административные вызовы, необходимые политике
+type AdminInterface interface {
+	TopicExists(ctx context.Context, topic string) (bool, error)
+	CreateTopic(ctx context.Context, req models.CreateTopicRequest) error
+}
+
+// TopicPolicy решает, можно ли отправлять сообщения в топик, и при необходимости создает его
+type TopicPolicy struct {
+	config config.TopicPolicyConfig
+	admin  AdminInterface
+	logger *zap.Logger
+
+	mu    sync.Mutex
+	known map[string]time.Time
+}
+
+func NewTopicPolicy(cfg config.TopicPolicyConfig, admin AdminInterface, logger *zap.Logger) *TopicPolicy {
+	if cfg.DefaultAction == "" {
+		cfg.DefaultAction = config.TopicActionExisting
+	}
+
+	return &TopicPolicy{
+		config: cfg,
+		admin:  admin,
+		logger: logger,
+		known:  make(map[string]time.Time),
+	}
+}
+
+// Check проверяет топик по политике. Возвращает ErrTopicDenied, если топик запрещен,
+// и kafka.ErrTopicNotFound, если топик не существует и не может быть создан
+func (tp *TopicPolicy) Check(ctx context.Context, topic string) error {
+	rule := tp.match(topic)
+
+	action := tp.config.DefaultAction
+	if rule != nil {
+		action = rule.Action
+	}
+
+	if action == config.TopicActionDeny {
+		return ErrTopicDenied
+	}
+
+	if tp.isKnown(topic) {
+		return nil
+	}
+
+	exists, err := tp.admin.TopicExists(ctx, topic)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		if action != config.TopicActionCreate {
+			return kafka.ErrTopicNotFound
+		}
+		if err := tp.create(ctx, topic, rule); err != nil {
+			return err
+		}
+	}
+
+	tp.remember(topic)
+	return nil
+}
+
+// Forget сбрасывает кэш существования топика; вызывается после удаления топика через административный API
+func (tp *TopicPolicy) Forget(topic string) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	delete(tp.known, topic)
+}
+
+func (tp *TopicPolicy) match(topic string) *config.TopicRule {
+	for i := range tp.config.Rules {
+		if ok, _ := path.Match(tp.config.Rules[i].Pattern, topic); ok {
+			return &tp.config.Rules[i]
+		}
+	}
+	return nil
+}
+
+func (tp *TopicPolicy) create(ctx context.Context, topic string, rule *config.TopicRule) error {
+	configs := make(map[string]string, len(rule.Configs)+1)
+	for k, v := range rule.Configs {
+		configs[k] = v
+	}
+	if rule.Retention > 0 {
+		configs["retention.ms"] = strconv.FormatInt(rule.Retention.Milliseconds(), 10)
+	}
+
+	err := tp.admin.CreateTopic(ctx, models.CreateTopicRequest{
+		Name:              topic,
+		Partitions:        rule.Partitions,
+		ReplicationFactor: rule.ReplicationFactor,
+		Configs:           configs,
+	})
+	// Топик мог создать параллельный запрос
+	if err != nil && !errors.Is(err, kafka.ErrTopicAlreadyExists) {
+		requestid.Logger(ctx, tp.logger).Error("Failed to auto-create topic", zap.String("topic", topic), zap.Error(err))
+		return err
+	}
+
+	requestid.Logger(ctx, tp.logger).Info("Topic auto-created by policy",
+		zap.String("topic", topic),
+		zap.String("pattern", rule.Pattern))
+
+	return nil
+}
+
+func (tp *TopicPolicy) isKnown(topic string) bool {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	checkedAt, ok := tp.known[topic]
+	return ok && time.Since(checkedAt) < knownTopicTTL
+}
+
+func (tp *TopicPolicy) remember(topic string) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	tp.known[topic] = time.Now()
+}
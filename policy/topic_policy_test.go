@@ -0,0 +1,117 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"kafkaGateway/config"
+	"kafkaGateway/kafka"
+	"kafkaGateway/models"
+)
+
+// MockAdmin - имитация административного клиента для тестирования
+type MockAdmin struct {
+	Topics      map[string]bool
+	Created     []models.CreateTopicRequest
+	ExistsCalls int
+}
+
+func (m *MockAdmin) TopicExists(ctx context.Context, topic string) (bool, error) {
+	m.ExistsCalls++
+	return m.Topics[topic], nil
+}
+
+func (m *MockAdmin) CreateTopic(ctx context.Context, req models.CreateTopicRequest) error {
+	m.Created = append(m.Created, req)
+	m.Topics[req.Name] = true
+	return nil
+}
+
+func TestTopicPolicyCheck(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	cfg := config.TopicPolicyConfig{
+		DefaultAction: config.TopicActionDeny,
+		Rules: []config.TopicRule{
+			{Pattern: "orders.*", Action: config.TopicActionExisting},
+			{Pattern: "tmp.*", Action: config.TopicActionDeny},
+			{
+				Pattern:           "events.*",
+				Action:            config.TopicActionCreate,
+				Partitions:        6,
+				ReplicationFactor: 3,
+				Retention:         24 * time.Hour,
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		topic       string
+		expectedErr error
+		created     bool
+	}{
+		{name: "existing allowed topic", topic: "orders.created", expectedErr: nil},
+		{name: "missing allowed topic", topic: "orders.typo", expectedErr: kafka.ErrTopicNotFound},
+		{name: "denied by rule", topic: "tmp.debug", expectedErr: ErrTopicDenied},
+		{name: "not matching any rule", topic: "users", expectedErr: ErrTopicDenied},
+		{name: "auto-created topic", topic: "events.clicks", expectedErr: nil, created: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admin := &MockAdmin{Topics: map[string]bool{"orders.created": true, "users": true}}
+			topicPolicy := NewTopicPolicy(cfg, admin, logger)
+
+			err := topicPolicy.Check(context.Background(), tt.topic)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if tt.created {
+				if len(admin.Created) != 1 {
+					t.Fatalf("Expected topic to be created, got %v", admin.Created)
+				}
+				created := admin.Created[0]
+				if created.Partitions != 6 || created.ReplicationFactor != 3 {
+					t.Errorf("Unexpected topic template: %+v", created)
+				}
+				if created.Configs["retention.ms"] != "86400000" {
+					t.Errorf("Expected retention.ms=86400000, got %s", created.Configs["retention.ms"])
+				}
+			} else if len(admin.Created) != 0 {
+				t.Errorf("Expected no topics to be created, got %v", admin.Created)
+			}
+		})
+	}
+}
+
+func TestTopicPolicyCachesExistingTopics(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	admin := &MockAdmin{Topics: map[string]bool{"users": true}}
+	topicPolicy := NewTopicPolicy(config.TopicPolicyConfig{}, admin, logger)
+
+	for i := 0; i < 3; i++ {
+		if err := topicPolicy.Check(context.Background(), "users"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if admin.ExistsCalls != 1 {
+		t.Errorf("Expected 1 metadata lookup, got %d", admin.ExistsCalls)
+	}
+
+	topicPolicy.Forget("users")
+	topicPolicy.Check(context.Background(), "users")
+
+	if admin.ExistsCalls != 2 {
+		t.Errorf("Expected metadata lookup after Forget, got %d calls", admin.ExistsCalls)
+	}
+}
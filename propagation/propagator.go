@@ -0,0 +1,140 @@
+package propagation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"kafkaGateway/config"
+)
+
+// Имена заголовков метаданных шлюза без префикса
+const (
+	MetadataInstance   = "instance"
+	MetadataAPIKey     = "api-key"
+	MetadataClientIP   = "client-ip"
+	MetadataReceivedAt = "received-at"
+)
+
+// Headers заголовки записей, полученные из HTTP запроса
+type Headers struct {
+	// Forwarded перенесенные заголовки запроса; заголовки из тела запроса имеют приоритет
+	Forwarded map[string]string
+	// Metadata метаданные шлюза; имеют приоритет над остальными заголовками, чтобы клиент не мог их подменить
+	Metadata map[string]string
+}
+
+// Merge объединяет заголовки записи из тела запроса с заголовками HTTP запроса.
+// Возвращает новую map, исходная не изменяется
+func (h Headers) Merge(headers map[string]string) map[string]string {
+	if len(h.Forwarded) == 0 && len(h.Metadata) == 0 {
+		return headers
+	}
+
+	merged := make(map[string]string, len(h.Forwarded)+len(headers)+len(h.Metadata))
+	for _, source := range []map[string]string{h.Forwarded, headers, h.Metadata} {
+		for name, value := range source {
+			merged[name] = value
+		}
+	}
+	return merged
+}
+
+// Propagator переносит заголовки HTTP запроса по правилам конфигурации и добавляет метаданные шлюза
+type Propagator struct {
+	forward  []config.HeaderForward
+	metadata config.HeaderMetadata
+	instance string
+}
+
+func NewPropagator(cfg config.HeadersConfig) *Propagator {
+	forward := make([]config.HeaderForward, len(cfg.Forward))
+	for i, rule := range cfg.Forward {
+		forward[i] = config.HeaderForward{Header: strings.ToLower(rule.Header), As: rule.As}
+	}
+
+	instance := cfg.Metadata.Instance
+	if instance == "" {
+		instance, _ = os.Hostname()
+	}
+
+	return &Propagator{
+		forward:  forward,
+		metadata: cfg.Metadata,
+		instance: instance,
+	}
+}
+
+// Collect возвращает заголовки записей для запроса. clientIP и apiKey определяются
+// HTTP слоем (с учетом доверенных прокси и аутентификации)
+func (p *Propagator) Collect(r *http.Request, clientIP, apiKey string) Headers {
+	var headers Headers
+
+	for name, values := range r.Header {
+		lower := strings.ToLower(name)
+		for _, rule := range p.forward {
+			if matched, _ := path.Match(rule.Header, lower); !matched {
+				continue
+			}
+			if headers.Forwarded == nil {
+				headers.Forwarded = make(map[string]string)
+			}
+			headers.Forwarded[targetName(rule, lower)] = strings.Join(values, ", ")
+			break
+		}
+	}
+
+	if p.metadata.Disabled {
+		return headers
+	}
+
+	prefix := p.metadata.Prefix
+	headers.Metadata = map[string]string{
+		prefix + MetadataReceivedAt: time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	if p.instance != "" {
+		headers.Metadata[prefix+MetadataInstance] = p.instance
+	}
+	if clientIP != "" {
+		headers.Metadata[prefix+MetadataClientIP] = clientIP
+	}
+	if apiKey != "" {
+		headers.Metadata[prefix+MetadataAPIKey] = p.APIKeyName(apiKey)
+	}
+	return headers
+}
+
+// APIKeyName возвращает имя ключа из конфигурации или его отпечаток; сам ключ в записи не попадает
+func (p *Propagator) APIKeyName(apiKey string) string {
+	fingerprint := Fingerprint(apiKey)
+	if name, ok := p.metadata.APIKeyNames[fingerprint]; ok {
+		return name
+	}
+	return fingerprint
+}
+
+// Fingerprint отпечаток API ключа: sha256:<первые 16 hex символов SHA-256>
+func Fingerprint(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
+// targetName имя заголовка записи для совпавшего правила
+func targetName(rule config.HeaderForward, name string) string {
+	if rule.As == "" {
+		return name
+	}
+	if !strings.Contains(rule.As, "*") {
+		return rule.As
+	}
+
+	// Часть имени, совпавшая с единственной * шаблона
+	star := strings.Index(rule.Header, "*")
+	prefix, suffix := rule.Header[:star], rule.Header[star+1:]
+	matched := name[len(prefix) : len(name)-len(suffix)]
+	return strings.ReplaceAll(rule.As, "*", matched)
+}
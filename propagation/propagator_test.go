@@ -0,0 +1,93 @@
+package propagation
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"kafkaGateway/config"
+)
+
+func TestPropagatorCollect(t *testing.T) {
+	propagator := NewPropagator(config.HeadersConfig{
+		Forward: []config.HeaderForward{
+			{Header: "x-request-id"},
+			{Header: "X-Tenant-*", As: "tenant.*"},
+			{Header: "traceparent", As: "trace-parent"},
+		},
+		Metadata: config.HeaderMetadata{
+			Prefix:      "gw-",
+			Instance:    "gateway-1",
+			APIKeyNames: map[string]string{Fingerprint("billing-key"): "billing"},
+		},
+	})
+
+	r, _ := http.NewRequest("POST", "/message", nil)
+	r.Header.Set("X-Request-Id", "req-1")
+	r.Header.Set("X-Tenant-Id", "acme")
+	r.Header.Add("X-Tenant-Region", "eu")
+	r.Header.Add("X-Tenant-Region", "us")
+	r.Header.Set("Traceparent", "00-abc-def-01")
+	r.Header.Set("Authorization", "Bearer billing-key")
+
+	headers := propagator.Collect(r, "10.0.0.1", "billing-key")
+
+	expected := map[string]string{
+		"x-request-id":  "req-1",
+		"tenant.id":     "acme",
+		"tenant.region": "eu, us",
+		"trace-parent":  "00-abc-def-01",
+	}
+	if len(headers.Forwarded) != len(expected) {
+		t.Errorf("Expected forwarded headers %v, got %v", expected, headers.Forwarded)
+	}
+	for name, value := range expected {
+		if headers.Forwarded[name] != value {
+			t.Errorf("Expected header %s=%q, got %q", name, value, headers.Forwarded[name])
+		}
+	}
+
+	if headers.Metadata["gw-instance"] != "gateway-1" ||
+		headers.Metadata["gw-client-ip"] != "10.0.0.1" ||
+		headers.Metadata["gw-api-key"] != "billing" {
+		t.Errorf("Unexpected metadata headers: %v", headers.Metadata)
+	}
+	if _, err := time.Parse(time.RFC3339Nano, headers.Metadata["gw-received-at"]); err != nil {
+		t.Errorf("Expected received-at timestamp, got %q", headers.Metadata["gw-received-at"])
+	}
+
+	unnamed := propagator.Collect(r, "", "other-key")
+	if name := unnamed.Metadata["gw-api-key"]; !strings.HasPrefix(name, "sha256:") || strings.Contains(name, "other-key") {
+		t.Errorf("Expected fingerprint of unnamed key, got %q", name)
+	}
+}
+
+func TestHeadersMerge(t *testing.T) {
+	headers := Headers{
+		Forwarded: map[string]string{"x-request-id": "forwarded", "tenant": "acme"},
+		Metadata:  map[string]string{"gateway-instance": "gateway-1"},
+	}
+	body := map[string]string{"x-request-id": "body", "gateway-instance": "spoofed"}
+
+	merged := headers.Merge(body)
+	if merged["x-request-id"] != "body" || merged["tenant"] != "acme" || merged["gateway-instance"] != "gateway-1" {
+		t.Errorf("Unexpected merged headers: %v", merged)
+	}
+	if body["gateway-instance"] != "spoofed" {
+		t.Errorf("Expected body headers to be unchanged")
+	}
+
+	if merged := (Headers{}).Merge(nil); merged != nil {
+		t.Errorf("Expected nil headers without propagation, got %v", merged)
+	}
+}
+
+func TestPropagatorMetadataDisabled(t *testing.T) {
+	propagator := NewPropagator(config.HeadersConfig{Metadata: config.HeaderMetadata{Disabled: true}})
+	r, _ := http.NewRequest("POST", "/message", nil)
+
+	if headers := propagator.Collect(r, "10.0.0.1", "key"); headers.Metadata != nil || headers.Forwarded != nil {
+		t.Errorf("Expected no headers, got %+v", headers)
+	}
+}
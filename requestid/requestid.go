@@ -0,0 +1,90 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// Header заголовок HTTP запроса и ответа с идентификатором запроса
+	Header = "X-Request-ID"
+	// RecordHeader заголовок записи Kafka с идентификатором запроса
+	RecordHeader = "x-request-id"
+	// ContextKey ключ идентификатора в gin.Context
+	ContextKey = "request_id"
+	// LogField имя поля идентификатора в логах
+	LogField = "request_id"
+
+	// maxLength наибольшая длина идентификатора, принимаемого от клиента
+	maxLength = 128
+)
+
+type contextKey struct{}
+
+// New создает идентификатор запроса (UUID v4)
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid проверяет идентификатор, переданный клиентом: непустой, не длиннее 128 символов,
+// только буквы, цифры и символы ._:-, чтобы его можно было без экранирования писать в логи и заголовки
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		ch := id[i]
+		switch {
+		case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch >= '0' && ch <= '9':
+		case ch == '.', ch == '_', ch == ':', ch == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// NewContext возвращает контекст с идентификатором запроса
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext возвращает идентификатор запроса из контекста или пустую строку
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Logger возвращает логгер с полем request_id, если в контексте есть идентификатор запроса
+func Logger(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	if id := FromContext(ctx); id != "" {
+		return logger.With(zap.String(LogField, id))
+	}
+	return logger
+}
+
+// FromHeaders возвращает поле лога с идентификатором запроса из заголовков записи
+func FromHeaders(headers map[string]string) zap.Field {
+	if id, ok := headers[RecordHeader]; ok {
+		return zap.String(LogField, id)
+	}
+	return zap.Skip()
+}
+
+// Inject добавляет идентификатор запроса из контекста в заголовки записи. Возвращает новую map;
+// без идентификатора заголовки не изменяются
+func Inject(ctx context.Context, headers map[string]string) map[string]string {
+	id := FromContext(ctx)
+	if id == "" {
+		return headers
+	}
+
+	injected := make(map[string]string, len(headers)+1)
+	for name, value := range headers {
+		injected[name] = value
+	}
+	injected[RecordHeader] = id
+	return injected
+}
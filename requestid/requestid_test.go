@@ -0,0 +1,50 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		id    string
+		valid bool
+	}{
+		{id: "0f8fad5b-d9cb-469f-a165-70867728950e", valid: true},
+		{id: "php.req_42:retry-1", valid: true},
+		{id: "", valid: false},
+		{id: "has space", valid: false},
+		{id: "line\nbreak", valid: false},
+		{id: "кириллица", valid: false},
+		{id: strings.Repeat("a", 128), valid: true},
+		{id: strings.Repeat("a", 129), valid: false},
+	}
+
+	for _, tt := range tests {
+		if got := Valid(tt.id); got != tt.valid {
+			t.Errorf("Valid(%q) = %v, expected %v", tt.id, got, tt.valid)
+		}
+	}
+
+	if id := New(); !Valid(id) {
+		t.Errorf("Expected generated ID %q to be valid", id)
+	}
+}
+
+func TestInject(t *testing.T) {
+	headers := map[string]string{"source": "php", RecordHeader: "spoofed"}
+
+	if injected := Inject(context.Background(), headers); injected[RecordHeader] != "spoofed" {
+		t.Errorf("Expected headers without request ID to be unchanged, got %v", injected)
+	}
+
+	ctx := NewContext(context.Background(), "req-1")
+	injected := Inject(ctx, headers)
+	if injected[RecordHeader] != "req-1" || injected["source"] != "php" {
+		t.Errorf("Expected request ID header with existing headers, got %v", injected)
+	}
+	if headers[RecordHeader] != "spoofed" {
+		t.Errorf("Expected original headers to be unchanged")
+	}
+}
@@ -0,0 +1,211 @@
+package routing
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"kafkaGateway/config"
+)
+
+// Message сведения о сообщении, по которым выбираются топики
+type Message struct {
+	Topic   string
+	Key     string
+	Headers map[string]string
+	Value   interface{}
+}
+
+// Router разрешает логические имена топиков и выбирает топики назначения сообщения
+// по правилам маршрутизации. Настройки можно перезагрузить из файла конфигурации без перезапуска шлюза
+type Router struct {
+	configPath string
+	logger     *zap.Logger
+
+	mu  sync.RWMutex
+	cfg config.RoutingConfig
+}
+
+func NewRouter(cfg config.RoutingConfig, configPath string, logger *zap.Logger) *Router {
+	return &Router{
+		configPath: configPath,
+		logger:     logger,
+		cfg:        cfg,
+	}
+}
+
+// Resolve возвращает физический топик для псевдонима с префиксом окружения.
+// Имена, не являющиеся псевдонимами, возвращаются без изменений
+func (r *Router) Resolve(topic string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.resolve(topic)
+}
+
+func (r *Router) resolve(topic string) string {
+	if physical, ok := r.cfg.Aliases[topic]; ok {
+		return r.cfg.Prefix + physical
+	}
+	return topic
+}
+
+// Route возвращает физические топики назначения. Правила проверяются по порядку, совпавшее правило
+// завершает проверку, если у него не задан continue. Без совпадений возвращается топик из запроса.
+// Топики правил и запроса могут быть псевдонимами
+func (r *Router) Route(msg Message) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var topics []string
+	seen := make(map[string]bool)
+	for _, rule := range r.cfg.Rules {
+		if !matches(rule.Match, msg) {
+			continue
+		}
+		for _, topic := range rule.Topics {
+			topic = r.resolve(topic)
+			if !seen[topic] {
+				seen[topic] = true
+				topics = append(topics, topic)
+			}
+		}
+		if !rule.Continue {
+			break
+		}
+	}
+
+	if len(topics) == 0 {
+		return []string{r.resolve(msg.Topic)}
+	}
+	return topics
+}
+
+// Rules возвращает действующие правила
+func (r *Router) Rules() []config.RoutingRule {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cfg.Rules
+}
+
+// Aliases возвращает псевдонимы топиков с разрешенными физическими именами
+func (r *Router) Aliases() map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	aliases := make(map[string]string, len(r.cfg.Aliases))
+	for alias := range r.cfg.Aliases {
+		aliases[alias] = r.resolve(alias)
+	}
+	return aliases
+}
+
+// Update заменяет псевдонимы и правила маршрутизации
+func (r *Router) Update(cfg config.RoutingConfig) {
+	r.mu.Lock()
+	r.cfg = cfg
+	r.mu.Unlock()
+
+	r.logger.Info("Routing config updated",
+		zap.Int("rules", len(cfg.Rules)),
+		zap.Int("aliases", len(cfg.Aliases)))
+}
+
+// Reload перечитывает правила из файла конфигурации. При ошибке действующие правила сохраняются
+func (r *Router) Reload() error {
+	if r.configPath == "" {
+		return fmt.Errorf("gateway config file is not set")
+	}
+
+	fileConfig, err := config.LoadFileConfig(r.configPath)
+	if err != nil {
+		return err
+	}
+
+	r.Update(fileConfig.Routing)
+	return nil
+}
+
+func matches(match config.RoutingMatch, msg Message) bool {
+	if !matchPattern(match.Topic, msg.Topic) || !matchPattern(match.Key, msg.Key) {
+		return false
+	}
+
+	for name, pattern := range match.Headers {
+		value, ok := header(msg.Headers, name)
+		if !ok || !matchPattern(pattern, value) {
+			return false
+		}
+	}
+
+	for fieldPath, expected := range match.Value {
+		actual, ok := lookup(msg.Value, fieldPath)
+		if !ok || !matchValue(expected, actual) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchPattern сверяет значение с шаблоном path.Match; пустой шаблон совпадает с любым значением
+func matchPattern(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	matched, _ := path.Match(pattern, value)
+	return matched
+}
+
+// header ищет заголовок без учета регистра имени
+func header(headers map[string]string, name string) (string, bool) {
+	if value, ok := headers[name]; ok {
+		return value, true
+	}
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// lookup возвращает поле JSON значения по пути через точку
+func lookup(value interface{}, fieldPath string) (interface{}, bool) {
+	current := value
+	for _, name := range strings.Split(fieldPath, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = object[name]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// matchValue сравнивает значения в JSON представлении; список ожидаемых значений означает любое из них
+func matchValue(expected, actual interface{}) bool {
+	if options, ok := expected.([]interface{}); ok {
+		for _, option := range options {
+			if matchValue(option, actual) {
+				return true
+			}
+		}
+		return false
+	}
+
+	expectedJSON, err := json.Marshal(expected)
+	if err != nil {
+		return false
+	}
+	actualJSON, err := json.Marshal(actual)
+	if err != nil {
+		return false
+	}
+	return string(expectedJSON) == string(actualJSON)
+}
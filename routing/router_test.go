@@ -0,0 +1,155 @@
+package routing
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"kafkaGateway/config"
+)
+
+func TestRouterRoute(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	router := NewRouter(config.RoutingConfig{Rules: []config.RoutingRule{
+		{
+			Name:     "audit",
+			Match:    config.RoutingMatch{Headers: map[string]string{"x-audit": "true"}},
+			Topics:   []string{"audit"},
+			Continue: true,
+		},
+		{
+			Name: "orders created",
+			Match: config.RoutingMatch{
+				Topic: "orders",
+				Value: map[string]interface{}{"event_type": "order.created"},
+			},
+			Topics: []string{"orders.created.v2", "analytics"},
+		},
+		{
+			Name: "eu customers",
+			Match: config.RoutingMatch{
+				Key:   "eu-*",
+				Value: map[string]interface{}{"customer.tier": []interface{}{"gold", "silver"}, "amount": 100},
+			},
+			Topics: []string{"orders.eu"},
+		},
+	}}, "", logger)
+
+	tests := []struct {
+		name     string
+		msg      Message
+		expected []string
+	}{
+		{
+			name:     "no rule matched",
+			msg:      Message{Topic: "users", Value: map[string]interface{}{"id": 1.0}},
+			expected: []string{"users"},
+		},
+		{
+			name:     "value field",
+			msg:      Message{Topic: "orders", Value: map[string]interface{}{"event_type": "order.created"}},
+			expected: []string{"orders.created.v2", "analytics"},
+		},
+		{
+			name:     "continue fans out",
+			msg:      Message{Topic: "orders", Headers: map[string]string{"X-Audit": "true"}, Value: map[string]interface{}{"event_type": "order.created"}},
+			expected: []string{"audit", "orders.created.v2", "analytics"},
+		},
+		{
+			name: "key and nested fields",
+			msg: Message{Topic: "orders", Key: "eu-42", Value: map[string]interface{}{
+				"customer": map[string]interface{}{"tier": "silver"},
+				"amount":   100.0,
+			}},
+			expected: []string{"orders.eu"},
+		},
+		{
+			name: "nested field mismatch",
+			msg: Message{Topic: "orders", Key: "eu-42", Value: map[string]interface{}{
+				"customer": map[string]interface{}{"tier": "bronze"},
+				"amount":   100.0,
+			}},
+			expected: []string{"orders"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topics := router.Route(tt.msg)
+			if !reflect.DeepEqual(topics, tt.expected) {
+				t.Errorf("Expected topics %v, got %v", tt.expected, topics)
+			}
+		})
+	}
+}
+
+func TestRouterReload(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	path := filepath.Join(t.TempDir(), "gateway.yaml")
+	writeFile := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+	}
+
+	writeFile("routing:\n  rules:\n    - match: {topic: user-events}\n      topics: [users.v1]\n")
+	router := NewRouter(config.RoutingConfig{}, path, logger)
+	if err := router.Reload(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if topics := router.Route(Message{Topic: "user-events"}); !reflect.DeepEqual(topics, []string{"users.v1"}) {
+		t.Errorf("Expected users.v1, got %v", topics)
+	}
+
+	// Некорректный файл не должен сбрасывать действующие правила
+	writeFile("routing:\n  rules:\n    - match: {topic: user-events}\n")
+	if err := router.Reload(); err == nil {
+		t.Errorf("Expected error for rule without topics")
+	}
+	if len(router.Rules()) != 1 {
+		t.Errorf("Expected previous rules to be kept, got %v", router.Rules())
+	}
+}
+
+func TestRouterResolve(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	router := NewRouter(config.RoutingConfig{
+		Prefix:  "prod.",
+		Aliases: map[string]string{"user-events": "users.events.v3", "audit": "audit.v1"},
+		Rules: []config.RoutingRule{
+			{Match: config.RoutingMatch{Topic: "orders"}, Topics: []string{"audit", "orders.v2"}},
+		},
+	}, "", logger)
+
+	tests := []struct {
+		name     string
+		topic    string
+		expected []string
+	}{
+		{name: "alias", topic: "user-events", expected: []string{"prod.users.events.v3"}},
+		{name: "physical topic", topic: "payments", expected: []string{"payments"}},
+		{name: "rule topics", topic: "orders", expected: []string{"prod.audit.v1", "orders.v2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topics := router.Route(Message{Topic: tt.topic})
+			if !reflect.DeepEqual(topics, tt.expected) {
+				t.Errorf("Expected topics %v, got %v", tt.expected, topics)
+			}
+		})
+	}
+
+	if topic := router.Resolve("user-events"); topic != "prod.users.events.v3" {
+		t.Errorf("Expected prod.users.events.v3, got %s", topic)
+	}
+}
@@ -0,0 +1,349 @@
+package schema
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hamba/avro/v2"
+)
+
+// Магический байт формата Confluent: 0x00, затем 4 байта ID схемы (big endian) и данные Avro
+const confluentMagicByte = 0x00
+
+// AvroCodec проверяет JSON значения по схеме Avro и кодирует их в формат Confluent
+type AvroCodec struct {
+	id     int
+	schema avro.Schema
+}
+
+// NewAvroCodec разбирает схему Avro, зарегистрированную в реестре под идентификатором id
+func NewAvroCodec(id int, schemaJSON string) (*AvroCodec, error) {
+	// Отдельный кэш, чтобы разные версии одной записи не конфликтовали по полному имени
+	parsed, err := avro.ParseWithCache(schemaJSON, "", &avro.SchemaCache{})
+	if err != nil {
+		return nil, fmt.Errorf("parse avro schema %d: %w", id, err)
+	}
+
+	return &AvroCodec{id: id, schema: parsed}, nil
+}
+
+// Encode проверяет значение по схеме и возвращает его в формате Confluent (magic byte + schema ID + Avro)
+func (ac *AvroCodec) Encode(value interface{}) ([]byte, error) {
+	buf := make([]byte, 5, 128)
+	buf[0] = confluentMagicByte
+	binary.BigEndian.PutUint32(buf[1:], uint32(ac.id))
+
+	return appendAvro(buf, ac.schema, value, "")
+}
+
+func appendAvro(buf []byte, schema avro.Schema, value interface{}, path string) ([]byte, error) {
+	switch s := schema.(type) {
+	case *avro.RefSchema:
+		return appendAvro(buf, s.Schema(), value, path)
+	case *avro.NullSchema:
+		if value != nil {
+			return nil, typeError(path, "null", value)
+		}
+		return buf, nil
+	case *avro.PrimitiveSchema:
+		return appendAvroPrimitive(buf, s, value, path)
+	case *avro.RecordSchema:
+		return appendAvroRecord(buf, s, value, path)
+	case *avro.EnumSchema:
+		symbol, ok := value.(string)
+		if !ok {
+			return nil, typeError(path, "enum "+s.Name(), value)
+		}
+		for i, candidate := range s.Symbols() {
+			if candidate == symbol {
+				return binary.AppendVarint(buf, int64(i)), nil
+			}
+		}
+		return nil, &ValidationError{Path: path, Message: fmt.Sprintf("value %q is not one of %v", symbol, s.Symbols())}
+	case *avro.ArraySchema:
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, typeError(path, "array", value)
+		}
+		if len(items) > 0 {
+			buf = binary.AppendVarint(buf, int64(len(items)))
+			for i, item := range items {
+				var err error
+				if buf, err = appendAvro(buf, s.Items(), item, path+"/"+strconv.Itoa(i)); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return binary.AppendVarint(buf, 0), nil
+	case *avro.MapSchema:
+		entries, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, typeError(path, "map", value)
+		}
+		if len(entries) > 0 {
+			keys := make([]string, 0, len(entries))
+			for k := range entries {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			buf = binary.AppendVarint(buf, int64(len(keys)))
+			for _, k := range keys {
+				buf = appendAvroString(buf, k)
+				var err error
+				if buf, err = appendAvro(buf, s.Values(), entries[k], path+"/"+escapePointer(k)); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return binary.AppendVarint(buf, 0), nil
+	case *avro.FixedSchema:
+		data, ok := toBytes(value)
+		if !ok {
+			return nil, typeError(path, "fixed "+s.Name(), value)
+		}
+		if len(data) != s.Size() {
+			return nil, &ValidationError{Path: path, Message: fmt.Sprintf("expected %d bytes, got %d", s.Size(), len(data))}
+		}
+		return append(buf, data...), nil
+	case *avro.UnionSchema:
+		return appendAvroUnion(buf, s, value, path)
+	}
+
+	return nil, &ValidationError{Path: path, Message: fmt.Sprintf("unsupported avro type %s", schema.Type())}
+}
+
+func appendAvroPrimitive(buf []byte, s *avro.PrimitiveSchema, value interface{}, path string) ([]byte, error) {
+	var logical avro.LogicalType
+	if s.Logical() != nil {
+		logical = s.Logical().Type()
+	}
+
+	switch s.Type() {
+	case avro.Boolean:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, typeError(path, "boolean", value)
+		}
+		if b {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case avro.Int:
+		if str, ok := value.(string); ok && logical == avro.Date {
+			date, err := time.Parse(time.DateOnly, str)
+			if err != nil {
+				return nil, &ValidationError{Path: path, Message: "expected date in YYYY-MM-DD format"}
+			}
+			return binary.AppendVarint(buf, date.Unix()/86400), nil
+		}
+		v, ok := toInt64(value)
+		if !ok {
+			return nil, typeError(path, "int", value)
+		}
+		if v < math.MinInt32 || v > math.MaxInt32 {
+			return nil, &ValidationError{Path: path, Message: fmt.Sprintf("value %d overflows int", v)}
+		}
+		return binary.AppendVarint(buf, v), nil
+	case avro.Long:
+		if str, ok := value.(string); ok && isTimestamp(logical) {
+			ts, err := time.Parse(time.RFC3339Nano, str)
+			if err != nil {
+				return nil, &ValidationError{Path: path, Message: "expected RFC3339 timestamp"}
+			}
+			if logical == avro.TimestampMicros || logical == avro.LocalTimestampMicros {
+				return binary.AppendVarint(buf, ts.UnixMicro()), nil
+			}
+			return binary.AppendVarint(buf, ts.UnixMilli()), nil
+		}
+		v, ok := toInt64(value)
+		if !ok {
+			return nil, typeError(path, "long", value)
+		}
+		return binary.AppendVarint(buf, v), nil
+	case avro.Float:
+		v, ok := toFloat64(value)
+		if !ok {
+			return nil, typeError(path, "float", value)
+		}
+		return binary.LittleEndian.AppendUint32(buf, math.Float32bits(float32(v))), nil
+	case avro.Double:
+		v, ok := toFloat64(value)
+		if !ok {
+			return nil, typeError(path, "double", value)
+		}
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(v)), nil
+	case avro.Bytes:
+		if logical == avro.Decimal {
+			return nil, &ValidationError{Path: path, Message: "decimal logical type is not supported"}
+		}
+		data, ok := toBytes(value)
+		if !ok {
+			return nil, typeError(path, "bytes", value)
+		}
+		buf = binary.AppendVarint(buf, int64(len(data)))
+		return append(buf, data...), nil
+	case avro.String:
+		str, ok := value.(string)
+		if !ok {
+			return nil, typeError(path, "string", value)
+		}
+		return appendAvroString(buf, str), nil
+	}
+
+	return nil, &ValidationError{Path: path, Message: fmt.Sprintf("unsupported avro type %s", s.Type())}
+}
+
+func appendAvroRecord(buf []byte, s *avro.RecordSchema, value interface{}, path string) ([]byte, error) {
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, typeError(path, "record "+s.Name(), value)
+	}
+
+	known := make(map[string]bool, len(s.Fields()))
+	for _, field := range s.Fields() {
+		known[field.Name()] = true
+	}
+	for name := range fields {
+		if !known[name] {
+			return nil, &ValidationError{Path: path + "/" + escapePointer(name), Message: "unknown field"}
+		}
+	}
+
+	for _, field := range s.Fields() {
+		fieldPath := path + "/" + escapePointer(field.Name())
+
+		fieldValue, present := fields[field.Name()]
+		if !present {
+			if !field.HasDefault() {
+				return nil, &ValidationError{Path: fieldPath, Message: "required field is missing"}
+			}
+			fieldValue = field.Default()
+		}
+
+		var err error
+		if buf, err = appendAvro(buf, field.Type(), fieldValue, fieldPath); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf, nil
+}
+
+func appendAvroUnion(buf []byte, s *avro.UnionSchema, value interface{}, path string) ([]byte, error) {
+	types := s.Types()
+
+	// Явная форма Avro JSON: {"string": "value"}
+	if wrapped, ok := value.(map[string]interface{}); ok && len(wrapped) == 1 {
+		for name, inner := range wrapped {
+			if branch, idx := types.Get(name); branch != nil {
+				return appendAvro(binary.AppendVarint(buf, int64(idx)), branch, inner, path)
+			}
+		}
+	}
+
+	// Обычный JSON: выбираем первую подходящую ветку
+	names := make([]string, 0, len(types))
+	for idx, branch := range types {
+		names = append(names, unionBranchName(branch))
+		encoded, err := appendAvro(binary.AppendVarint(nil, int64(idx)), branch, value, path)
+		if err == nil {
+			return append(buf, encoded...), nil
+		}
+	}
+
+	return nil, &ValidationError{
+		Path:    path,
+		Message: fmt.Sprintf("%s does not match any of union types [%s]", jsonTypeName(value), strings.Join(names, ", ")),
+	}
+}
+
+func appendAvroString(buf []byte, str string) []byte {
+	buf = binary.AppendVarint(buf, int64(len(str)))
+	return append(buf, str...)
+}
+
+func unionBranchName(schema avro.Schema) string {
+	if named, ok := schema.(avro.NamedSchema); ok {
+		return named.FullName()
+	}
+	if ref, ok := schema.(*avro.RefSchema); ok {
+		return ref.Schema().FullName()
+	}
+	return string(schema.Type())
+}
+
+func isTimestamp(logical avro.LogicalType) bool {
+	switch logical {
+	case avro.TimestampMillis, avro.TimestampMicros, avro.LocalTimestampMillis, avro.LocalTimestampMicros:
+		return true
+	}
+	return false
+}
+
+func toInt64(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt64 || v > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(v), true
+	case float32:
+		return toInt64(float64(v))
+	case json.Number:
+		i, err := v.Int64()
+		return i, err == nil
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	}
+	return 0, false
+}
+
+func toFloat64(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// toBytes возвращает данные bytes и fixed. Значения по умолчанию полей fixed hamba/avro
+// разбирает в массив [N]byte
+func toBytes(value interface{}) ([]byte, bool) {
+	switch v := value.(type) {
+	case string:
+		return []byte(v), true
+	case []byte:
+		return v, true
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+		data := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(data), rv)
+		return data, true
+	}
+	return nil, false
+}
@@ -0,0 +1,218 @@
+package schema
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+)
+
+const userSchema = `{
+	"type": "record",
+	"name": "User",
+	"namespace": "com.example",
+	"fields": [
+		{"name": "id", "type": "long"},
+		{"name": "name", "type": "string"},
+		{"name": "email", "type": ["null", "string"], "default": null},
+		{"name": "age", "type": "int", "default": 0},
+		{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["ACTIVE", "BLOCKED"]}},
+		{"name": "tags", "type": {"type": "array", "items": "string"}, "default": []},
+		{"name": "address", "type": ["null", {"type": "record", "name": "Address", "fields": [
+			{"name": "city", "type": "string"}
+		]}], "default": null}
+	]
+}`
+
+func TestAvroCodecEncode(t *testing.T) {
+	codec, err := NewAvroCodec(42, userSchema)
+	if err != nil {
+		t.Fatalf("Failed to create codec: %v", err)
+	}
+
+	value := map[string]interface{}{
+		"id":      float64(1001),
+		"name":    "Alice",
+		"email":   "alice@example.com",
+		"status":  "ACTIVE",
+		"tags":    []interface{}{"vip"},
+		"address": map[string]interface{}{"city": "Almaty"},
+	}
+
+	encoded, err := codec.Encode(value)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Заголовок формата Confluent: magic byte и ID схемы
+	if !bytes.Equal(encoded[:5], []byte{0, 0, 0, 0, 42}) {
+		t.Errorf("Unexpected wire format header: %v", encoded[:5])
+	}
+
+	// Проверяем, что данные читаются независимой реализацией Avro
+	var decoded map[string]interface{}
+	if err := avro.Unmarshal(codec.schema, encoded[5:], &decoded); err != nil {
+		t.Fatalf("Failed to decode encoded value: %v", err)
+	}
+
+	if decoded["id"] != int64(1001) || decoded["name"] != "Alice" || decoded["age"] != 0 {
+		t.Errorf("Unexpected decoded value: %v", decoded)
+	}
+
+	if decoded["email"] != "alice@example.com" {
+		t.Errorf("Expected email union to be decoded, got %v", decoded["email"])
+	}
+
+	address, _ := decoded["address"].(map[string]interface{})
+	city, _ := address["com.example.Address"].(map[string]interface{})
+	if city["city"] != "Almaty" {
+		t.Errorf("Expected nested record in union, got %v", decoded["address"])
+	}
+}
+
+func TestAvroCodecValidation(t *testing.T) {
+	codec, err := NewAvroCodec(1, userSchema)
+	if err != nil {
+		t.Fatalf("Failed to create codec: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		value        interface{}
+		expectedPath string
+	}{
+		{
+			name:         "not an object",
+			value:        "user",
+			expectedPath: "",
+		},
+		{
+			name:         "missing required field",
+			value:        map[string]interface{}{"id": float64(1), "status": "ACTIVE"},
+			expectedPath: "/name",
+		},
+		{
+			name:         "wrong type",
+			value:        map[string]interface{}{"id": "one", "name": "Bob", "status": "ACTIVE"},
+			expectedPath: "/id",
+		},
+		{
+			name:         "fractional long",
+			value:        map[string]interface{}{"id": 1.5, "name": "Bob", "status": "ACTIVE"},
+			expectedPath: "/id",
+		},
+		{
+			name:         "unknown enum symbol",
+			value:        map[string]interface{}{"id": float64(1), "name": "Bob", "status": "DELETED"},
+			expectedPath: "/status",
+		},
+		{
+			name:         "unknown field",
+			value:        map[string]interface{}{"id": float64(1), "name": "Bob", "status": "ACTIVE", "nickname": "b"},
+			expectedPath: "/nickname",
+		},
+		{
+			name: "wrong array item",
+			value: map[string]interface{}{
+				"id": float64(1), "name": "Bob", "status": "ACTIVE", "tags": []interface{}{"ok", float64(2)},
+			},
+			expectedPath: "/tags/1",
+		},
+		{
+			name:         "int overflow",
+			value:        map[string]interface{}{"id": float64(1), "name": "Bob", "status": "ACTIVE", "age": float64(1 << 40)},
+			expectedPath: "/age",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := codec.Encode(tt.value)
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected ValidationError, got %v", err)
+			}
+
+			if validationErr.Path != tt.expectedPath {
+				t.Errorf("Expected path %q, got %q (%v)", tt.expectedPath, validationErr.Path, validationErr)
+			}
+		})
+	}
+}
+
+func TestAvroCodecLogicalTypes(t *testing.T) {
+	codec, err := NewAvroCodec(7, `{
+		"type": "record",
+		"name": "Event",
+		"fields": [
+			{"name": "occurred_at", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+			{"name": "day", "type": {"type": "int", "logicalType": "date"}}
+		]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to create codec: %v", err)
+	}
+
+	fromString, err := codec.Encode(map[string]interface{}{"occurred_at": "2026-01-02T03:04:05Z", "day": "2026-01-02"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	fromNumber, err := codec.Encode(map[string]interface{}{"occurred_at": float64(1767323045000), "day": float64(20455)})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !bytes.Equal(fromString, fromNumber) {
+		t.Errorf("Expected RFC3339 and epoch values to encode equally: %v != %v", fromString, fromNumber)
+	}
+}
+
+func TestAvroCodecFixedDefault(t *testing.T) {
+	codec, err := NewAvroCodec(7, `{
+		"type": "record",
+		"name": "Device",
+		"fields": [
+			{"name": "id", "type": "string"},
+			{"name": "mac", "type": {"type": "fixed", "name": "MAC", "size": 6}, "default": "\u0000\u0000\u0000\u0000\u0000\u0001"},
+			{"name": "salt", "type": "bytes", "default": "ab"},
+			{"name": "location", "type": {"type": "record", "name": "Location", "fields": [
+				{"name": "zone", "type": {"type": "fixed", "name": "Zone", "size": 2}}
+			]}, "default": {"zone": "eu"}}
+		]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to create codec: %v", err)
+	}
+
+	// Поля с fixed в значении по умолчанию опущены
+	encoded, err := codec.Encode(map[string]interface{}{"id": "d-1"})
+	if err != nil {
+		t.Fatalf("Expected defaults to be encoded, got %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := avro.Unmarshal(codec.schema, encoded[5:], &decoded); err != nil {
+		t.Fatalf("Failed to decode encoded value: %v", err)
+	}
+
+	if mac, _ := decoded["mac"].([6]byte); mac != [6]byte{0, 0, 0, 0, 0, 1} {
+		t.Errorf("Expected default mac, got %v", decoded["mac"])
+	}
+	if !bytes.Equal(decoded["salt"].([]byte), []byte("ab")) {
+		t.Errorf("Expected default salt, got %v", decoded["salt"])
+	}
+	location, _ := decoded["location"].(map[string]interface{})
+	if zone, _ := location["zone"].([2]byte); zone != [2]byte{'e', 'u'} {
+		t.Errorf("Expected default zone, got %v", decoded["location"])
+	}
+
+	// Явное значение fixed неверной длины по-прежнему отклоняется
+	_, err = codec.Encode(map[string]interface{}{"id": "d-2", "mac": "abc"})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Path != "/mac" {
+		t.Errorf("Expected validation error at /mac, got %v", err)
+	}
+}
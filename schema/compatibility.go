@@ -0,0 +1,248 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hamba/avro/v2"
+
+	"kafkaGateway/models"
+)
+
+// CompatibilityError возвращается при регистрации версии, нарушающей режим совместимости топика
+type CompatibilityError struct {
+	Compatibility string   `json:"compatibility"`
+	Problems      []string `json:"problems"`
+}
+
+func (e *CompatibilityError) Error() string {
+	return fmt.Sprintf("schema is not %s compatible: %s", e.Compatibility, strings.Join(e.Problems, "; "))
+}
+
+func isCompatibility(mode string) bool {
+	switch mode {
+	case models.CompatibilityBackward, models.CompatibilityForward, models.CompatibilityFull, models.CompatibilityNone:
+		return true
+	}
+	return false
+}
+
+// checkCompatibility возвращает нарушения совместимости новой версии схемы с предыдущей
+func checkCompatibility(mode string, previous, next *storedSchema) []string {
+	if mode == models.CompatibilityNone {
+		return nil
+	}
+
+	if previous.Type != next.Type {
+		return []string{fmt.Sprintf("schema type changed from %s to %s", previous.Type, next.Type)}
+	}
+
+	var problems []string
+	if mode == models.CompatibilityBackward || mode == models.CompatibilityFull {
+		// Новая схема должна читать данные, записанные по предыдущей
+		for _, problem := range canRead(next, previous) {
+			problems = append(problems, "backward: "+problem)
+		}
+	}
+	if mode == models.CompatibilityForward || mode == models.CompatibilityFull {
+		// Предыдущая схема должна читать данные, записанные по новой
+		for _, problem := range canRead(previous, next) {
+			problems = append(problems, "forward: "+problem)
+		}
+	}
+	return problems
+}
+
+// canRead проверяет, что данные, записанные по схеме writer, читаются по схеме reader
+func canRead(reader, writer *storedSchema) []string {
+	if reader.Type == models.SchemaTypeAvro {
+		if err := avro.NewSchemaCompatibility().Compatible(reader.avro.schema, writer.avro.schema); err != nil {
+			return []string{err.Error()}
+		}
+		return nil
+	}
+
+	var problems []string
+	jsonSchemaAccepts(reader.doc, writer.doc, "", &problems)
+	return problems
+}
+
+// Ключевые слова JSON Schema, которые не анализируются структурно и должны совпадать
+var opaqueKeywords = []string{
+	"$ref", "$defs", "definitions", "allOf", "anyOf", "oneOf", "not", "if", "then", "else",
+	"const", "format", "multipleOf", "uniqueItems", "contains", "prefixItems",
+	"patternProperties", "propertyNames", "dependentRequired", "dependentSchemas",
+	"unevaluatedProperties", "unevaluatedItems",
+}
+
+// Ограничения снизу и сверху: новая схема не должна их ужесточать
+var (
+	lowerBoundKeywords = []string{"minimum", "exclusiveMinimum", "minLength", "minItems", "minProperties"}
+	upperBoundKeywords = []string{"maximum", "exclusiveMaximum", "maxLength", "maxItems", "maxProperties"}
+)
+
+// jsonSchemaAccepts структурно проверяет, что любое значение, допустимое по схеме narrow,
+// допустимо и по схеме wide. Свойства, не описанные в narrow, считаются отсутствующими
+func jsonSchemaAccepts(wide, narrow interface{}, path string, problems *[]string) {
+	report := func(format string, args ...interface{}) {
+		location := path
+		if location == "" {
+			location = "/"
+		}
+		*problems = append(*problems, location+": "+fmt.Sprintf(format, args...))
+	}
+
+	if wide == true || narrow == false {
+		return
+	}
+	if wide == false {
+		report("no values are accepted")
+		return
+	}
+
+	w, _ := wide.(map[string]interface{})
+	n, _ := narrow.(map[string]interface{})
+	if n == nil {
+		n = map[string]interface{}{}
+	}
+	if len(w) == 0 {
+		return
+	}
+
+	for _, keyword := range opaqueKeywords {
+		if wv, ok := w[keyword]; ok && !reflect.DeepEqual(wv, n[keyword]) {
+			report("keyword %s changed", keyword)
+		}
+	}
+
+	if wideTypes := schemaTypes(w); wideTypes != nil {
+		narrowTypes := schemaTypes(n)
+		if narrowTypes == nil {
+			report("type restricted to %s", joinKeys(wideTypes))
+		}
+		for _, t := range sortedKeys(narrowTypes) {
+			if !wideTypes[t] && !(t == "integer" && wideTypes["number"]) {
+				report("type %s is no longer accepted", t)
+			}
+		}
+	}
+
+	if wideEnum, ok := w["enum"].([]interface{}); ok {
+		narrowEnum, ok := n["enum"].([]interface{})
+		if !ok {
+			report("values restricted to enum")
+		}
+		for _, value := range narrowEnum {
+			if !containsValue(wideEnum, value) {
+				report("enum value %v is no longer accepted", value)
+			}
+		}
+	}
+
+	for _, keyword := range lowerBoundKeywords {
+		if wv, ok := w[keyword].(float64); ok {
+			if nv, ok := n[keyword].(float64); !ok || nv < wv {
+				report("%s raised to %v", keyword, wv)
+			}
+		}
+	}
+	for _, keyword := range upperBoundKeywords {
+		if wv, ok := w[keyword].(float64); ok {
+			if nv, ok := n[keyword].(float64); !ok || nv > wv {
+				report("%s lowered to %v", keyword, wv)
+			}
+		}
+	}
+	if wv, ok := w["pattern"]; ok && wv != n["pattern"] {
+		report("pattern changed to %v", wv)
+	}
+
+	narrowRequired := make(map[string]bool)
+	if required, ok := n["required"].([]interface{}); ok {
+		for _, name := range required {
+			narrowRequired[fmt.Sprint(name)] = true
+		}
+	}
+	if required, ok := w["required"].([]interface{}); ok {
+		for _, name := range required {
+			if !narrowRequired[fmt.Sprint(name)] {
+				*problems = append(*problems, path+"/"+escapePointer(fmt.Sprint(name))+": field is now required")
+			}
+		}
+	}
+
+	wideProps, _ := w["properties"].(map[string]interface{})
+	narrowProps, _ := n["properties"].(map[string]interface{})
+	wideAdditional, hasWideAdditional := w["additionalProperties"]
+	narrowAdditional, narrowAdditionalSchema := n["additionalProperties"].(map[string]interface{})
+
+	for _, name := range sortedKeys(wideProps) {
+		propPath := path + "/" + escapePointer(name)
+		if narrowProp, ok := narrowProps[name]; ok {
+			jsonSchemaAccepts(wideProps[name], narrowProp, propPath, problems)
+		} else if narrowAdditionalSchema {
+			jsonSchemaAccepts(wideProps[name], narrowAdditional, propPath, problems)
+		}
+	}
+	for _, name := range sortedKeys(narrowProps) {
+		if _, ok := wideProps[name]; ok || !hasWideAdditional {
+			continue
+		}
+		propPath := path + "/" + escapePointer(name)
+		if wideAdditional == false {
+			*problems = append(*problems, propPath+": field is no longer allowed")
+		} else {
+			jsonSchemaAccepts(wideAdditional, narrowProps[name], propPath, problems)
+		}
+	}
+	if hasWideAdditional && narrowAdditionalSchema {
+		jsonSchemaAccepts(wideAdditional, narrowAdditional, path+"/*", problems)
+	}
+
+	if wideItems, ok := w["items"]; ok {
+		narrowItems, ok := n["items"]
+		if !ok {
+			narrowItems = true
+		}
+		jsonSchemaAccepts(wideItems, narrowItems, path+"/*", problems)
+	}
+}
+
+// schemaTypes возвращает множество типов ключевого слова type или nil, если оно не задано
+func schemaTypes(schema map[string]interface{}) map[string]bool {
+	switch t := schema["type"].(type) {
+	case string:
+		return map[string]bool{t: true}
+	case []interface{}:
+		types := make(map[string]bool, len(t))
+		for _, item := range t {
+			types[fmt.Sprint(item)] = true
+		}
+		return types
+	}
+	return nil
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, candidate := range values {
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinKeys(m map[string]bool) string {
+	return strings.Join(sortedKeys(m), ", ")
+}
@@ -0,0 +1,136 @@
+package schema
+
+import (
+	"testing"
+
+	"kafkaGateway/models"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	tests := []struct {
+		name          string
+		schemaType    string
+		mode          string
+		previous      string
+		next          string
+		expectProblem bool
+	}{
+		{
+			name:       "json add optional field",
+			schemaType: models.SchemaTypeJSON,
+			mode:       models.CompatibilityFull,
+			previous:   `{"type": "object", "properties": {"id": {"type": "string"}}}`,
+			next:       `{"type": "object", "properties": {"id": {"type": "string"}, "note": {"type": "string"}}}`,
+		},
+		{
+			name:          "json add required field breaks backward",
+			schemaType:    models.SchemaTypeJSON,
+			mode:          models.CompatibilityBackward,
+			previous:      `{"type": "object", "properties": {"id": {"type": "string"}}}`,
+			next:          `{"type": "object", "properties": {"id": {"type": "string"}, "note": {"type": "string"}}, "required": ["note"]}`,
+			expectProblem: true,
+		},
+		{
+			name:       "json add required field is forward compatible",
+			schemaType: models.SchemaTypeJSON,
+			mode:       models.CompatibilityForward,
+			previous:   `{"type": "object", "properties": {"id": {"type": "string"}}}`,
+			next:       `{"type": "object", "properties": {"id": {"type": "string"}, "note": {"type": "string"}}, "required": ["note"]}`,
+		},
+		{
+			name:          "json change field type",
+			schemaType:    models.SchemaTypeJSON,
+			mode:          models.CompatibilityBackward,
+			previous:      `{"type": "object", "properties": {"age": {"type": "string"}}}`,
+			next:          `{"type": "object", "properties": {"age": {"type": "integer"}}}`,
+			expectProblem: true,
+		},
+		{
+			name:       "json widen integer to number",
+			schemaType: models.SchemaTypeJSON,
+			mode:       models.CompatibilityBackward,
+			previous:   `{"type": "object", "properties": {"age": {"type": "integer"}}}`,
+			next:       `{"type": "object", "properties": {"age": {"type": "number"}}}`,
+		},
+		{
+			name:          "json remove enum value breaks backward",
+			schemaType:    models.SchemaTypeJSON,
+			mode:          models.CompatibilityBackward,
+			previous:      `{"type": "string", "enum": ["new", "paid"]}`,
+			next:          `{"type": "string", "enum": ["new"]}`,
+			expectProblem: true,
+		},
+		{
+			name:          "json forbid additional properties breaks backward",
+			schemaType:    models.SchemaTypeJSON,
+			mode:          models.CompatibilityBackward,
+			previous:      `{"type": "object", "properties": {"id": {"type": "string"}, "note": {"type": "string"}}}`,
+			next:          `{"type": "object", "properties": {"id": {"type": "string"}}, "additionalProperties": false}`,
+			expectProblem: true,
+		},
+		{
+			name:          "json tighten max length",
+			schemaType:    models.SchemaTypeJSON,
+			mode:          models.CompatibilityBackward,
+			previous:      `{"type": "array", "items": {"type": "string", "maxLength": 64}}`,
+			next:          `{"type": "array", "items": {"type": "string", "maxLength": 32}}`,
+			expectProblem: true,
+		},
+		{
+			name:          "json any change with none",
+			schemaType:    models.SchemaTypeJSON,
+			mode:          models.CompatibilityNone,
+			previous:      `{"type": "string"}`,
+			next:          `{"type": "integer"}`,
+			expectProblem: false,
+		},
+		{
+			name:       "avro add field with default",
+			schemaType: models.SchemaTypeAvro,
+			mode:       models.CompatibilityFull,
+			previous:   `{"type": "record", "name": "Order", "fields": [{"name": "id", "type": "string"}]}`,
+			next:       `{"type": "record", "name": "Order", "fields": [{"name": "id", "type": "string"}, {"name": "note", "type": "string", "default": ""}]}`,
+		},
+		{
+			name:          "avro add field without default breaks backward",
+			schemaType:    models.SchemaTypeAvro,
+			mode:          models.CompatibilityBackward,
+			previous:      `{"type": "record", "name": "Order", "fields": [{"name": "id", "type": "string"}]}`,
+			next:          `{"type": "record", "name": "Order", "fields": [{"name": "id", "type": "string"}, {"name": "note", "type": "string"}]}`,
+			expectProblem: true,
+		},
+		{
+			name:       "avro promote int to long",
+			schemaType: models.SchemaTypeAvro,
+			mode:       models.CompatibilityBackward,
+			previous:   `{"type": "record", "name": "Order", "fields": [{"name": "qty", "type": "int"}]}`,
+			next:       `{"type": "record", "name": "Order", "fields": [{"name": "qty", "type": "long"}]}`,
+		},
+		{
+			name:          "avro promote int to long breaks forward",
+			schemaType:    models.SchemaTypeAvro,
+			mode:          models.CompatibilityForward,
+			previous:      `{"type": "record", "name": "Order", "fields": [{"name": "qty", "type": "int"}]}`,
+			next:          `{"type": "record", "name": "Order", "fields": [{"name": "qty", "type": "long"}]}`,
+			expectProblem: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous, err := compileSchema("orders", 1, tt.schemaType, []byte(tt.previous))
+			if err != nil {
+				t.Fatalf("Failed to compile previous schema: %v", err)
+			}
+			next, err := compileSchema("orders", 2, tt.schemaType, []byte(tt.next))
+			if err != nil {
+				t.Fatalf("Failed to compile next schema: %v", err)
+			}
+
+			problems := checkCompatibility(tt.mode, previous, next)
+			if (len(problems) > 0) != tt.expectProblem {
+				t.Errorf("Expected problems=%v, got %v", tt.expectProblem, problems)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package schema
+
+import (
+	"reflect"
+	"strconv"
+
+	"kafkaGateway/models"
+)
+
+// diffDocuments сравнивает два JSON документа схем и собирает отличия с путями в формате JSON Pointer
+func diffDocuments(from, to interface{}, path string, changes *[]models.SchemaChange) {
+	fromMap, fromIsMap := from.(map[string]interface{})
+	toMap, toIsMap := to.(map[string]interface{})
+	if fromIsMap && toIsMap {
+		keys := make(map[string]bool, len(fromMap)+len(toMap))
+		for k := range fromMap {
+			keys[k] = true
+		}
+		for k := range toMap {
+			keys[k] = true
+		}
+
+		for _, k := range sortedKeys(keys) {
+			fromValue, inFrom := fromMap[k]
+			toValue, inTo := toMap[k]
+			keyPath := path + "/" + escapePointer(k)
+
+			switch {
+			case !inFrom:
+				*changes = append(*changes, models.SchemaChange{Path: keyPath, Change: "added", To: toValue})
+			case !inTo:
+				*changes = append(*changes, models.SchemaChange{Path: keyPath, Change: "removed", From: fromValue})
+			default:
+				diffDocuments(fromValue, toValue, keyPath, changes)
+			}
+		}
+		return
+	}
+
+	fromList, fromIsList := from.([]interface{})
+	toList, toIsList := to.([]interface{})
+	if fromIsList && toIsList && len(fromList) == len(toList) {
+		for i := range fromList {
+			diffDocuments(fromList[i], toList[i], path+"/"+strconv.Itoa(i), changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		*changes = append(*changes, models.SchemaChange{Path: path, Change: "changed", From: from, To: to})
+	}
+}
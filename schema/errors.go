@@ -0,0 +1,52 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ValidationError ошибка проверки значения по схеме с путем в формате JSON Pointer
+type ValidationError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e *ValidationError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "/"
+	}
+	return path + ": " + e.Message
+}
+
+func typeError(path, expected string, value interface{}) *ValidationError {
+	return &ValidationError{
+		Path:    path,
+		Message: fmt.Sprintf("expected %s, got %s", expected, jsonTypeName(value)),
+	}
+}
+
+// jsonTypeName возвращает название JSON типа значения для сообщений об ошибках
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64, float32, int, int32, int64, json.Number:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// escapePointer экранирует сегмент пути JSON Pointer (RFC 6901)
+func escapePointer(segment string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(segment, "~", "~0"), "/", "~1")
+}
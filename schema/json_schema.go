@@ -0,0 +1,80 @@
+package schema
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+var errorPrinter = message.NewPrinter(language.English)
+
+// ValidationErrors список ошибок проверки значения по схеме
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// compileJSONSchema компилирует JSON Schema (по умолчанию draft 2020-12)
+func compileJSONSchema(topic string, version int, raw []byte) (*jsonschema.Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("mem://schemas/%s/%d.json", topic, version)
+
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+	if err := compiler.AddResource(url, doc); err != nil {
+		return nil, err
+	}
+
+	return compiler.Compile(url)
+}
+
+// validateJSONSchema проверяет значение по скомпилированной схеме и возвращает ValidationErrors
+func validateJSONSchema(compiled *jsonschema.Schema, value interface{}) error {
+	err := compiled.Validate(value)
+	if err == nil {
+		return nil
+	}
+
+	var schemaErr *jsonschema.ValidationError
+	if !errors.As(err, &schemaErr) {
+		return err
+	}
+
+	var result ValidationErrors
+	collectValidationErrors(schemaErr, &result)
+	return result
+}
+
+// collectValidationErrors собирает конечные ошибки дерева проверки
+func collectValidationErrors(err *jsonschema.ValidationError, result *ValidationErrors) {
+	if len(err.Causes) == 0 {
+		segments := make([]string, 0, len(err.InstanceLocation))
+		for _, segment := range err.InstanceLocation {
+			segments = append(segments, "/"+escapePointer(segment))
+		}
+
+		*result = append(*result, &ValidationError{
+			Path:    strings.Join(segments, ""),
+			Message: err.ErrorKind.LocalizedString(errorPrinter),
+		})
+		return
+	}
+
+	for _, cause := range err.Causes {
+		collectValidationErrors(cause, result)
+	}
+}
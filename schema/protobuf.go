@@ -0,0 +1,83 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// ProtobufCodec кодирует JSON значения в бинарный Protobuf по описанию сообщения
+type ProtobufCodec struct {
+	descriptor protoreflect.MessageDescriptor
+}
+
+// NewProtobufCodec загружает скомпилированный FileDescriptorSet (protoc --include_imports
+// --descriptor_set_out) и находит в нем тип сообщения по полному имени
+func NewProtobufCodec(descriptorSetPath, messageName string) (*ProtobufCodec, error) {
+	data, err := os.ReadFile(descriptorSetPath)
+	if err != nil {
+		return nil, fmt.Errorf("read descriptor set: %w", err)
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parse descriptor set %s: %w", descriptorSetPath, err)
+	}
+
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("build descriptors from %s: %w", descriptorSetPath, err)
+	}
+
+	found, err := files.FindDescriptorByName(protoreflect.FullName(messageName))
+	if err != nil {
+		return nil, fmt.Errorf("message %s not found in %s: %w", messageName, descriptorSetPath, err)
+	}
+
+	descriptor, ok := found.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s in %s is not a message", messageName, descriptorSetPath)
+	}
+
+	return &ProtobufCodec{descriptor: descriptor}, nil
+}
+
+// Encode проверяет значение по описанию сообщения и возвращает его в бинарном формате Protobuf.
+// Неизвестные поля отклоняются
+func (pc *ProtobufCodec) Encode(value interface{}) ([]byte, error) {
+	if _, ok := value.(map[string]interface{}); !ok {
+		return nil, typeError("", "message "+string(pc.descriptor.FullName()), value)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	message := dynamicpb.NewMessage(pc.descriptor)
+	if err := protojson.Unmarshal(data, message); err != nil {
+		return nil, &ValidationError{Message: protobufErrorMessage(err)}
+	}
+
+	return proto.Marshal(message)
+}
+
+// protobufErrorMessage убирает из ошибки protojson служебный префикс и позицию в сгенерированном JSON
+func protobufErrorMessage(err error) string {
+	message := strings.TrimPrefix(err.Error(), "proto:")
+	message = strings.TrimSpace(strings.ReplaceAll(message, "\u00a0", " "))
+	if strings.HasPrefix(message, "(line ") {
+		if idx := strings.Index(message, "):"); idx >= 0 {
+			message = strings.TrimSpace(message[idx+2:])
+		}
+	}
+	return message
+}
@@ -0,0 +1,139 @@
+package schema
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// writeDescriptorSet сохраняет FileDescriptorSet с сообщением shop.v1.Order, как это сделал бы protoc
+func writeDescriptorSet(t *testing.T) string {
+	t.Helper()
+
+	field := func(name string, number int32, fieldType descriptorpb.FieldDescriptorProto_Type, label descriptorpb.FieldDescriptorProto_Label) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Type:     fieldType.Enum(),
+			Label:    label.Enum(),
+		}
+	}
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("shop/v1/order.proto"),
+		Package: proto.String("shop.v1"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Order"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL),
+				field("amount", 2, descriptorpb.FieldDescriptorProto_TYPE_INT64, descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL),
+				field("tags", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING, descriptorpb.FieldDescriptorProto_LABEL_REPEATED),
+			},
+		}},
+	}
+
+	data, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{file}})
+	if err != nil {
+		t.Fatalf("Failed to marshal descriptor set: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "order.pb")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("Failed to write descriptor set: %v", err)
+	}
+	return path
+}
+
+func TestProtobufCodecEncode(t *testing.T) {
+	codec, err := NewProtobufCodec(writeDescriptorSet(t), "shop.v1.Order")
+	if err != nil {
+		t.Fatalf("Failed to create codec: %v", err)
+	}
+
+	encoded, err := codec.Encode(map[string]interface{}{
+		"id":     "o-1",
+		"amount": float64(1500),
+		"tags":   []interface{}{"new"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	decoded := dynamicpb.NewMessage(codec.descriptor)
+	if err := proto.Unmarshal(encoded, decoded); err != nil {
+		t.Fatalf("Failed to decode encoded value: %v", err)
+	}
+
+	fields := codec.descriptor.Fields()
+	if id := decoded.Get(fields.ByName("id")).String(); id != "o-1" {
+		t.Errorf("Expected id o-1, got %s", id)
+	}
+	if amount := decoded.Get(fields.ByName("amount")).Int(); amount != 1500 {
+		t.Errorf("Expected amount 1500, got %d", amount)
+	}
+	if tags := decoded.Get(fields.ByName("tags")).List(); tags.Len() != 1 || tags.Get(0).String() != "new" {
+		t.Errorf("Expected tags [new], got %v", tags)
+	}
+}
+
+func TestProtobufCodecValidation(t *testing.T) {
+	codec, err := NewProtobufCodec(writeDescriptorSet(t), "shop.v1.Order")
+	if err != nil {
+		t.Fatalf("Failed to create codec: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		value           interface{}
+		expectedMessage string
+	}{
+		{
+			name:            "unknown field",
+			value:           map[string]interface{}{"id": "o-1", "discount": float64(5)},
+			expectedMessage: `unknown field "discount"`,
+		},
+		{
+			name:            "wrong type",
+			value:           map[string]interface{}{"id": float64(1)},
+			expectedMessage: "invalid value for string field id",
+		},
+		{
+			name:            "not an object",
+			value:           []interface{}{"o-1"},
+			expectedMessage: "expected message shop.v1.Order, got array",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := codec.Encode(tt.value)
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected ValidationError, got %v", err)
+			}
+
+			if !strings.Contains(validationErr.Message, tt.expectedMessage) {
+				t.Errorf("Expected message to contain %q, got %q", tt.expectedMessage, validationErr.Message)
+			}
+		})
+	}
+}
+
+func TestNewProtobufCodecUnknownMessage(t *testing.T) {
+	if _, err := NewProtobufCodec(writeDescriptorSet(t), "shop.v1.Missing"); err == nil {
+		t.Errorf("Expected error for unknown message type")
+	}
+
+	if _, err := NewProtobufCodec(filepath.Join(t.TempDir(), "missing.pb"), "shop.v1.Order"); err == nil {
+		t.Errorf("Expected error for missing descriptor set")
+	}
+}
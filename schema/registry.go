@@ -0,0 +1,120 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSubjectNotFound возвращается, если субъект или его версия отсутствуют в реестре
+var ErrSubjectNotFound = errors.New("schema subject not found")
+
+// ErrRegistry ошибка обращения к реестру схем: сбой соединения, неожиданный статус или ответ
+var ErrRegistry = errors.New("schema registry error")
+
+// Время жизни кэша последней версии субъекта
+const latestSchemaTTL = 5 * time.Minute
+
+// RegistrySchema схема, зарегистрированная в реестре схем
+type RegistrySchema struct {
+	Subject    string `json:"subject"`
+	Version    int    `json:"version"`
+	ID         int    `json:"id"`
+	SchemaType string `json:"schemaType,omitempty"`
+	Schema     string `json:"schema"`
+}
+
+type cachedSchema struct {
+	schema    *RegistrySchema
+	fetchedAt time.Time
+}
+
+// RegistryClient клиент реестра схем, совместимого с Confluent Schema Registry
+type RegistryClient struct {
+	baseURL    string
+	username   string
+	password   string
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	versions map[string]cachedSchema
+}
+
+func NewRegistryClient(baseURL, username, password string) *RegistryClient {
+	return &RegistryClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		username:   username,
+		password:   password,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		versions:   make(map[string]cachedSchema),
+	}
+}
+
+// GetSchema возвращает схему субъекта указанной версии ("latest" или номер версии).
+// Конкретные версии неизменяемы и кэшируются навсегда, последняя версия - на latestSchemaTTL
+func (rc *RegistryClient) GetSchema(ctx context.Context, subject, version string) (*RegistrySchema, error) {
+	if version == "" {
+		version = "latest"
+	}
+	cacheKey := subject + "/" + version
+
+	rc.mu.Lock()
+	cached, ok := rc.versions[cacheKey]
+	rc.mu.Unlock()
+	if ok && (version != "latest" || time.Since(cached.fetchedAt) < latestSchemaTTL) {
+		return cached.schema, nil
+	}
+
+	path := fmt.Sprintf("/subjects/%s/versions/%s", url.PathEscape(subject), url.PathEscape(version))
+	var schema RegistrySchema
+	if err := rc.get(ctx, path, &schema); err != nil {
+		if ok {
+			// Реестр недоступен - продолжаем работать с последней известной схемой
+			return cached.schema, nil
+		}
+		return nil, err
+	}
+
+	rc.mu.Lock()
+	rc.versions[cacheKey] = cachedSchema{schema: &schema, fetchedAt: time.Now()}
+	rc.mu.Unlock()
+
+	return &schema, nil
+}
+
+func (rc *RegistryClient) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rc.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json")
+	if rc.username != "" {
+		req.SetBasicAuth(rc.username, rc.password)
+	}
+
+	resp, err := rc.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: request failed: %w", ErrRegistry, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrSubjectNotFound, path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%w: returned %d: %s", ErrRegistry, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: decode response: %w", ErrRegistry, err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"kafkaGateway/config"
+	"kafkaGateway/requestid"
+)
+
+// RegistryInterface определяет обращения к реестру схем
+type RegistryInterface interface {
+	GetSchema(ctx context.Context, subject, version string) (*RegistrySchema, error)
+}
+
+// Serializer кодирует значения сообщений по схемам, привязанным к топикам
+type Serializer struct {
+	topics   map[string]config.TopicConfig
+	registry RegistryInterface
+	logger   *zap.Logger
+
+	mu     sync.Mutex
+	codecs map[int]*AvroCodec
+
+	protobuf map[string]*ProtobufCodec
+}
+
+func NewSerializer(topics map[string]config.TopicConfig, registry RegistryInterface, logger *zap.Logger) *Serializer {
+	return &Serializer{
+		topics:   topics,
+		registry: registry,
+		logger:   logger,
+		codecs:   make(map[int]*AvroCodec),
+		protobuf: make(map[string]*ProtobufCodec),
+	}
+}
+
+// LoadDescriptors загружает описания Protobuf сообщений топиков. Вызывается при старте,
+// чтобы ошибки конфигурации обнаруживались до приема сообщений
+func (s *Serializer) LoadDescriptors() error {
+	for topic, topicConfig := range s.topics {
+		if topicConfig.Protobuf == nil {
+			continue
+		}
+
+		codec, err := NewProtobufCodec(topicConfig.Protobuf.DescriptorSet, topicConfig.Protobuf.Message)
+		if err != nil {
+			return fmt.Errorf("topic %s: %w", topic, err)
+		}
+		s.protobuf[topic] = codec
+	}
+	return nil
+}
+
+// Serialize кодирует значение для топика. Если к топику не привязана схема,
+// возвращает handled=false, и значение отправляется как есть
+func (s *Serializer) Serialize(ctx context.Context, topic string, value interface{}) ([]byte, bool, error) {
+	if codec, ok := s.protobuf[topic]; ok {
+		encoded, err := codec.Encode(value)
+		return encoded, true, err
+	}
+
+	topicConfig, ok := s.topics[topic]
+	if !ok || topicConfig.Avro == nil {
+		return nil, false, nil
+	}
+
+	codec, err := s.avroCodec(ctx, topicConfig.Avro)
+	if err != nil {
+		return nil, true, err
+	}
+
+	encoded, err := codec.Encode(value)
+	if err != nil {
+		return nil, true, err
+	}
+
+	return encoded, true, nil
+}
+
+func (s *Serializer) avroCodec(ctx context.Context, binding *config.AvroConfig) (*AvroCodec, error) {
+	registered, err := s.registry.GetSchema(ctx, binding.Subject, binding.Version)
+	if err != nil {
+		requestid.Logger(ctx, s.logger).Error("Failed to fetch schema from registry",
+			zap.String("subject", binding.Subject),
+			zap.String("version", binding.Version),
+			zap.Error(err))
+		return nil, err
+	}
+
+	if registered.SchemaType != "" && registered.SchemaType != "AVRO" {
+		return nil, fmt.Errorf("subject %s has schema type %s, expected AVRO", binding.Subject, registered.SchemaType)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if codec, ok := s.codecs[registered.ID]; ok {
+		return codec, nil
+	}
+
+	codec, err := NewAvroCodec(registered.ID, registered.Schema)
+	if err != nil {
+		return nil, err
+	}
+	s.codecs[registered.ID] = codec
+
+	return codec, nil
+}
@@ -0,0 +1,93 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"kafkaGateway/config"
+)
+
+// newTestRegistry запускает in-process заглушку реестра схем с одним субъектом orders-value
+func newTestRegistry(t *testing.T, requests *int32) *httptest.Server {
+	t.Helper()
+
+	orderSchema := `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"},{"name":"amount","type":"double"}]}`
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/subjects/orders-value/versions/latest", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+		json.NewEncoder(w).Encode(RegistrySchema{Subject: "orders-value", Version: 3, ID: 17, Schema: orderSchema})
+	})
+	mux.HandleFunc("/subjects/broken-value/versions/latest", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error_code":50001,"message":"Error in the backend datastore"}`))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error_code":40401,"message":"Subject not found"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSerializerAvro(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	var requests int32
+	registry := newTestRegistry(t, &requests)
+
+	topics := map[string]config.TopicConfig{
+		"orders":  {Avro: &config.AvroConfig{Subject: "orders-value", Version: "latest"}},
+		"missing": {Avro: &config.AvroConfig{Subject: "missing-value", Version: "latest"}},
+		"broken":  {Avro: &config.AvroConfig{Subject: "broken-value", Version: "latest"}},
+	}
+	serializer := NewSerializer(topics, NewRegistryClient(registry.URL, "", ""), logger)
+
+	// Топик без схемы не обрабатывается
+	if _, handled, err := serializer.Serialize(context.Background(), "plain", "value"); handled || err != nil {
+		t.Errorf("Expected plain topic to be skipped, got handled=%v err=%v", handled, err)
+	}
+
+	value := map[string]interface{}{"id": "o-1", "amount": 99.5}
+	for i := 0; i < 3; i++ {
+		encoded, handled, err := serializer.Serialize(context.Background(), "orders", value)
+		if err != nil || !handled {
+			t.Fatalf("Expected value to be serialized, got handled=%v err=%v", handled, err)
+		}
+		if encoded[0] != 0 || encoded[4] != 17 {
+			t.Errorf("Expected Confluent header with schema ID 17, got %v", encoded[:5])
+		}
+	}
+
+	// Схема кэшируется локально
+	if requests != 1 {
+		t.Errorf("Expected 1 registry request, got %d", requests)
+	}
+
+	_, _, err := serializer.Serialize(context.Background(), "orders", map[string]interface{}{"id": "o-2"})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Path != "/amount" {
+		t.Errorf("Expected validation error at /amount, got %v", err)
+	}
+
+	_, _, err = serializer.Serialize(context.Background(), "missing", value)
+	if !errors.Is(err, ErrSubjectNotFound) {
+		t.Errorf("Expected ErrSubjectNotFound, got %v", err)
+	}
+
+	_, _, err = serializer.Serialize(context.Background(), "broken", value)
+	if !errors.Is(err, ErrRegistry) || errors.Is(err, ErrSubjectNotFound) {
+		t.Errorf("Expected ErrRegistry, got %v", err)
+	}
+}
@@ -0,0 +1,550 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"go.uber.org/zap"
+
+	"kafkaGateway/models"
+)
+
+var (
+	// ErrSchemaNotFound возвращается, если для топика не зарегистрировано ни одной схемы
+	ErrSchemaNotFound = errors.New("schema not found")
+	// ErrSchemaVersionNotFound возвращается при обращении к несуществующей версии схемы
+	ErrSchemaVersionNotFound = errors.New("schema version not found")
+	// ErrInvalidSchema возвращается при регистрации некорректной схемы
+	ErrInvalidSchema = errors.New("invalid schema")
+)
+
+// Файлы каталога хранилища: <topic>/<version>.json (JSON Schema), <topic>/<version>.avsc (Avro)
+// и <topic>/config.json с режимом совместимости и закрепленной версией.
+// Одиночный файл <topic>.json или <topic>.avsc загружается как версия 1
+var schemaFileExt = map[string]string{
+	models.SchemaTypeJSON: ".json",
+	models.SchemaTypeAvro: ".avsc",
+}
+
+const topicConfigFile = "config.json"
+
+type storedSchema struct {
+	models.SchemaVersion
+
+	// doc разобранный документ схемы для проверки совместимости и сравнения версий
+	doc        interface{}
+	jsonSchema *jsonschema.Schema
+	avro       *AvroCodec
+}
+
+type topicConfig struct {
+	Compatibility string `json:"compatibility,omitempty"`
+	PinnedVersion int    `json:"pinned_version,omitempty"`
+}
+
+type topicSchemas struct {
+	topicConfig
+	versions []*storedSchema
+}
+
+func (ts *topicSchemas) latest() *storedSchema {
+	return ts.versions[len(ts.versions)-1]
+}
+
+// active возвращает версию, по которой проверяются сообщения: закрепленную или последнюю
+func (ts *topicSchemas) active() *storedSchema {
+	if ts.PinnedVersion > 0 {
+		if version := ts.version(ts.PinnedVersion); version != nil {
+			return version
+		}
+	}
+	return ts.latest()
+}
+
+func (ts *topicSchemas) version(version int) *storedSchema {
+	for _, stored := range ts.versions {
+		if stored.Version == version {
+			return stored
+		}
+	}
+	return nil
+}
+
+// Store локальное версионируемое хранилище схем топиков (JSON Schema и Avro)
+// с проверкой совместимости новых версий
+type Store struct {
+	dir                  string
+	defaultCompatibility string
+	logger               *zap.Logger
+
+	mu     sync.RWMutex
+	topics map[string]*topicSchemas
+}
+
+// NewStore создает хранилище схем. Если dir не пуст, схемы, зарегистрированные
+// через API, сохраняются в этот каталог и переживают перезапуск
+func NewStore(dir, defaultCompatibility string, logger *zap.Logger) *Store {
+	return &Store{
+		dir:                  dir,
+		defaultCompatibility: strings.ToUpper(defaultCompatibility),
+		logger:               logger,
+		topics:               make(map[string]*topicSchemas),
+	}
+}
+
+// Load проверяет настройки хранилища и загружает схемы из каталога
+func (s *Store) Load() error {
+	if !isCompatibility(s.defaultCompatibility) {
+		return fmt.Errorf("invalid schema compatibility %q", s.defaultCompatibility)
+	}
+	if s.dir == "" {
+		return nil
+	}
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("read schema dir: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			if err := s.loadTopicDir(entry.Name()); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Одиночные файлы схем, для которых еще нет каталога с версиями
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		topic, schemaType, ok := parseSchemaFileName(entry.Name())
+		if !ok || s.topics[topic] != nil {
+			continue
+		}
+
+		stored, err := s.loadSchemaFile(filepath.Join(s.dir, entry.Name()), topic, 1, schemaType)
+		if err != nil {
+			return err
+		}
+		s.topics[topic] = &topicSchemas{versions: []*storedSchema{stored}}
+	}
+
+	s.logger.Info("Schemas loaded", zap.String("dir", s.dir), zap.Int("topics", len(s.topics)))
+	return nil
+}
+
+func (s *Store) loadTopicDir(topic string) error {
+	topicDir := filepath.Join(s.dir, topic)
+
+	entries, err := os.ReadDir(topicDir)
+	if err != nil {
+		return fmt.Errorf("read schema dir: %w", err)
+	}
+
+	ts := &topicSchemas{}
+	if data, err := os.ReadFile(filepath.Join(topicDir, topicConfigFile)); err == nil {
+		if err := json.Unmarshal(data, &ts.topicConfig); err != nil {
+			return fmt.Errorf("parse %s: %w", filepath.Join(topicDir, topicConfigFile), err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("read schema config: %w", err)
+	}
+
+	for _, entry := range entries {
+		name, schemaType, ok := parseSchemaFileName(entry.Name())
+		if !ok {
+			continue
+		}
+		version, err := strconv.Atoi(name)
+		if err != nil || version < 1 {
+			continue
+		}
+
+		stored, err := s.loadSchemaFile(filepath.Join(topicDir, entry.Name()), topic, version, schemaType)
+		if err != nil {
+			return err
+		}
+		ts.versions = append(ts.versions, stored)
+	}
+
+	if len(ts.versions) == 0 {
+		return nil
+	}
+	sort.Slice(ts.versions, func(i, j int) bool { return ts.versions[i].Version < ts.versions[j].Version })
+
+	s.topics[topic] = ts
+	return nil
+}
+
+func (s *Store) loadSchemaFile(path, topic string, version int, schemaType string) (*storedSchema, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read schema: %w", err)
+	}
+
+	stored, err := compileSchema(topic, version, schemaType, raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if info, err := os.Stat(path); err == nil {
+		stored.CreatedAt = info.ModTime()
+	}
+	return stored, nil
+}
+
+// Register регистрирует новую версию схемы топика. Версия, нарушающая режим совместимости,
+// отклоняется с CompatibilityError. Повторная регистрация последней версии возвращает ее же
+func (s *Store) Register(topic, schemaType string, raw []byte) (*models.SchemaVersion, error) {
+	if schemaType == "" {
+		schemaType = models.SchemaTypeJSON
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ts := s.topics[topic]
+	version := 1
+	if ts != nil {
+		version = ts.latest().Version + 1
+	}
+
+	stored, err := compileSchema(topic, version, schemaType, raw)
+	if err != nil {
+		return nil, err
+	}
+	stored.CreatedAt = time.Now()
+
+	if ts != nil {
+		latest := ts.latest()
+		if latest.Type == stored.Type && bytes.Equal(latest.Schema, stored.Schema) {
+			return &latest.SchemaVersion, nil
+		}
+
+		mode := s.compatibility(ts)
+		if problems := checkCompatibility(mode, latest, stored); len(problems) > 0 {
+			return nil, &CompatibilityError{Compatibility: mode, Problems: problems}
+		}
+	} else {
+		ts = &topicSchemas{}
+	}
+
+	ts.versions = append(ts.versions, stored)
+	if err := s.persist(topic, ts); err != nil {
+		ts.versions = ts.versions[:len(ts.versions)-1]
+		return nil, err
+	}
+	s.topics[topic] = ts
+
+	s.logger.Info("Schema version registered",
+		zap.String("topic", topic),
+		zap.String("type", stored.Type),
+		zap.Int("version", stored.Version))
+
+	return &stored.SchemaVersion, nil
+}
+
+// Configure меняет режим совместимости и закрепленную версию топика.
+// Пустой режим и nil версия оставляют настройку без изменений, версия 0 снимает закрепление
+func (s *Store) Configure(topic, compatibility string, pinnedVersion *int) (*models.TopicSchemaInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ts, err := s.topic(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := ts.topicConfig
+	if compatibility != "" {
+		if !isCompatibility(compatibility) {
+			return nil, fmt.Errorf("%w: unknown compatibility %q", ErrInvalidSchema, compatibility)
+		}
+		updated.Compatibility = compatibility
+	}
+	if pinnedVersion != nil {
+		if *pinnedVersion > 0 && ts.version(*pinnedVersion) == nil {
+			return nil, fmt.Errorf("%w: %s version %d", ErrSchemaVersionNotFound, topic, *pinnedVersion)
+		}
+		updated.PinnedVersion = *pinnedVersion
+	}
+
+	previous := ts.topicConfig
+	ts.topicConfig = updated
+	if err := s.persist(topic, ts); err != nil {
+		ts.topicConfig = previous
+		return nil, err
+	}
+
+	info := s.info(topic, ts)
+	return &info, nil
+}
+
+// Delete удаляет все версии схемы топика
+func (s *Store) Delete(topic string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.topic(topic); err != nil {
+		return err
+	}
+
+	if s.dir != "" {
+		if err := os.RemoveAll(filepath.Join(s.dir, topic)); err != nil {
+			return fmt.Errorf("remove schemas: %w", err)
+		}
+		if err := s.removeSingleFiles(topic); err != nil {
+			return err
+		}
+	}
+
+	delete(s.topics, topic)
+	return nil
+}
+
+// Get возвращает исходный текст активной версии схемы топика
+func (s *Store) Get(topic string) (json.RawMessage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ts, err := s.topic(topic)
+	if err != nil {
+		return nil, err
+	}
+	return ts.active().Schema, nil
+}
+
+// Topics возвращает сводку по схемам всех топиков, отсортированную по имени
+func (s *Store) Topics() []models.TopicSchemaInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]models.TopicSchemaInfo, 0, len(s.topics))
+	for _, topic := range sortedKeys(s.topics) {
+		result = append(result, s.info(topic, s.topics[topic]))
+	}
+	return result
+}
+
+// Versions возвращает все версии схемы топика
+func (s *Store) Versions(topic string) ([]models.SchemaVersion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ts, err := s.topic(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.SchemaVersion, 0, len(ts.versions))
+	for _, stored := range ts.versions {
+		result = append(result, stored.SchemaVersion)
+	}
+	return result, nil
+}
+
+// Version возвращает версию схемы топика, 0 означает активную версию
+func (s *Store) Version(topic string, version int) (*models.SchemaVersion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stored, err := s.storedVersion(topic, version)
+	if err != nil {
+		return nil, err
+	}
+	return &stored.SchemaVersion, nil
+}
+
+// Diff возвращает отличия между двумя версиями схемы топика
+func (s *Store) Diff(topic string, from, to int) (*models.SchemaDiff, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fromVersion, err := s.storedVersion(topic, from)
+	if err != nil {
+		return nil, err
+	}
+	toVersion, err := s.storedVersion(topic, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &models.SchemaDiff{
+		Topic:   topic,
+		From:    fromVersion.Version,
+		To:      toVersion.Version,
+		Changes: []models.SchemaChange{},
+	}
+	diffDocuments(fromVersion.doc, toVersion.doc, "", &diff.Changes)
+	return diff, nil
+}
+
+// Validate проверяет значение по активной версии схемы топика. Топики без схемы не проверяются
+func (s *Store) Validate(topic string, value interface{}) error {
+	s.mu.RLock()
+	ts, ok := s.topics[topic]
+	var active *storedSchema
+	if ok {
+		active = ts.active()
+	}
+	s.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	if active.Type == models.SchemaTypeAvro {
+		_, err := active.avro.Encode(value)
+		return err
+	}
+	return validateJSONSchema(active.jsonSchema, value)
+}
+
+func (s *Store) topic(topic string) (*topicSchemas, error) {
+	ts, ok := s.topics[topic]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSchemaNotFound, topic)
+	}
+	return ts, nil
+}
+
+func (s *Store) storedVersion(topic string, version int) (*storedSchema, error) {
+	ts, err := s.topic(topic)
+	if err != nil {
+		return nil, err
+	}
+	if version == 0 {
+		return ts.active(), nil
+	}
+
+	stored := ts.version(version)
+	if stored == nil {
+		return nil, fmt.Errorf("%w: %s version %d", ErrSchemaVersionNotFound, topic, version)
+	}
+	return stored, nil
+}
+
+func (s *Store) compatibility(ts *topicSchemas) string {
+	if ts.Compatibility != "" {
+		return ts.Compatibility
+	}
+	return s.defaultCompatibility
+}
+
+func (s *Store) info(topic string, ts *topicSchemas) models.TopicSchemaInfo {
+	versions := make([]int, 0, len(ts.versions))
+	for _, stored := range ts.versions {
+		versions = append(versions, stored.Version)
+	}
+
+	active := ts.active()
+	return models.TopicSchemaInfo{
+		Topic:         topic,
+		Type:          active.Type,
+		Compatibility: s.compatibility(ts),
+		PinnedVersion: ts.PinnedVersion,
+		ActiveVersion: active.Version,
+		Versions:      versions,
+	}
+}
+
+// persist сохраняет версии и настройки топика в каталог хранилища
+func (s *Store) persist(topic string, ts *topicSchemas) error {
+	if s.dir == "" {
+		return nil
+	}
+
+	topicDir := filepath.Join(s.dir, topic)
+	if err := os.MkdirAll(topicDir, 0o755); err != nil {
+		return fmt.Errorf("save schema: %w", err)
+	}
+
+	for _, stored := range ts.versions {
+		path := filepath.Join(topicDir, strconv.Itoa(stored.Version)+schemaFileExt[stored.Type])
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if err := os.WriteFile(path, stored.Schema, 0o644); err != nil {
+			return fmt.Errorf("save schema: %w", err)
+		}
+	}
+
+	config, err := json.MarshalIndent(ts.topicConfig, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(topicDir, topicConfigFile), config, 0o644); err != nil {
+		return fmt.Errorf("save schema config: %w", err)
+	}
+
+	// Одиночный файл заменяется каталогом с версиями
+	return s.removeSingleFiles(topic)
+}
+
+func (s *Store) removeSingleFiles(topic string) error {
+	for _, ext := range schemaFileExt {
+		err := os.Remove(filepath.Join(s.dir, topic+ext))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove schema: %w", err)
+		}
+	}
+	return nil
+}
+
+// parseSchemaFileName разбирает имя файла схемы на имя без расширения и тип схемы
+func parseSchemaFileName(fileName string) (string, string, bool) {
+	if fileName == topicConfigFile {
+		return "", "", false
+	}
+	for schemaType, ext := range schemaFileExt {
+		if name, ok := strings.CutSuffix(fileName, ext); ok && name != "" {
+			return name, schemaType, true
+		}
+	}
+	return "", "", false
+}
+
+// compileSchema разбирает и компилирует схему указанного типа
+func compileSchema(topic string, version int, schemaType string, raw []byte) (*storedSchema, error) {
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+
+	stored := &storedSchema{
+		SchemaVersion: models.SchemaVersion{
+			Version: version,
+			Type:    schemaType,
+			Schema:  json.RawMessage(compacted.Bytes()),
+		},
+	}
+	if err := json.Unmarshal(raw, &stored.doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+
+	var err error
+	switch schemaType {
+	case models.SchemaTypeJSON:
+		stored.jsonSchema, err = compileJSONSchema(topic, version, raw)
+	case models.SchemaTypeAvro:
+		stored.avro, err = NewAvroCodec(version, string(raw))
+	default:
+		err = fmt.Errorf("unknown schema type %q", schemaType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+
+	return stored, nil
+}
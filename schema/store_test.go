@@ -0,0 +1,233 @@
+package schema
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"kafkaGateway/models"
+)
+
+const orderJSONSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"required": ["id", "items"],
+	"properties": {
+		"id": {"type": "string"},
+		"items": {
+			"type": "array",
+			"prefixItems": [{"type": "object"}],
+			"items": {
+				"type": "object",
+				"required": ["qty"],
+				"properties": {"qty": {"type": "integer", "minimum": 1}}
+			}
+		}
+	},
+	"additionalProperties": false
+}`
+
+func TestStoreValidateJSONSchema(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	store := NewStore("", models.CompatibilityBackward, logger)
+	if _, err := store.Register("orders", models.SchemaTypeJSON, []byte(orderJSONSchema)); err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+
+	// Топики без схемы не проверяются
+	if err := store.Validate("logs", "anything"); err != nil {
+		t.Errorf("Expected no error for topic without schema, got %v", err)
+	}
+
+	valid := map[string]interface{}{
+		"id":    "o-1",
+		"items": []interface{}{map[string]interface{}{}, map[string]interface{}{"qty": float64(2)}},
+	}
+	if err := store.Validate("orders", valid); err != nil {
+		t.Errorf("Expected valid value, got %v", err)
+	}
+
+	invalid := map[string]interface{}{
+		"id":    float64(1),
+		"items": []interface{}{map[string]interface{}{}, map[string]interface{}{"qty": float64(0)}},
+		"extra": true,
+	}
+	err := store.Validate("orders", invalid)
+
+	var validationErrs ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		t.Fatalf("Expected ValidationErrors, got %v", err)
+	}
+
+	paths := make(map[string]bool)
+	for _, validationErr := range validationErrs {
+		paths[validationErr.Path] = true
+	}
+	for _, expected := range []string{"/id", "/items/1/qty", ""} {
+		if !paths[expected] {
+			t.Errorf("Expected error at %q, got %v", expected, validationErrs)
+		}
+	}
+}
+
+func TestStoreInvalidSchema(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	store := NewStore("", models.CompatibilityBackward, logger)
+
+	for _, raw := range []string{`{"type": `, `{"type": "unknown"}`, `{"minimum": "one"}`} {
+		if _, err := store.Register("orders", models.SchemaTypeJSON, []byte(raw)); !errors.Is(err, ErrInvalidSchema) {
+			t.Errorf("Expected ErrInvalidSchema for schema %s, got %v", raw, err)
+		}
+	}
+
+	if _, err := store.Register("orders", models.SchemaTypeAvro, []byte(`{"type": "record"}`)); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("Expected ErrInvalidSchema for avro schema, got %v", err)
+	}
+
+	if len(store.Topics()) != 0 {
+		t.Errorf("Expected invalid schemas not to be registered, got %v", store.Topics())
+	}
+}
+
+func TestStoreDir(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	// Одиночный файл схемы загружается как версия 1
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "orders.json"), []byte(orderJSONSchema), 0o644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	store := NewStore(dir, models.CompatibilityBackward, logger)
+	if err := store.Load(); err != nil {
+		t.Fatalf("Failed to load schemas: %v", err)
+	}
+
+	if err := store.Validate("orders", map[string]interface{}{"id": "o-1"}); err == nil {
+		t.Errorf("Expected schema from directory to be applied")
+	}
+
+	// Новые версии и настройки сохраняются в каталог топика
+	relaxed := `{"type": "object", "required": ["id"], "properties": {"id": {"type": "string"}}}`
+	if _, err := store.Register("orders", models.SchemaTypeJSON, []byte(relaxed)); err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+	pinned := 1
+	if _, err := store.Configure("orders", models.CompatibilityFull, &pinned); err != nil {
+		t.Fatalf("Failed to configure topic: %v", err)
+	}
+	if _, err := store.Register("users", models.SchemaTypeAvro, []byte(`"string"`)); err != nil {
+		t.Fatalf("Failed to register schema: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "orders.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected single schema file to be replaced by versions dir, got %v", err)
+	}
+
+	reloaded := NewStore(dir, models.CompatibilityBackward, logger)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Failed to reload schemas: %v", err)
+	}
+
+	topics := reloaded.Topics()
+	if len(topics) != 2 || topics[0].Topic != "orders" || topics[1].Topic != "users" {
+		t.Fatalf("Expected orders and users schemas, got %+v", topics)
+	}
+	if info := topics[0]; len(info.Versions) != 2 || info.ActiveVersion != 1 || info.Compatibility != models.CompatibilityFull {
+		t.Errorf("Unexpected orders schema info: %+v", info)
+	}
+	if info := topics[1]; info.Type != models.SchemaTypeAvro || info.Compatibility != models.CompatibilityBackward {
+		t.Errorf("Unexpected users schema info: %+v", info)
+	}
+
+	if err := reloaded.Delete("users"); err != nil {
+		t.Fatalf("Failed to delete schema: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "users")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected schema dir to be removed, got %v", err)
+	}
+
+	if err := reloaded.Delete("users"); !errors.Is(err, ErrSchemaNotFound) {
+		t.Errorf("Expected ErrSchemaNotFound, got %v", err)
+	}
+}
+
+func TestStoreVersions(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	store := NewStore("", models.CompatibilityBackward, logger)
+
+	v1 := `{"type": "object", "properties": {"id": {"type": "string"}}, "required": ["id"]}`
+	v2 := `{"type": "object", "properties": {"id": {"type": "string"}, "note": {"type": "string"}}, "required": ["id"]}`
+	breaking := `{"type": "object", "properties": {"id": {"type": "integer"}}, "required": ["id"]}`
+
+	if version, err := store.Register("orders", models.SchemaTypeJSON, []byte(v1)); err != nil || version.Version != 1 {
+		t.Fatalf("Expected version 1, got %v, %v", version, err)
+	}
+	if version, err := store.Register("orders", models.SchemaTypeJSON, []byte(v2)); err != nil || version.Version != 2 {
+		t.Fatalf("Expected version 2, got %v, %v", version, err)
+	}
+
+	// Повторная регистрация последней версии не создает новую
+	if version, err := store.Register("orders", models.SchemaTypeJSON, []byte(v2)); err != nil || version.Version != 2 {
+		t.Errorf("Expected existing version 2, got %v, %v", version, err)
+	}
+
+	_, err := store.Register("orders", models.SchemaTypeJSON, []byte(breaking))
+	var compatibilityErr *CompatibilityError
+	if !errors.As(err, &compatibilityErr) || compatibilityErr.Compatibility != models.CompatibilityBackward {
+		t.Fatalf("Expected BACKWARD CompatibilityError, got %v", err)
+	}
+
+	if _, err := store.Register("orders", models.SchemaTypeAvro, []byte(`"string"`)); !errors.As(err, &compatibilityErr) {
+		t.Errorf("Expected schema type change to be rejected, got %v", err)
+	}
+
+	// Закрепленная версия используется для проверки сообщений
+	note := map[string]interface{}{"id": "o-1", "note": float64(1)}
+	if err := store.Validate("orders", note); err == nil {
+		t.Errorf("Expected latest version to validate note field")
+	}
+
+	pinned := 1
+	if _, err := store.Configure("orders", "", &pinned); err != nil {
+		t.Fatalf("Failed to pin version: %v", err)
+	}
+	if err := store.Validate("orders", note); err != nil {
+		t.Errorf("Expected pinned version 1 to accept value, got %v", err)
+	}
+
+	missing := 5
+	if _, err := store.Configure("orders", "", &missing); !errors.Is(err, ErrSchemaVersionNotFound) {
+		t.Errorf("Expected ErrSchemaVersionNotFound, got %v", err)
+	}
+
+	// В режиме NONE проверка совместимости отключена
+	if _, err := store.Configure("orders", models.CompatibilityNone, nil); err != nil {
+		t.Fatalf("Failed to change compatibility: %v", err)
+	}
+	if version, err := store.Register("orders", models.SchemaTypeJSON, []byte(breaking)); err != nil || version.Version != 3 {
+		t.Errorf("Expected version 3, got %v, %v", version, err)
+	}
+
+	diff, err := store.Diff("orders", 1, 2)
+	if err != nil {
+		t.Fatalf("Failed to diff versions: %v", err)
+	}
+	if len(diff.Changes) != 1 || diff.Changes[0].Path != "/properties/note" || diff.Changes[0].Change != "added" {
+		t.Errorf("Unexpected diff: %+v", diff.Changes)
+	}
+
+	if _, err := store.Diff("orders", 1, 7); !errors.Is(err, ErrSchemaVersionNotFound) {
+		t.Errorf("Expected ErrSchemaVersionNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Экспортеры спанов
+const (
+	// ExporterNone спаны не экспортируются, traceparent входящих запросов все равно передается в записи
+	ExporterNone = "none"
+	// ExporterOTLP OTLP/HTTP; адрес задается стандартными переменными OTEL_EXPORTER_OTLP_ENDPOINT
+	// и OTEL_EXPORTER_OTLP_TRACES_ENDPOINT
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	// ExporterFile спаны в JSON по одному на строку в файл Options.File
+	ExporterFile = "file"
+)
+
+// Options настройки трассировки
+type Options struct {
+	Exporter    string
+	File        string
+	ServiceName string
+	// SampleRatio доля трассировок, начатых шлюзом; решение вызывающей стороны из traceparent соблюдается
+	SampleRatio float64
+}
+
+// ShutdownFunc отправляет накопленные спаны и останавливает экспортер
+type ShutdownFunc func(ctx context.Context) error
+
+// Setup настраивает глобальные TracerProvider и пропагатор W3C Trace Context
+func Setup(ctx context.Context, opts Options, logger *zap.Logger) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if opts.Exporter == "" || opts.Exporter == ExporterNone {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, closer, err := newExporter(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(opts.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	logger.Info("Tracing enabled",
+		zap.String("exporter", opts.Exporter),
+		zap.String("service", opts.ServiceName),
+		zap.Float64("sample_ratio", opts.SampleRatio))
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			if closeErr := closer.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		return err
+	}, nil
+}
+
+func newExporter(ctx context.Context, opts Options) (sdktrace.SpanExporter, io.Closer, error) {
+	switch opts.Exporter {
+	case ExporterOTLP:
+		exporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("create OTLP exporter: %w", err)
+		}
+		return exporter, nil, nil
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		return exporter, nil, err
+	case ExporterFile:
+		if opts.File == "" {
+			return nil, nil, fmt.Errorf("file exporter requires a file path")
+		}
+		file, err := os.OpenFile(opts.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+		return exporter, file, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown tracing exporter %q", opts.Exporter)
+	}
+}
+
+// Tracer возвращает трассировщик компонента из глобального TracerProvider
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer("kafkaGateway/" + name)
+}
+
+// Inject добавляет контекст трассировки (traceparent, tracestate) в заголовки записи.
+// Возвращает новую map; без активного спана заголовки не изменяются
+func Inject(ctx context.Context, headers map[string]string) map[string]string {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return headers
+	}
+
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return headers
+	}
+
+	injected := make(map[string]string, len(headers)+len(carrier))
+	for name, value := range headers {
+		injected[name] = value
+	}
+	for name, value := range carrier {
+		injected[name] = value
+	}
+	return injected
+}
+
+// Extract возвращает контекст с трассировкой из заголовков записи
+func Extract(ctx context.Context, headers map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(headers))
+}
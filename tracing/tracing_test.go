@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+)
+
+func TestInjectExtract(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Options{Exporter: ExporterNone}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	defer shutdown(context.Background())
+
+	headers := map[string]string{"source": "php"}
+	if injected := Inject(context.Background(), headers); len(injected) != 1 {
+		t.Errorf("Expected headers without span to be unchanged, got %v", injected)
+	}
+
+	parent := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	ctx := Extract(context.Background(), map[string]string{"traceparent": parent})
+
+	injected := Inject(ctx, headers)
+	if injected["traceparent"] != parent {
+		t.Errorf("Expected traceparent %q, got %q", parent, injected["traceparent"])
+	}
+	if injected["source"] != "php" {
+		t.Errorf("Expected existing headers to be kept, got %v", injected)
+	}
+	if _, ok := headers["traceparent"]; ok {
+		t.Errorf("Expected original headers to be unchanged")
+	}
+}
+
+func TestSetupFileExporter(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	file := filepath.Join(t.TempDir(), "spans.json")
+	shutdown, err := Setup(context.Background(), Options{
+		Exporter:    ExporterFile,
+		File:        file,
+		ServiceName: "gateway-test",
+		SampleRatio: 1,
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	_, span := Tracer("test").Start(context.Background(), "orders publish")
+	span.End()
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	data, err := os.Open(file)
+	if err != nil {
+		t.Fatalf("Failed to open span file: %v", err)
+	}
+	defer data.Close()
+
+	var spans []map[string]interface{}
+	decoder := json.NewDecoder(bufio.NewReader(data))
+	for decoder.More() {
+		var span map[string]interface{}
+		if err := decoder.Decode(&span); err != nil {
+			t.Fatalf("Failed to decode span: %v", err)
+		}
+		spans = append(spans, span)
+	}
+	if len(spans) != 1 || spans[0]["Name"] != "orders publish" {
+		t.Fatalf("Expected one exported span, got %v", spans)
+	}
+
+	if _, err := Setup(context.Background(), Options{Exporter: "jaeger"}, zap.NewNop()); err == nil || !strings.Contains(err.Error(), "unknown") {
+		t.Errorf("Expected error for unknown exporter, got %v", err)
+	}
+}
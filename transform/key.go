@@ -0,0 +1,174 @@
+package transform
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"kafkaGateway/config"
+)
+
+// ErrKeyNotFound в значении нет поля для ключа сообщения
+var ErrKeyNotFound = errors.New("message key not found in value")
+
+// keySource получает ключ из значения сообщения
+type keySource struct {
+	path     []pathSegment
+	template *template.Template
+	required bool
+}
+
+// pathSegment элемент пути: имя поля объекта или индекс массива
+type pathSegment struct {
+	field string
+	index int
+}
+
+// KeyExtractors источники ключей по топикам
+type KeyExtractors struct {
+	sources map[string]keySource
+}
+
+// NewKeyExtractors собирает источники ключей для топиков, у которых в конфигурации задан key
+func NewKeyExtractors(topics map[string]config.TopicConfig) (*KeyExtractors, error) {
+	e := &KeyExtractors{sources: make(map[string]keySource)}
+	for topic, topicConfig := range topics {
+		if topicConfig.Key == nil {
+			continue
+		}
+		source, err := newKeySource(*topicConfig.Key)
+		if err != nil {
+			return nil, fmt.Errorf("topic %q: key: %w", topic, err)
+		}
+		e.sources[topic] = source
+	}
+	return e, nil
+}
+
+func newKeySource(cfg config.KeyConfig) (keySource, error) {
+	source := keySource{required: cfg.Required}
+	if cfg.Template != "" {
+		tmpl, err := template.New("key").Option("missingkey=error").Parse(cfg.Template)
+		if err != nil {
+			return source, err
+		}
+		source.template = tmpl
+		return source, nil
+	}
+
+	path, err := parseJSONPath(cfg.Path)
+	if err != nil {
+		return source, err
+	}
+	source.path = path
+	return source, nil
+}
+
+// ExtractKey возвращает ключ для значения сообщения топика. found = false, если для топика
+// источник не задан или необязательное поле отсутствует; ErrKeyNotFound - если обязательное поле отсутствует
+func (e *KeyExtractors) ExtractKey(topic string, value interface{}) ([]byte, bool, error) {
+	source, ok := e.sources[topic]
+	if !ok {
+		return nil, false, nil
+	}
+
+	key, err := source.extract(value)
+	if err != nil {
+		if source.required {
+			return nil, false, err
+		}
+		return nil, false, nil
+	}
+	return key, true, nil
+}
+
+func (s keySource) extract(value interface{}) ([]byte, error) {
+	if s.template != nil {
+		var buf bytes.Buffer
+		if err := s.template.Execute(&buf, value); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrKeyNotFound, err)
+		}
+		if buf.Len() == 0 {
+			return nil, ErrKeyNotFound
+		}
+		return buf.Bytes(), nil
+	}
+
+	field, ok := lookupJSONPath(value, s.path)
+	if !ok || field == nil {
+		return nil, ErrKeyNotFound
+	}
+	key, err := castString(field)
+	if err != nil {
+		return nil, err
+	}
+	if key == "" {
+		return nil, ErrKeyNotFound
+	}
+	return []byte(key.(string)), nil
+}
+
+// parseJSONPath разбирает упрощенный JSONPath: $, поля через точку и индексы массивов ($.items[0].sku)
+func parseJSONPath(path string) ([]pathSegment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("path %q must start with $", path)
+	}
+
+	var segments []pathSegment
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			field := rest[1 : end+1]
+			if field == "" {
+				return nil, fmt.Errorf("path %q has empty field name", path)
+			}
+			segments = append(segments, pathSegment{field: field})
+			rest = rest[end+1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("path %q has unclosed [", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("path %q has invalid index %q", path, rest[1:end])
+			}
+			segments = append(segments, pathSegment{index: index})
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("path %q is invalid", path)
+		}
+	}
+	return segments, nil
+}
+
+func lookupJSONPath(value interface{}, path []pathSegment) (interface{}, bool) {
+	current := value
+	for _, segment := range path {
+		if segment.field != "" {
+			obj, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if current, ok = obj[segment.field]; !ok {
+				return nil, false
+			}
+			continue
+		}
+
+		items, ok := current.([]interface{})
+		if !ok || segment.index >= len(items) {
+			return nil, false
+		}
+		current = items[segment.index]
+	}
+	return current, true
+}
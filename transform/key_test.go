@@ -0,0 +1,76 @@
+package transform
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"kafkaGateway/config"
+)
+
+func TestKeyExtractors(t *testing.T) {
+	extractors, err := NewKeyExtractors(map[string]config.TopicConfig{
+		"users":    {Key: &config.KeyConfig{Path: "$.user.id"}},
+		"orders":   {Key: &config.KeyConfig{Path: "$.items[1].sku", Required: true}},
+		"payments": {Key: &config.KeyConfig{Template: "{{.tenant}}:{{.user.id}}", Required: true}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		topic     string
+		value     string
+		wantKey   string
+		wantFound bool
+		wantErr   bool
+	}{
+		{name: "numeric field", topic: "users", value: `{"user":{"id":42}}`, wantKey: "42", wantFound: true},
+		{name: "string field", topic: "users", value: `{"user":{"id":"u-1"}}`, wantKey: "u-1", wantFound: true},
+		{name: "optional missing", topic: "users", value: `{"user":{}}`},
+		{name: "null field", topic: "users", value: `{"user":{"id":null}}`},
+		{name: "array index", topic: "orders", value: `{"items":[{"sku":"a"},{"sku":"b"}]}`, wantKey: "b", wantFound: true},
+		{name: "required missing", topic: "orders", value: `{"items":[{"sku":"a"}]}`, wantErr: true},
+		{name: "template", topic: "payments", value: `{"tenant":"acme","user":{"id":"7"}}`, wantKey: "acme:7", wantFound: true},
+		{name: "template missing field", topic: "payments", value: `{"user":{"id":"7"}}`, wantErr: true},
+		{name: "topic without key config", topic: "logs", value: `{"id":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value interface{}
+			json.Unmarshal([]byte(tt.value), &value)
+
+			key, found, err := extractors.ExtractKey(tt.topic, value)
+			if tt.wantErr {
+				if !errors.Is(err, ErrKeyNotFound) {
+					t.Errorf("Expected ErrKeyNotFound, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if found != tt.wantFound || string(key) != tt.wantKey {
+				t.Errorf("Expected key %q (found=%v), got %q (found=%v)", tt.wantKey, tt.wantFound, key, found)
+			}
+		})
+	}
+}
+
+func TestParseJSONPath(t *testing.T) {
+	valid := []string{"$", "$.user.id", "$.items[0].sku", "$[2]"}
+	for _, path := range valid {
+		if _, err := parseJSONPath(path); err != nil {
+			t.Errorf("Unexpected error for path %q: %v", path, err)
+		}
+	}
+
+	invalid := []string{"user.id", "$..id", "$.items[", "$.items[-1]", "$.items[x]", "$user"}
+	for _, path := range invalid {
+		if _, err := parseJSONPath(path); err == nil {
+			t.Errorf("Expected error for path %q", path)
+		}
+	}
+}
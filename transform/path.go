@@ -0,0 +1,69 @@
+package transform
+
+import "strings"
+
+// Поля задаются путем через точку: customer.address.city
+
+func getPath(obj map[string]interface{}, path string) (interface{}, bool) {
+	parts := strings.Split(path, ".")
+	current := obj
+	for i, part := range parts {
+		value, ok := current[part]
+		if !ok {
+			return nil, false
+		}
+		if i == len(parts)-1 {
+			return value, true
+		}
+		if current, ok = value.(map[string]interface{}); !ok {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// setPath задает значение поля, создавая недостающие промежуточные объекты.
+// Промежуточное поле, не являющееся объектом, заменяется объектом
+func setPath(obj map[string]interface{}, path string, value interface{}) {
+	parts := strings.Split(path, ".")
+	current := obj
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[part] = next
+		}
+		current = next
+	}
+	current[parts[len(parts)-1]] = value
+}
+
+func deletePath(obj map[string]interface{}, path string) (interface{}, bool) {
+	parts := strings.Split(path, ".")
+	current := obj
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	last := parts[len(parts)-1]
+	value, ok := current[last]
+	delete(current, last)
+	return value, ok
+}
+
+// parentPath возвращает объект, содержащий поле, и имя поля в нем
+func parentPath(obj map[string]interface{}, path string) (map[string]interface{}, string, bool) {
+	idx := strings.LastIndex(path, ".")
+	if idx < 0 {
+		return obj, path, true
+	}
+	parent, ok := getPath(obj, path[:idx])
+	if !ok {
+		return nil, "", false
+	}
+	parentObj, ok := parent.(map[string]interface{})
+	return parentObj, path[idx+1:], ok
+}
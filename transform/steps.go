@@ -0,0 +1,384 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"text/template"
+
+	"kafkaGateway/config"
+)
+
+func newStep(step config.TransformStep) (Transformer, error) {
+	switch {
+	case step.Add != nil:
+		return setFields{values: step.Add, overwrite: true}, nil
+	case step.Defaults != nil:
+		return setFields{values: step.Defaults}, nil
+	case step.Drop != nil:
+		return dropFields(step.Drop), nil
+	case step.Rename != nil:
+		return renameFields(step.Rename), nil
+	case step.Cast != nil:
+		return castFields(step.Cast), nil
+	case step.Flatten != nil:
+		separator := step.Flatten.Separator
+		if separator == "" {
+			separator = "."
+		}
+		return flattenField{field: step.Flatten.Field, separator: separator}, nil
+	case step.Template != nil:
+		return newTemplateFields(step.Template)
+	case step.Custom != "":
+		factory, ok := lookup(step.Custom)
+		if !ok {
+			return nil, fmt.Errorf("custom step %q is not registered", step.Custom)
+		}
+		return factory(step.Options)
+	default:
+		return nil, fmt.Errorf("step has no action")
+	}
+}
+
+func objectValue(msg *Message) (map[string]interface{}, error) {
+	obj, ok := msg.Value.(map[string]interface{})
+	if !ok {
+		return nil, ErrNotObject
+	}
+	return obj, nil
+}
+
+// sortedKeys задает порядок применения полей, чтобы пересекающиеся пути обрабатывались одинаково
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// setFields шаги add (overwrite) и defaults
+type setFields struct {
+	values    map[string]interface{}
+	overwrite bool
+}
+
+func (s setFields) Transform(msg *Message) error {
+	obj, err := objectValue(msg)
+	if err != nil {
+		return err
+	}
+	for _, path := range sortedKeys(s.values) {
+		if !s.overwrite {
+			if current, exists := getPath(obj, path); exists && current != nil {
+				continue
+			}
+		}
+		setPath(obj, path, deepCopy(s.values[path]))
+	}
+	return nil
+}
+
+type dropFields []string
+
+func (d dropFields) Transform(msg *Message) error {
+	obj, err := objectValue(msg)
+	if err != nil {
+		return err
+	}
+	for _, path := range d {
+		deletePath(obj, path)
+	}
+	return nil
+}
+
+type renameFields map[string]string
+
+func (r renameFields) Transform(msg *Message) error {
+	obj, err := objectValue(msg)
+	if err != nil {
+		return err
+	}
+
+	// Сначала извлекаем все поля, затем записываем, чтобы переименования не мешали друг другу (a->b, b->a)
+	moved := make(map[string]interface{}, len(r))
+	for _, from := range sortedKeys(r) {
+		if value, ok := deletePath(obj, from); ok {
+			moved[r[from]] = value
+		}
+	}
+	for _, to := range sortedKeys(moved) {
+		setPath(obj, to, moved[to])
+	}
+	return nil
+}
+
+type castFields map[string]string
+
+func (c castFields) Transform(msg *Message) error {
+	obj, err := objectValue(msg)
+	if err != nil {
+		return err
+	}
+	for _, path := range sortedKeys(c) {
+		value, ok := getPath(obj, path)
+		if !ok || value == nil {
+			continue
+		}
+		casted, err := castValue(value, c[path])
+		if err != nil {
+			return fmt.Errorf("cast %s: %w", path, err)
+		}
+		setPath(obj, path, casted)
+	}
+	return nil
+}
+
+func castValue(value interface{}, castType string) (interface{}, error) {
+	switch castType {
+	case config.CSVTypeString:
+		return castString(value)
+	case config.CSVTypeInt:
+		return castInt(value)
+	case config.CSVTypeFloat:
+		return castFloat(value)
+	case config.CSVTypeBool:
+		return castBool(value)
+	default:
+		return nil, fmt.Errorf("unknown type %q", castType)
+	}
+}
+
+func castString(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case json.Number:
+		return v.String(), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v), nil
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return string(encoded), nil
+	}
+}
+
+func castInt(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n, nil
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", v)
+		}
+		return floatToInt(f)
+	case bool:
+		if v {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return floatToInt(f)
+	case float64:
+		return floatToInt(v)
+	case float32:
+		return floatToInt(float64(v))
+	case int:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint:
+		return uintToInt(uint64(v))
+	case uint8:
+		return int64(v), nil
+	case uint16:
+		return int64(v), nil
+	case uint32:
+		return int64(v), nil
+	case uint64:
+		return uintToInt(v)
+	default:
+		return nil, fmt.Errorf("cannot cast %T to int", value)
+	}
+}
+
+func floatToInt(f float64) (interface{}, error) {
+	if f != math.Trunc(f) || f > math.MaxInt64 || f < math.MinInt64 {
+		return nil, fmt.Errorf("%v is not an integer", f)
+	}
+	return int64(f), nil
+}
+
+func uintToInt(u uint64) (interface{}, error) {
+	if u > math.MaxInt64 {
+		return nil, fmt.Errorf("%d overflows int64", u)
+	}
+	return int64(u), nil
+}
+
+func castFloat(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", v)
+		}
+		return f, nil
+	case bool:
+		if v {
+			return float64(1), nil
+		}
+		return float64(0), nil
+	case json.Number:
+		return v.Float64()
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return strconv.ParseFloat(fmt.Sprint(v), 64)
+	default:
+		return nil, fmt.Errorf("cannot cast %T to float", value)
+	}
+}
+
+func castBool(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", v)
+		}
+		return b, nil
+	default:
+		n, err := castFloat(value)
+		if err != nil {
+			return nil, fmt.Errorf("cannot cast %T to bool", value)
+		}
+		switch n.(float64) {
+		case 0:
+			return false, nil
+		case 1:
+			return true, nil
+		default:
+			return nil, fmt.Errorf("%v is not a boolean", value)
+		}
+	}
+}
+
+// flattenField разворачивает вложенные объекты поля field (или всего значения) в объект,
+// содержащий поле: {"address": {"city": "Riga"}} -> {"address.city": "Riga"}
+type flattenField struct {
+	field     string
+	separator string
+}
+
+func (f flattenField) Transform(msg *Message) error {
+	obj, err := objectValue(msg)
+	if err != nil {
+		return err
+	}
+
+	if f.field == "" {
+		flat := make(map[string]interface{}, len(obj))
+		f.flatten(flat, "", obj)
+		msg.Value = flat
+		return nil
+	}
+
+	parent, name, ok := parentPath(obj, f.field)
+	if !ok {
+		return nil
+	}
+	nested, ok := parent[name].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	delete(parent, name)
+	f.flatten(parent, name, nested)
+	return nil
+}
+
+func (f flattenField) flatten(dst map[string]interface{}, prefix string, obj map[string]interface{}) {
+	for key, value := range obj {
+		name := key
+		if prefix != "" {
+			name = prefix + f.separator + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			f.flatten(dst, name, nested)
+			continue
+		}
+		dst[name] = value
+	}
+}
+
+// templateFields задает поля строками по шаблонам text/template.
+// Все шаблоны шага вычисляются по значению до шага; отсутствующее поле в шаблоне - ошибка
+type templateFields struct {
+	paths     []string
+	templates map[string]*template.Template
+}
+
+func newTemplateFields(fields map[string]string) (Transformer, error) {
+	t := templateFields{templates: make(map[string]*template.Template, len(fields))}
+	for _, path := range sortedKeys(fields) {
+		tmpl, err := template.New(path).Option("missingkey=error").Parse(fields[path])
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %w", path, err)
+		}
+		t.paths = append(t.paths, path)
+		t.templates[path] = tmpl
+	}
+	return t, nil
+}
+
+func (t templateFields) Transform(msg *Message) error {
+	obj, err := objectValue(msg)
+	if err != nil {
+		return err
+	}
+
+	rendered := make([]string, len(t.paths))
+	for i, path := range t.paths {
+		var buf bytes.Buffer
+		if err := t.templates[path].Execute(&buf, obj); err != nil {
+			return fmt.Errorf("template %s: %w", path, err)
+		}
+		rendered[i] = buf.String()
+	}
+	for i, path := range t.paths {
+		setPath(obj, path, rendered[i])
+	}
+	return nil
+}
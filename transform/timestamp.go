@@ -0,0 +1,92 @@
+package transform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"kafkaGateway/config"
+)
+
+// ErrInvalidTimestamp значение нельзя использовать как время записи
+var ErrInvalidTimestamp = errors.New("invalid timestamp")
+
+// ParseTimestamp разбирает время записи: строку RFC3339 или число миллисекунд Unix epoch
+// (в том числе строкой)
+func ParseTimestamp(value interface{}) (time.Time, error) {
+	var millis float64
+	switch v := value.(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t, nil
+		}
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: %q is neither RFC3339 nor epoch milliseconds", ErrInvalidTimestamp, v)
+		}
+		millis = n
+	case json.Number:
+		n, err := v.Float64()
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: %v", ErrInvalidTimestamp, err)
+		}
+		millis = n
+	case bool:
+		return time.Time{}, fmt.Errorf("%w: unsupported type %T", ErrInvalidTimestamp, value)
+	default:
+		n, err := castFloat(value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: unsupported type %T", ErrInvalidTimestamp, value)
+		}
+		millis = n.(float64)
+	}
+
+	if millis < 0 || millis > float64(math.MaxInt64/int64(time.Millisecond)) || math.IsNaN(millis) {
+		return time.Time{}, fmt.Errorf("%w: %v is out of range", ErrInvalidTimestamp, millis)
+	}
+	return time.UnixMilli(int64(millis)), nil
+}
+
+// TimestampExtractors пути к времени записи в значении по топикам
+type TimestampExtractors struct {
+	paths map[string][]pathSegment
+}
+
+// NewTimestampExtractors собирает пути для топиков, у которых в конфигурации задан timestamp_path
+func NewTimestampExtractors(topics map[string]config.TopicConfig) (*TimestampExtractors, error) {
+	e := &TimestampExtractors{paths: make(map[string][]pathSegment)}
+	for topic, topicConfig := range topics {
+		if topicConfig.TimestampPath == "" {
+			continue
+		}
+		path, err := parseJSONPath(topicConfig.TimestampPath)
+		if err != nil {
+			return nil, fmt.Errorf("topic %q: timestamp_path: %w", topic, err)
+		}
+		e.paths[topic] = path
+	}
+	return e, nil
+}
+
+// ExtractTimestamp возвращает время записи из значения сообщения топика. found = false,
+// если путь для топика не задан или поле отсутствует
+func (e *TimestampExtractors) ExtractTimestamp(topic string, value interface{}) (time.Time, bool, error) {
+	path, ok := e.paths[topic]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+
+	field, ok := lookupJSONPath(value, path)
+	if !ok || field == nil {
+		return time.Time{}, false, nil
+	}
+
+	t, err := ParseTimestamp(field)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t, true, nil
+}
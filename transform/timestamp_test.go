@@ -0,0 +1,67 @@
+package transform
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"kafkaGateway/config"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	expected := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	valid := []interface{}{
+		"2021-03-04T05:06:07Z",
+		"2021-03-04T08:06:07+03:00",
+		float64(expected.UnixMilli()),
+		expected.UnixMilli(),
+		uint64(expected.UnixMilli()),
+		json.Number("1614834367000"),
+		"1614834367000",
+	}
+	for _, value := range valid {
+		parsed, err := ParseTimestamp(value)
+		if err != nil {
+			t.Errorf("Unexpected error for %v (%T): %v", value, value, err)
+			continue
+		}
+		if !parsed.Equal(expected) {
+			t.Errorf("Expected %v for %v (%T), got %v", expected, value, value, parsed)
+		}
+	}
+
+	invalid := []interface{}{"yesterday", "2021-03-04", float64(-1), true, map[string]interface{}{}, 1e300}
+	for _, value := range invalid {
+		if _, err := ParseTimestamp(value); !errors.Is(err, ErrInvalidTimestamp) {
+			t.Errorf("Expected ErrInvalidTimestamp for %v (%T), got %v", value, value, err)
+		}
+	}
+}
+
+func TestTimestampExtractors(t *testing.T) {
+	extractors, err := NewTimestampExtractors(map[string]config.TopicConfig{
+		"events": {TimestampPath: "$.meta.occurred_at"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var value interface{}
+	json.Unmarshal([]byte(`{"meta":{"occurred_at":"2021-03-04T05:06:07Z"}}`), &value)
+	timestamp, found, err := extractors.ExtractTimestamp("events", value)
+	if err != nil || !found || timestamp.UnixMilli() != 1614834367000 {
+		t.Errorf("Unexpected timestamp %v (found=%v, err=%v)", timestamp, found, err)
+	}
+
+	if _, found, err := extractors.ExtractTimestamp("events", map[string]interface{}{}); found || err != nil {
+		t.Errorf("Expected missing field to be skipped, got found=%v err=%v", found, err)
+	}
+	if _, _, err := extractors.ExtractTimestamp("events", map[string]interface{}{"meta": map[string]interface{}{"occurred_at": "soon"}}); err == nil {
+		t.Errorf("Expected error for invalid timestamp")
+	}
+	if _, found, _ := extractors.ExtractTimestamp("other", value); found {
+		t.Errorf("Expected topic without timestamp_path to be skipped")
+	}
+}
@@ -0,0 +1,153 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"kafkaGateway/config"
+)
+
+// ErrNotObject значение сообщения не является JSON объектом, а шаг работает с полями
+var ErrNotObject = errors.New("value is not a JSON object")
+
+// Message сообщение, которое преобразуют шаги. Шаг может изменить ключ, заголовки и значение
+type Message struct {
+	Topic   string
+	Key     []byte
+	Headers map[string]string
+	Value   interface{}
+}
+
+// Transformer шаг преобразования сообщения. Собственные шаги регистрируются через Register
+// и подключаются в конфигурации топика (custom: имя)
+type Transformer interface {
+	Transform(msg *Message) error
+}
+
+// TransformerFunc позволяет использовать функцию как Transformer
+type TransformerFunc func(msg *Message) error
+
+func (f TransformerFunc) Transform(msg *Message) error {
+	return f(msg)
+}
+
+// Factory создает собственный шаг по параметрам options из конфигурации
+type Factory func(options map[string]interface{}) (Transformer, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register регистрирует собственный шаг под именем name. Вызывается до загрузки конфигурации,
+// обычно в init(). Повторная регистрация имени приводит к панике
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if factory == nil {
+		panic("transform: Register factory is nil for " + name)
+	}
+	if _, exists := registry[name]; exists {
+		panic("transform: Register called twice for " + name)
+	}
+	registry[name] = factory
+}
+
+func lookup(name string) (Factory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	factory, ok := registry[name]
+	return factory, ok
+}
+
+// Pipeline цепочка шагов, применяемых по порядку
+type Pipeline []Transformer
+
+// Transform применяет шаги к копии значения и заголовков, исходное сообщение запроса не изменяется.
+// Это важно при отправке одного сообщения в несколько топиков с разными цепочками
+func (p Pipeline) Transform(msg *Message) error {
+	if len(p) == 0 {
+		return nil
+	}
+
+	msg.Value = deepCopy(msg.Value)
+	if msg.Headers != nil {
+		headers := make(map[string]string, len(msg.Headers))
+		for name, value := range msg.Headers {
+			headers[name] = value
+		}
+		msg.Headers = headers
+	}
+
+	for i, step := range p {
+		if err := step.Transform(msg); err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// NewPipeline собирает цепочку из шагов конфигурации
+func NewPipeline(steps []config.TransformStep) (Pipeline, error) {
+	pipeline := make(Pipeline, 0, len(steps))
+	for i, step := range steps {
+		transformer, err := newStep(step)
+		if err != nil {
+			return nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+		pipeline = append(pipeline, transformer)
+	}
+	return pipeline, nil
+}
+
+// Pipelines цепочки преобразований по топикам
+type Pipelines struct {
+	pipelines map[string]Pipeline
+}
+
+// NewPipelines собирает цепочки для топиков, у которых в конфигурации заданы шаги transform
+func NewPipelines(topics map[string]config.TopicConfig) (*Pipelines, error) {
+	p := &Pipelines{pipelines: make(map[string]Pipeline)}
+	for topic, topicConfig := range topics {
+		if len(topicConfig.Transform) == 0 {
+			continue
+		}
+		pipeline, err := NewPipeline(topicConfig.Transform)
+		if err != nil {
+			return nil, fmt.Errorf("topic %q: %w", topic, err)
+		}
+		p.pipelines[topic] = pipeline
+	}
+	return p, nil
+}
+
+// Set задает цепочку топика из кода, например для шагов без конфигурации
+func (p *Pipelines) Set(topic string, pipeline Pipeline) {
+	p.pipelines[topic] = pipeline
+}
+
+// Transform применяет цепочку топика msg.Topic. Для топиков без цепочки сообщение не изменяется
+func (p *Pipelines) Transform(msg *Message) error {
+	return p.pipelines[msg.Topic].Transform(msg)
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = deepCopy(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = deepCopy(item)
+		}
+		return copied
+	default:
+		return v
+	}
+}
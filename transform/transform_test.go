@@ -0,0 +1,186 @@
+package transform
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"kafkaGateway/config"
+)
+
+func TestPipelineSteps(t *testing.T) {
+	tests := []struct {
+		name     string
+		steps    []config.TransformStep
+		value    string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "add overwrites",
+			steps:    []config.TransformStep{{Add: map[string]interface{}{"source": "gateway", "meta.version": 2}}},
+			value:    `{"source":"client"}`,
+			expected: `{"meta":{"version":2},"source":"gateway"}`,
+		},
+		{
+			name:     "defaults keep existing",
+			steps:    []config.TransformStep{{Defaults: map[string]interface{}{"currency": "USD", "status": "new"}}},
+			value:    `{"currency":"EUR"}`,
+			expected: `{"currency":"EUR","status":"new"}`,
+		},
+		{
+			name:     "drop nested",
+			steps:    []config.TransformStep{{Drop: []string{"debug", "user.password", "missing.field"}}},
+			value:    `{"debug":true,"user":{"id":1,"password":"secret"}}`,
+			expected: `{"user":{"id":1}}`,
+		},
+		{
+			name:     "rename swaps",
+			steps:    []config.TransformStep{{Rename: map[string]string{"a": "b", "b": "a", "userId": "user.id"}}},
+			value:    `{"a":1,"b":2,"userId":7}`,
+			expected: `{"a":2,"b":1,"user":{"id":7}}`,
+		},
+		{
+			name:     "cast",
+			steps:    []config.TransformStep{{Cast: map[string]string{"amount": "float", "qty": "int", "active": "bool", "id": "string", "none": "int"}}},
+			value:    `{"amount":"10.5","qty":"3","active":"true","id":42,"none":null}`,
+			expected: `{"active":true,"amount":10.5,"id":"42","none":null,"qty":3}`,
+		},
+		{
+			name:    "cast invalid number",
+			steps:   []config.TransformStep{{Cast: map[string]string{"qty": "int"}}},
+			value:   `{"qty":"three"}`,
+			wantErr: true,
+		},
+		{
+			name:     "flatten field",
+			steps:    []config.TransformStep{{Flatten: &config.FlattenStep{Field: "address", Separator: "_"}}},
+			value:    `{"id":1,"address":{"city":"Riga","geo":{"lat":56.9}}}`,
+			expected: `{"address_city":"Riga","address_geo_lat":56.9,"id":1}`,
+		},
+		{
+			name:     "flatten value",
+			steps:    []config.TransformStep{{Flatten: &config.FlattenStep{}}},
+			value:    `{"id":1,"user":{"name":"Ann","tags":["a"]}}`,
+			expected: `{"id":1,"user.name":"Ann","user.tags":["a"]}`,
+		},
+		{
+			name: "template after rename",
+			steps: []config.TransformStep{
+				{Rename: map[string]string{"first": "first_name"}},
+				{Template: map[string]string{"full_name": "{{.first_name}} {{.user.last}}"}},
+			},
+			value:    `{"first":"Ann","user":{"last":"Lee"}}`,
+			expected: `{"first_name":"Ann","full_name":"Ann Lee","user":{"last":"Lee"}}`,
+		},
+		{
+			name:    "template missing field",
+			steps:   []config.TransformStep{{Template: map[string]string{"full_name": "{{.first_name}}"}}},
+			value:   `{}`,
+			wantErr: true,
+		},
+		{
+			name:    "value is not an object",
+			steps:   []config.TransformStep{{Drop: []string{"a"}}},
+			value:   `[1,2]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline, err := NewPipeline(tt.steps)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var value interface{}
+			json.Unmarshal([]byte(tt.value), &value)
+			msg := &Message{Topic: "orders", Value: value}
+
+			err = pipeline.Transform(msg)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got value %v", msg.Value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			result, _ := json.Marshal(msg.Value)
+			if string(result) != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPipelineDoesNotModifyInput(t *testing.T) {
+	pipeline, err := NewPipeline([]config.TransformStep{{Drop: []string{"user.password"}}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	value := map[string]interface{}{"user": map[string]interface{}{"password": "secret"}}
+	headers := map[string]string{"x-source": "api"}
+	msg := &Message{Value: value, Headers: headers}
+	if err := pipeline.Transform(msg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, ok := value["user"].(map[string]interface{})["password"]; !ok {
+		t.Errorf("Expected original value to be unchanged")
+	}
+	msg.Headers["x-source"] = "changed"
+	if headers["x-source"] != "api" {
+		t.Errorf("Expected original headers to be unchanged")
+	}
+}
+
+func TestCustomTransformer(t *testing.T) {
+	Register("test-uppercase-key", func(options map[string]interface{}) (Transformer, error) {
+		header, _ := options["header"].(string)
+		if header == "" {
+			return nil, errors.New("header option is required")
+		}
+		return TransformerFunc(func(msg *Message) error {
+			msg.Key = []byte(strings.ToUpper(string(msg.Key)))
+			msg.Headers[header] = "true"
+			return nil
+		}), nil
+	})
+
+	pipelines, err := NewPipelines(map[string]config.TopicConfig{
+		"orders": {Transform: []config.TransformStep{{Custom: "test-uppercase-key", Options: map[string]interface{}{"header": "x-transformed"}}}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	msg := &Message{Topic: "orders", Key: []byte("abc"), Headers: map[string]string{}}
+	if err := pipelines.Transform(msg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(msg.Key) != "ABC" || msg.Headers["x-transformed"] != "true" {
+		t.Errorf("Unexpected message: key=%s headers=%v", msg.Key, msg.Headers)
+	}
+
+	untouched := &Message{Topic: "payments", Key: []byte("abc")}
+	if err := pipelines.Transform(untouched); err != nil || string(untouched.Key) != "abc" {
+		t.Errorf("Expected topic without pipeline to be unchanged")
+	}
+
+	invalid := []config.TransformStep{
+		{Custom: "unknown-step"},
+		{Custom: "test-uppercase-key"},
+		{Template: map[string]string{"x": "{{.a"}},
+	}
+	for _, step := range invalid {
+		if _, err := NewPipeline([]config.TransformStep{step}); err == nil {
+			t.Errorf("Expected error for step %+v", step)
+		}
+	}
+}